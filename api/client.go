@@ -0,0 +1,429 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error adalah error yang dikembalikan daemon beserta kode status HTTP-nya
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Client adalah client HTTP untuk socket minidockerd
+type Client struct {
+	socket string
+	http   *http.Client
+}
+
+// SocketPath mengambil path socket dari host yang berupa path atau URL
+// unix:///path
+func SocketPath(host string) (string, error) {
+	socket := host
+	if strings.Contains(host, "://") {
+		u, err := url.Parse(host)
+		if err != nil {
+			return "", fmt.Errorf("host daemon tidak valid: %v", err)
+		}
+		if u.Scheme != "unix" {
+			return "", fmt.Errorf("host daemon %q tidak didukung, hanya unix:// yang didukung", host)
+		}
+		socket = u.Path
+	}
+	if socket == "" {
+		return "", fmt.Errorf("path socket daemon kosong")
+	}
+	return socket, nil
+}
+
+// NewClient membuat client untuk socket daemon. host boleh berupa path
+// socket atau URL unix:///path.
+func NewClient(host string) (*Client, error) {
+	socket, err := SocketPath(host)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{socket: socket}
+	c.http = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return c.dial(ctx)
+			},
+		},
+	}
+	return c, nil
+}
+
+// Socket mengembalikan path socket daemon
+func (c *Client) Socket() string {
+	return c.socket
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", c.socket)
+}
+
+// newRequest membuat request ke endpoint berversi dengan body JSON
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := "http://minidocker/v" + Version + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// do mengirim request dan mengubah response error menjadi *Error. Pemanggil
+// wajib menutup body response jika error nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal terhubung ke daemon di %s: %v", c.socket, err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
+
+// call mengirim request dan men-decode response JSON ke out jika tidak nil
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	resp, err := c.do(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("response daemon tidak valid: %v", err)
+	}
+	return nil
+}
+
+// decodeError membaca pesan error dari response daemon
+func decodeError(resp *http.Response) error {
+	var body ErrorResponse
+	data, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(data, &body); err != nil || body.Message == "" {
+		body.Message = strings.TrimSpace(string(data))
+		if body.Message == "" {
+			body.Message = resp.Status
+		}
+	}
+	return &Error{StatusCode: resp.StatusCode, Message: body.Message}
+}
+
+// Ping memeriksa apakah daemon menjawab
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, http.MethodGet, "/_ping", nil, nil, nil)
+}
+
+// ContainerCreate membuat container tanpa menjalankannya
+func (c *Client) ContainerCreate(ctx context.Context, name string, req ContainerCreateRequest) (ContainerCreateResponse, error) {
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	var resp ContainerCreateResponse
+	err := c.call(ctx, http.MethodPost, "/containers/create", query, req, &resp)
+	return resp, err
+}
+
+// ContainerStart menjalankan container yang sudah dibuat
+func (c *Client) ContainerStart(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/start", nil, nil, nil)
+}
+
+// ContainerStop menghentikan container. timeout negatif memakai default daemon.
+func (c *Client) ContainerStop(ctx context.Context, id string, timeout int) error {
+	query := url.Values{}
+	if timeout >= 0 {
+		query.Set("t", strconv.Itoa(timeout))
+	}
+	return c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/stop", query, nil, nil)
+}
+
+// ContainerRemove menghapus container
+func (c *Client) ContainerRemove(ctx context.Context, id string, force bool) error {
+	query := url.Values{}
+	if force {
+		query.Set("force", "1")
+	}
+	return c.call(ctx, http.MethodDelete, "/containers/"+url.PathEscape(id), query, nil, nil)
+}
+
+// ContainerKill mengirim sinyal ke container. signal kosong berarti SIGKILL.
+func (c *Client) ContainerKill(ctx context.Context, id, signal string) error {
+	query := url.Values{}
+	if signal != "" {
+		query.Set("signal", signal)
+	}
+	return c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/kill", query, nil, nil)
+}
+
+// ContainerPause membekukan semua proses di container
+func (c *Client) ContainerPause(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/pause", nil, nil, nil)
+}
+
+// ContainerUnpause melanjutkan container yang di-pause
+func (c *Client) ContainerUnpause(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/unpause", nil, nil, nil)
+}
+
+// ContainerUpdate mengubah resource limits container. Field resources yang
+// bernilai nol tidak diubah.
+func (c *Client) ContainerUpdate(ctx context.Context, id string, resources Resources) (ContainerUpdateResponse, error) {
+	var resp ContainerUpdateResponse
+	err := c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/update", nil, resources, &resp)
+	return resp, err
+}
+
+// ContainersPrune menghapus semua container yang sudah berhenti
+func (c *Client) ContainersPrune(ctx context.Context, filters map[string][]string) (ContainersPruneReport, error) {
+	query := url.Values{}
+	if f := EncodeFilters(filters); f != "" {
+		query.Set("filters", f)
+	}
+	var report ContainersPruneReport
+	err := c.call(ctx, http.MethodPost, "/containers/prune", query, nil, &report)
+	return report, err
+}
+
+// ContainersResume menjalankan kembali container dengan restart policy
+// always/unless-stopped yang tidak sedang berjalan
+func (c *Client) ContainersResume(ctx context.Context) (ContainersResumeReport, error) {
+	var report ContainersResumeReport
+	err := c.call(ctx, http.MethodPost, "/containers/resume", nil, nil, &report)
+	return report, err
+}
+
+// ContainerList mengembalikan daftar container
+func (c *Client) ContainerList(ctx context.Context, all bool, filters map[string][]string) ([]Container, error) {
+	query := url.Values{}
+	if all {
+		query.Set("all", "1")
+	}
+	if f := EncodeFilters(filters); f != "" {
+		query.Set("filters", f)
+	}
+	var containers []Container
+	err := c.call(ctx, http.MethodGet, "/containers/json", query, nil, &containers)
+	return containers, err
+}
+
+// ContainerInspect mengembalikan detail container
+func (c *Client) ContainerInspect(ctx context.Context, id string) (ContainerJSON, error) {
+	var info ContainerJSON
+	err := c.call(ctx, http.MethodGet, "/containers/"+url.PathEscape(id)+"/json", nil, nil, &info)
+	return info, err
+}
+
+// ContainerWait menunggu container berhenti dan mengembalikan exit code-nya.
+// condition bisa kosong (not-running), next-exit, atau removed.
+func (c *Client) ContainerWait(ctx context.Context, id, condition string) (WaitResponse, error) {
+	query := url.Values{}
+	if condition != "" {
+		query.Set("condition", condition)
+	}
+	var resp WaitResponse
+	err := c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/wait", query, nil, &resp)
+	return resp, err
+}
+
+// ContainerLogs membuka stream log container. Untuk container tanpa TTY,
+// stream berformat multiplexed dan bisa dipisahkan dengan StdCopy.
+func (c *Client) ContainerLogs(ctx context.Context, id string, follow bool) (io.ReadCloser, error) {
+	query := url.Values{"stdout": {"1"}, "stderr": {"1"}}
+	if follow {
+		query.Set("follow", "1")
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(id)+"/logs", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Events membuka stream event dari daemon. Stream berisi EventMessage JSON
+// berurutan dan berakhir setelah until, atau terus berjalan jika until zero.
+// Tanpa since dan until, hanya event baru yang dikirim.
+func (c *Client) Events(ctx context.Context, since, until time.Time, filters map[string][]string) (io.ReadCloser, error) {
+	query := url.Values{}
+	if !since.IsZero() {
+		query.Set("since", formatTimestamp(since))
+	}
+	if !until.IsZero() {
+		query.Set("until", formatTimestamp(until))
+	}
+	if encoded := EncodeFilters(filters); encoded != "" {
+		query.Set("filters", encoded)
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/events", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// formatTimestamp menulis waktu sebagai Unix timestamp dengan nanodetik
+func formatTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+// ExecCreate menyiapkan perintah exec di container dan mengembalikan ID-nya
+func (c *Client) ExecCreate(ctx context.Context, id string, config ExecConfig) (string, error) {
+	var resp IDResponse
+	err := c.call(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/exec", nil, config, &resp)
+	return resp.ID, err
+}
+
+// HijackedConn adalah koneksi exec yang sudah di-upgrade menjadi stream
+// dua arah. Output dibaca dari Reader, input ditulis ke Conn.
+type HijackedConn struct {
+	Conn   net.Conn
+	Reader *bufio.Reader
+}
+
+// CloseWrite memberi tahu daemon bahwa input sudah selesai
+func (h *HijackedConn) CloseWrite() error {
+	if conn, ok := h.Conn.(interface{ CloseWrite() error }); ok {
+		return conn.CloseWrite()
+	}
+	return nil
+}
+
+// Close menutup koneksi
+func (h *HijackedConn) Close() error {
+	return h.Conn.Close()
+}
+
+// ExecAttach menjalankan exec dan mengembalikan stream input/output-nya
+func (c *Client) ExecAttach(ctx context.Context, execID string, config ExecStartConfig) (*HijackedConn, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/exec/"+url.PathEscape(execID)+"/start", nil, config)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "tcp")
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal terhubung ke daemon di %s: %v", c.socket, err)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("response daemon tidak valid: %v", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer conn.Close()
+		return nil, decodeError(resp)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols && resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("daemon tidak meng-upgrade koneksi exec: %s", resp.Status)
+	}
+	return &HijackedConn{Conn: conn, Reader: reader}, nil
+}
+
+// ExecInspect mengembalikan status exec
+func (c *Client) ExecInspect(ctx context.Context, execID string) (ExecInspect, error) {
+	var info ExecInspect
+	err := c.call(ctx, http.MethodGet, "/exec/"+url.PathEscape(execID)+"/json", nil, nil, &info)
+	return info, err
+}
+
+// ImageList mengembalikan daftar image
+func (c *Client) ImageList(ctx context.Context, filters map[string][]string) ([]ImageSummary, error) {
+	query := url.Values{}
+	if f := EncodeFilters(filters); f != "" {
+		query.Set("filters", f)
+	}
+	var images []ImageSummary
+	err := c.call(ctx, http.MethodGet, "/images/json", query, nil, &images)
+	return images, err
+}
+
+// VolumeList mengembalikan daftar volume
+func (c *Client) VolumeList(ctx context.Context, filters map[string][]string) (VolumeListResponse, error) {
+	query := url.Values{}
+	if f := EncodeFilters(filters); f != "" {
+		query.Set("filters", f)
+	}
+	var resp VolumeListResponse
+	err := c.call(ctx, http.MethodGet, "/volumes", query, nil, &resp)
+	return resp, err
+}
+
+// VolumeCreate membuat volume baru
+func (c *Client) VolumeCreate(ctx context.Context, req VolumeCreateRequest) (Volume, error) {
+	var volume Volume
+	err := c.call(ctx, http.MethodPost, "/volumes/create", nil, req, &volume)
+	return volume, err
+}
+
+// VolumeRemove menghapus volume
+func (c *Client) VolumeRemove(ctx context.Context, name string, force bool) error {
+	query := url.Values{}
+	if force {
+		query.Set("force", "1")
+	}
+	return c.call(ctx, http.MethodDelete, "/volumes/"+url.PathEscape(name), query, nil, nil)
+}
+
+// VolumeBackup menulis backup data volume ke path di host daemon
+func (c *Client) VolumeBackup(ctx context.Context, name, path string) error {
+	return c.call(ctx, http.MethodPost, "/volumes/"+url.PathEscape(name)+"/backup", nil, VolumeBackupRequest{Path: path}, nil)
+}
+
+// VolumeRestore memulihkan data volume dari file backup di host daemon
+func (c *Client) VolumeRestore(ctx context.Context, name, path string) error {
+	return c.call(ctx, http.MethodPost, "/volumes/"+url.PathEscape(name)+"/restore", nil, VolumeBackupRequest{Path: path}, nil)
+}
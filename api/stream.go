@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Jenis stream pada format multiplexed Docker
+const (
+	Stdin  byte = 0
+	Stdout byte = 1
+	Stderr byte = 2
+)
+
+// Content type response stream
+const (
+	MediaTypeRawStream         = "application/vnd.docker.raw-stream"
+	MediaTypeMultiplexedStream = "application/vnd.docker.multiplexed-stream"
+)
+
+// stdHeaderLen adalah panjang header setiap frame: 1 byte jenis stream,
+// 3 byte kosong, lalu 4 byte panjang payload big-endian
+const stdHeaderLen = 8
+
+// frameWriter menulis setiap Write sebagai satu frame multiplexed
+type frameWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	stream byte
+}
+
+func (f *frameWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	header := make([]byte, stdHeaderLen)
+	header[0] = f.stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(p)))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.w.Write(header); err != nil {
+		return 0, err
+	}
+	return f.w.Write(p)
+}
+
+// NewMultiplexedWriters mengembalikan writer stdout dan stderr yang menulis
+// frame multiplexed ke w. Kedua writer aman dipakai dari goroutine berbeda.
+func NewMultiplexedWriters(w io.Writer) (stdout, stderr io.Writer) {
+	mu := &sync.Mutex{}
+	return &frameWriter{mu: mu, w: w, stream: Stdout}, &frameWriter{mu: mu, w: w, stream: Stderr}
+}
+
+// StdCopy memisahkan stream multiplexed dari src ke stdout dan stderr
+// sampai src mencapai EOF
+func StdCopy(stdout, stderr io.Writer, src io.Reader) error {
+	header := make([]byte, stdHeaderLen)
+	for {
+		if _, err := io.ReadFull(src, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var dst io.Writer
+		switch header[0] {
+		case Stdin, Stdout:
+			dst = stdout
+		case Stderr:
+			dst = stderr
+		default:
+			return fmt.Errorf("jenis stream tidak dikenal: %d", header[0])
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(dst, src, size); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,364 @@
+// Package api berisi tipe request dan response dari subset Docker Engine API
+// yang dilayani minidockerd, beserta client HTTP untuk socket daemon. Nama
+// field JSON mengikuti Docker agar tooling berbasis Docker SDK bisa dipakai
+// tanpa perubahan.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	// Version adalah versi Docker Engine API yang dilayani daemon
+	Version = "1.43"
+	// MinVersion adalah versi API tertua yang masih diterima
+	MinVersion = "1.24"
+	// DefaultSocket adalah lokasi default socket daemon
+	DefaultSocket = "/run/minidocker.sock"
+	// EnvHost menimpa lokasi socket daemon untuk CLI
+	EnvHost = "MINIDOCKER_HOST"
+)
+
+// ErrorResponse adalah body response untuk request yang gagal
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// VersionResponse adalah response GET /version
+type VersionResponse struct {
+	Version       string
+	APIVersion    string `json:"ApiVersion"`
+	MinAPIVersion string `json:"MinAPIVersion"`
+	GoVersion     string
+	Os            string
+	Arch          string
+	KernelVersion string
+}
+
+// ContainerConfig adalah konfigurasi container yang tidak bergantung host
+type ContainerConfig struct {
+	Image      string
+	Cmd        []string
+	Entrypoint []string            `json:",omitempty"`
+	Env        []string            `json:",omitempty"`
+	Labels     map[string]string   `json:",omitempty"`
+	Volumes    map[string]struct{} `json:",omitempty"`
+	Tty        bool
+	OpenStdin  bool
+	StopSignal string `json:",omitempty"`
+}
+
+// RestartPolicy sama dengan restart policy Docker
+type RestartPolicy struct {
+	Name              string
+	MaximumRetryCount int
+}
+
+// PortBinding adalah port host untuk satu port container
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string
+}
+
+// LogConfig memilih log driver container
+type LogConfig struct {
+	Type   string
+	Config map[string]string `json:",omitempty"`
+}
+
+// ThrottleDevice adalah batas bandwidth IO untuk satu block device
+type ThrottleDevice struct {
+	Path string
+	Rate uint64
+}
+
+// Resources adalah batas resource container. Field-nya ada di level
+// teratas HostConfig dan body POST /containers/{id}/update seperti di
+// Docker; nilai nol berarti tidak diubah.
+type Resources struct {
+	Memory            int64
+	MemorySwap        int64
+	MemoryReservation int64
+	NanoCPUs          int64  `json:"NanoCpus"`
+	CPUShares         int64  `json:"CpuShares"`
+	CPUPeriod         int64  `json:"CpuPeriod"`
+	CPUQuota          int64  `json:"CpuQuota"`
+	CpusetCpus        string `json:",omitempty"`
+	CpusetMems        string `json:",omitempty"`
+	PidsLimit         *int64 `json:",omitempty"`
+
+	BlkioDeviceReadBps  []ThrottleDevice `json:",omitempty"`
+	BlkioDeviceWriteBps []ThrottleDevice `json:",omitempty"`
+}
+
+// HostConfig adalah konfigurasi container yang bergantung host
+type HostConfig struct {
+	Binds          []string                 `json:",omitempty"`
+	PortBindings   map[string][]PortBinding `json:",omitempty"`
+	RestartPolicy  RestartPolicy
+	AutoRemove     bool
+	Privileged     bool
+	ReadonlyRootfs bool
+	LogConfig      LogConfig
+	// SecurityOpt memilih profil keamanan minidocker dengan "profile=NAMA"
+	// dan menimpa opsinya dengan format --security-opt, misalnya
+	// "seccomp=/path/profil.json" atau "no-new-privileges"
+	SecurityOpt []string `json:",omitempty"`
+	CapAdd      []string `json:",omitempty"`
+	CapDrop     []string `json:",omitempty"`
+	// Tmpfs memetakan path container ke opsi mount, misalnya "size=64m"
+	Tmpfs       map[string]string `json:",omitempty"`
+	Annotations map[string]string `json:",omitempty"`
+
+	Resources
+}
+
+// ContainerCreateRequest adalah body POST /containers/create. Field
+// ContainerConfig berada di level teratas seperti di Docker.
+type ContainerCreateRequest struct {
+	ContainerConfig
+	HostConfig *HostConfig `json:",omitempty"`
+}
+
+// ContainerCreateResponse adalah response POST /containers/create
+type ContainerCreateResponse struct {
+	ID       string `json:"Id"`
+	Warnings []string
+}
+
+// Port adalah port yang dipublikasikan container
+type Port struct {
+	IP          string `json:"IP,omitempty"`
+	PrivatePort uint16
+	PublicPort  uint16 `json:",omitempty"`
+	Type        string
+}
+
+// Container adalah satu item response GET /containers/json
+type Container struct {
+	ID      string `json:"Id"`
+	Names   []string
+	Image   string
+	ImageID string
+	Command string
+	Created int64
+	Ports   []Port
+	Labels  map[string]string
+	State   string
+	Status  string
+}
+
+// ContainerState adalah state proses container di GET /containers/{id}/json
+type ContainerState struct {
+	Status     string
+	Running    bool
+	Paused     bool
+	Restarting bool
+	OOMKilled  bool
+	Dead       bool
+	Pid        int
+	ExitCode   int
+	Error      string
+	StartedAt  string
+	FinishedAt string
+}
+
+// MountPoint adalah mount container di GET /containers/{id}/json
+type MountPoint struct {
+	Type        string
+	Name        string `json:",omitempty"`
+	Source      string
+	Destination string
+	Mode        string
+	RW          bool
+}
+
+// NetworkSettings berisi port mapping container
+type NetworkSettings struct {
+	Ports map[string][]PortBinding
+}
+
+// ContainerJSON adalah response GET /containers/{id}/json
+type ContainerJSON struct {
+	ID              string `json:"Id"`
+	Created         string
+	Path            string
+	Args            []string
+	State           *ContainerState
+	Image           string
+	Name            string
+	RestartCount    int
+	LogPath         string
+	AppArmorProfile string
+	HostConfig      *HostConfig
+	Config          *ContainerConfig
+	Mounts          []MountPoint
+	NetworkSettings *NetworkSettings
+}
+
+// WaitResponse adalah response POST /containers/{id}/wait
+type WaitResponse struct {
+	StatusCode int64
+}
+
+// ContainerUpdateResponse adalah response POST /containers/{id}/update
+type ContainerUpdateResponse struct {
+	Warnings []string
+}
+
+// ContainersPruneReport adalah response POST /containers/prune
+type ContainersPruneReport struct {
+	ContainersDeleted []string
+	SpaceReclaimed    uint64
+}
+
+// ContainersResumeReport adalah response POST /containers/resume, endpoint
+// khusus minidocker yang menjalankan kembali container dengan restart
+// policy always/unless-stopped
+type ContainersResumeReport struct {
+	ContainersResumed []string
+}
+
+// VolumeBackupRequest adalah body POST /volumes/{name}/backup dan
+// /volumes/{name}/restore, endpoint khusus minidocker. Path adalah path
+// absolut file backup di host daemon.
+type VolumeBackupRequest struct {
+	Path string
+}
+
+// ExecConfig adalah body POST /containers/{id}/exec
+type ExecConfig struct {
+	AttachStdin  bool
+	AttachStdout bool
+	AttachStderr bool
+	Tty          bool
+	Env          []string `json:",omitempty"`
+	WorkingDir   string   `json:",omitempty"`
+	Cmd          []string
+}
+
+// IDResponse adalah response yang hanya berisi ID, misalnya untuk exec
+type IDResponse struct {
+	ID string `json:"Id"`
+}
+
+// ExecStartConfig adalah body POST /exec/{id}/start
+type ExecStartConfig struct {
+	Detach bool
+	Tty    bool
+}
+
+// ExecInspect adalah response GET /exec/{id}/json
+type ExecInspect struct {
+	ID          string
+	ContainerID string
+	Running     bool
+	ExitCode    int
+	Pid         int
+}
+
+// ImageSummary adalah satu item response GET /images/json
+type ImageSummary struct {
+	ID          string `json:"Id"`
+	ParentID    string `json:"ParentId"`
+	RepoTags    []string
+	RepoDigests []string
+	Created     int64
+	Size        int64
+	SharedSize  int64
+	Labels      map[string]string
+	Containers  int64
+}
+
+// Volume adalah volume di response /volumes
+type Volume struct {
+	Name       string
+	Driver     string
+	Mountpoint string
+	CreatedAt  string `json:",omitempty"`
+	Labels     map[string]string
+	Scope      string
+	Options    map[string]string
+}
+
+// VolumeListResponse adalah response GET /volumes
+type VolumeListResponse struct {
+	Volumes  []Volume
+	Warnings []string
+}
+
+// VolumeCreateRequest adalah body POST /volumes/create
+type VolumeCreateRequest struct {
+	Name       string
+	Driver     string            `json:",omitempty"`
+	DriverOpts map[string]string `json:",omitempty"`
+	Labels     map[string]string `json:",omitempty"`
+}
+
+// EventActor adalah objek yang mengalami event
+type EventActor struct {
+	ID         string
+	Attributes map[string]string
+}
+
+// EventMessage adalah satu event di stream GET /events
+type EventMessage struct {
+	Type     string
+	Action   string
+	Actor    EventActor
+	Scope    string `json:"scope"`
+	Time     int64  `json:"time"`
+	TimeNano int64  `json:"timeNano"`
+
+	// Field lama yang masih dibaca client Docker versi lama
+	Status string `json:"status,omitempty"`
+	ID     string `json:"id,omitempty"`
+	From   string `json:"from,omitempty"`
+}
+
+// EncodeFilters mengubah filter menjadi nilai query "filters" dengan format
+// {"key": {"value": true}} seperti Docker
+func EncodeFilters(filters map[string][]string) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	encoded := make(map[string]map[string]bool, len(filters))
+	for key, values := range filters {
+		encoded[key] = make(map[string]bool, len(values))
+		for _, value := range values {
+			encoded[key][value] = true
+		}
+	}
+	data, _ := json.Marshal(encoded)
+	return string(data)
+}
+
+// DecodeFilters membaca nilai query "filters". Format lama Docker
+// {"key": ["value"]} juga diterima.
+func DecodeFilters(value string) (map[string][]string, error) {
+	filters := map[string][]string{}
+	if value == "" {
+		return filters, nil
+	}
+
+	var current map[string]map[string]bool
+	if err := json.Unmarshal([]byte(value), &current); err == nil {
+		for key, values := range current {
+			for v, enabled := range values {
+				if enabled {
+					filters[key] = append(filters[key], v)
+				}
+			}
+			sort.Strings(filters[key])
+		}
+		return filters, nil
+	}
+
+	var legacy map[string][]string
+	if err := json.Unmarshal([]byte(value), &legacy); err != nil {
+		return nil, fmt.Errorf("format filters tidak valid: %v", err)
+	}
+	return legacy, nil
+}
@@ -0,0 +1,345 @@
+// Package client adalah SDK Go untuk minidocker. Client yang sama bisa
+// menjalankan operasi langsung di proses pemanggil (NewLocal) atau lewat
+// daemon minidockerd (NewRemote). Tidak ada method yang mencetak ke stdout;
+// semua hasil dikembalikan sebagai nilai atau error.
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/config"
+)
+
+// Jenis error yang bisa diperiksa dengan errors.Is, baik untuk client
+// in-process maupun client daemon
+var (
+	// ErrNotFound dikembalikan jika container, image, atau volume tidak ada
+	ErrNotFound = container.ErrNotFound
+	// ErrConflict dikembalikan jika operasi bertentangan dengan state objek,
+	// misalnya menghapus container yang sedang berjalan
+	ErrConflict = container.ErrConflict
+)
+
+// RunOptions berisi parameter untuk membuat container. Field yang kosong
+// memakai default dari config.json (in-process) atau dari daemon.
+type RunOptions struct {
+	Image string
+	Name  string
+	// Cmd menggantikan Cmd image jika diisi
+	Cmd []string
+	Env []string
+	// Volumes berformat HOST:CONTAINER, NAMA_VOLUME:CONTAINER, atau CONTAINER
+	// untuk anonymous volume
+	Volumes []string
+	// Ports berformat HOST:CONTAINER[/PROTO]
+	Ports []string
+	// Tmpfs memetakan path container ke opsi mount tmpfs, misalnya
+	// "/run": "size=64m"
+	Tmpfs       map[string]string
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// Memory adalah batas memory dalam bytes
+	Memory int64
+	// NanoCPUs adalah jumlah CPU dalam satuan 1e-9 (1.5 CPU = 1500000000)
+	NanoCPUs  int64
+	PidsLimit int64
+
+	// RestartPolicy berformat seperti --restart, misalnya "on-failure:3"
+	RestartPolicy string
+	AutoRemove    bool
+	// SecurityProfile adalah nama profil keamanan: default, restricted,
+	// privileged, atau profil di profiles_dir
+	SecurityProfile string
+	// SecurityOpt menimpa opsi profil seperti --security-opt, misalnya
+	// "seccomp=unconfined" atau "no-new-privileges"
+	SecurityOpt []string
+	// CapAdd dan CapDrop mengubah capability profil seperti --cap-add dan
+	// --cap-drop, misalnya "NET_ADMIN" atau "ALL"
+	CapAdd         []string
+	CapDrop        []string
+	ReadOnlyRootfs bool
+	// LogDriver adalah file atau none
+	LogDriver string
+}
+
+// ContainerState adalah state container. Status memakai nama state
+// minidocker: created, running, paused, restarting, atau stopped.
+type ContainerState struct {
+	ID      string
+	Name    string
+	Image   string
+	Command []string
+	Status  string
+
+	Running    bool
+	Paused     bool
+	Restarting bool
+	OOMKilled  bool
+	Pid        int
+	ExitCode   int
+
+	RestartCount int
+	Labels       map[string]string
+	// Ports berformat HOST:CONTAINER[/PROTO]
+	Ports []string
+
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// ImageSummary adalah ringkasan image di registry lokal
+type ImageSummary struct {
+	Name      string
+	Tag       string
+	Digest    string
+	Size      int64
+	CreatedAt time.Time
+	Labels    map[string]string
+}
+
+// VolumeSummary adalah ringkasan volume
+type VolumeSummary struct {
+	Name       string
+	Driver     string
+	Mountpoint string
+	CreatedAt  time.Time
+	Labels     map[string]string
+}
+
+// ExecOptions berisi perintah yang dijalankan di container beserta stdio-nya.
+// Stdin, Stdout, dan Stderr boleh nil.
+type ExecOptions struct {
+	Cmd []string
+	Env []string
+	// WorkingDir adalah direktori kerja absolut di container, default /
+	WorkingDir string
+	Stdin      io.Reader
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+// Event adalah perubahan state container, image, volume, atau network,
+// misalnya container start, die, atau oom
+type Event struct {
+	Type   string
+	Action string
+	// ID adalah ID container, referensi image (NAMA:TAG), nama volume, atau
+	// nama network
+	ID         string
+	Attributes map[string]string
+	Time       time.Time
+}
+
+// EventsOptions memilih event yang dikirim Events. Tanpa Since dan Until,
+// hanya event baru yang dikirim; tanpa Until, stream berjalan sampai ctx
+// selesai.
+type EventsOptions struct {
+	Since time.Time
+	Until time.Time
+	// Filters mendukung type, event, container, image, volume, network, dan label
+	Filters map[string][]string
+}
+
+// engine adalah implementasi operasi client, in-process atau lewat daemon
+type engine interface {
+	ping(ctx context.Context) error
+	create(ctx context.Context, opts RunOptions) (string, error)
+	start(ctx context.Context, id string) error
+	stop(ctx context.Context, id string, timeout time.Duration) error
+	remove(ctx context.Context, id string, force bool) error
+	list(ctx context.Context, all bool, filters map[string][]string) ([]ContainerState, error)
+	inspect(ctx context.Context, id string) (ContainerState, error)
+	wait(ctx context.Context, id string) (int, error)
+	logs(ctx context.Context, id string, follow bool, stdout, stderr io.Writer) error
+	exec(ctx context.Context, id string, opts ExecOptions) (int, error)
+	images(ctx context.Context, filters map[string][]string) ([]ImageSummary, error)
+	volumeCreate(ctx context.Context, name string, labels map[string]string) (VolumeSummary, error)
+	volumes(ctx context.Context, filters map[string][]string) ([]VolumeSummary, error)
+	volumeRemove(ctx context.Context, name string, force bool) error
+	events(ctx context.Context, opts EventsOptions, fn func(Event) error) error
+}
+
+// Client adalah client minidocker. Semua method aman dipakai dari beberapa
+// goroutine sekaligus.
+type Client struct {
+	engine engine
+}
+
+// NewLocal membuat client yang menjalankan operasi langsung di proses ini
+// dengan direktori data dari cfg (nil berarti konfigurasi bawaan). binary
+// adalah binary minidocker yang dipakai sebagai shim container; kosong
+// berarti dicari di PATH. Lokasi data paket container bersifat global, jadi
+// satu proses hanya bisa memakai satu root. Pesan progres engine dibuang.
+//
+// Client in-process hanya memeriksa ctx sebelum operasi dimulai. Setelah
+// dimulai, ContainerCreate, ContainerStart, ContainerStop (yang bisa menunggu
+// sampai timeout), dan ContainerRemove tetap berjalan sampai selesai walaupun
+// ctx dibatalkan. ContainerWait, ContainerLogs, ContainerExec, dan Events
+// berhenti begitu ctx selesai.
+func NewLocal(cfg *config.Config, binary string) (*Client, error) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if binary == "" {
+		path, err := exec.LookPath("minidocker")
+		if err != nil {
+			return nil, fmt.Errorf("binary minidocker tidak ditemukan di PATH: %v", err)
+		}
+		binary = path
+	}
+
+	container.Configure(cfg)
+	container.ShimBinary = binary
+	container.SetOutput(io.Discard)
+	return &Client{engine: &localEngine{cfg: cfg}}, nil
+}
+
+// NewRemote membuat client untuk daemon minidockerd. host berupa path socket
+// atau URL unix:///path; kosong berarti socket default.
+func NewRemote(host string) (*Client, error) {
+	if host == "" {
+		host = api.DefaultSocket
+	}
+	c, err := api.NewClient(host)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{engine: &remoteEngine{api: c}}, nil
+}
+
+// Ping memeriksa apakah engine bisa dipakai
+func (c *Client) Ping(ctx context.Context) error {
+	return c.engine.ping(ctx)
+}
+
+// ContainerCreate membuat container tanpa menjalankannya dan mengembalikan ID-nya
+func (c *Client) ContainerCreate(ctx context.Context, opts RunOptions) (string, error) {
+	return c.engine.create(ctx, opts)
+}
+
+// ContainerStart menjalankan container di background. Container yang sudah
+// berjalan dibiarkan.
+func (c *Client) ContainerStart(ctx context.Context, id string) error {
+	return c.engine.start(ctx, id)
+}
+
+// ContainerRun membuat lalu menjalankan container di background. Jika start
+// gagal, ID container yang sudah dibuat tetap dikembalikan.
+func (c *Client) ContainerRun(ctx context.Context, opts RunOptions) (string, error) {
+	id, err := c.engine.create(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	return id, c.engine.start(ctx, id)
+}
+
+// ContainerStop menghentikan container dengan stop signal-nya, lalu SIGKILL
+// setelah timeout. timeout negatif memakai default 10 detik. Container yang
+// tidak berjalan dibiarkan.
+func (c *Client) ContainerStop(ctx context.Context, id string, timeout time.Duration) error {
+	if timeout < 0 {
+		timeout = container.DefaultStopTimeout
+	}
+	return c.engine.stop(ctx, id, timeout)
+}
+
+// ContainerRemove menghapus container. force menghentikan container yang
+// masih berjalan dengan SIGKILL.
+func (c *Client) ContainerRemove(ctx context.Context, id string, force bool) error {
+	return c.engine.remove(ctx, id, force)
+}
+
+// ContainerList mengembalikan container yang cocok dengan filter (status,
+// name, id, ancestor, label). Tanpa all, hanya container aktif yang dikembalikan.
+func (c *Client) ContainerList(ctx context.Context, all bool, filters map[string][]string) ([]ContainerState, error) {
+	return c.engine.list(ctx, all, filters)
+}
+
+// ContainerInspect mengembalikan state container berdasarkan ID, prefix ID, atau nama
+func (c *Client) ContainerInspect(ctx context.Context, id string) (ContainerState, error) {
+	return c.engine.inspect(ctx, id)
+}
+
+// ContainerWait menunggu container berhenti dan mengembalikan exit code-nya
+func (c *Client) ContainerWait(ctx context.Context, id string) (int, error) {
+	return c.engine.wait(ctx, id)
+}
+
+// ContainerLogs menulis log container ke stdout dan stderr. Dengan follow,
+// log baru terus ditulis sampai container berhenti atau ctx selesai. Log
+// dari client in-process tidak dipisah per stream dan seluruhnya ditulis
+// ke stdout.
+func (c *Client) ContainerLogs(ctx context.Context, id string, follow bool, stdout, stderr io.Writer) error {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	return c.engine.logs(ctx, id, follow, stdout, stderr)
+}
+
+// ContainerExec menjalankan perintah di container yang sedang berjalan dan
+// mengembalikan exit code-nya
+func (c *Client) ContainerExec(ctx context.Context, id string, opts ExecOptions) (int, error) {
+	return c.engine.exec(ctx, id, opts)
+}
+
+// ImageList mengembalikan image yang cocok dengan filter (reference, label, dangling)
+func (c *Client) ImageList(ctx context.Context, filters map[string][]string) ([]ImageSummary, error) {
+	return c.engine.images(ctx, filters)
+}
+
+// VolumeCreate membuat volume baru
+func (c *Client) VolumeCreate(ctx context.Context, name string, labels map[string]string) (VolumeSummary, error) {
+	return c.engine.volumeCreate(ctx, name, labels)
+}
+
+// VolumeList mengembalikan volume yang cocok dengan filter (name, driver, label, dangling)
+func (c *Client) VolumeList(ctx context.Context, filters map[string][]string) ([]VolumeSummary, error) {
+	return c.engine.volumes(ctx, filters)
+}
+
+// VolumeRemove menghapus volume. force menghapus volume yang masih dipakai.
+func (c *Client) VolumeRemove(ctx context.Context, name string, force bool) error {
+	return c.engine.volumeRemove(ctx, name, force)
+}
+
+// Events mengirim event yang cocok dengan opts secara berurutan ke channel
+// pertama. Jika stream berhenti karena error atau ctx selesai, error-nya
+// dikirim ke channel kedua. Kedua channel ditutup setelah stream berakhir,
+// jadi error nil berarti Until sudah terlewati.
+func (c *Client) Events(ctx context.Context, opts EventsOptions) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		err := c.engine.events(ctx, opts, func(e Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
@@ -0,0 +1,348 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/config"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// localEngine menjalankan operasi langsung lewat paket container. Operasi
+// paket container tidak bisa dibatalkan, jadi ctx hanya diperiksa di awal
+// kecuali untuk wait, logs, exec, dan events.
+type localEngine struct {
+	cfg *config.Config
+}
+
+func (e *localEngine) ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (e *localEngine) create(ctx context.Context, opts RunOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	restartPolicy, err := container.ParseRestartPolicy(opts.RestartPolicy)
+	if err != nil {
+		return "", err
+	}
+
+	runOpts := container.RunOptions{
+		Image:         opts.Image,
+		Name:          opts.Name,
+		Command:       opts.Cmd,
+		Env:           opts.Env,
+		Volumes:       opts.Volumes,
+		Ports:         opts.Ports,
+		Tmpfs:         opts.Tmpfs,
+		Labels:        opts.Labels,
+		Annotations:   opts.Annotations,
+		Detach:        true,
+		AutoRemove:    opts.AutoRemove,
+		RestartPolicy: restartPolicy,
+		LogDriver:     e.cfg.LogDriver,
+		Resources: container.Resources{
+			Memory:    e.cfg.DefaultMemory,
+			CPU:       e.cfg.DefaultCPU,
+			PidsLimit: opts.PidsLimit,
+		},
+	}
+	if opts.Memory > 0 {
+		runOpts.Memory = strconv.FormatInt(opts.Memory, 10)
+	}
+	if opts.NanoCPUs > 0 {
+		runOpts.NanoCPUs = opts.NanoCPUs
+		runOpts.CPU = ""
+	}
+	if opts.LogDriver != "" {
+		runOpts.LogDriver = opts.LogDriver
+	}
+
+	profileName := e.cfg.SecurityProfile
+	if opts.SecurityProfile != "" {
+		profileName = opts.SecurityProfile
+	}
+	secProfile, err := container.BuildSecurityProfile(profileName, opts.ReadOnlyRootfs, opts.SecurityOpt, opts.CapAdd, opts.CapDrop)
+	if err != nil {
+		return "", err
+	}
+
+	c, err := container.CreateContainer(runOpts, secProfile)
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
+func (e *localEngine) start(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	info, err := container.InspectContainer(id)
+	if err != nil {
+		return err
+	}
+	if info.State.Running {
+		return nil
+	}
+	return container.StartContainer(info.ID)
+}
+
+func (e *localEngine) stop(ctx context.Context, id string, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	info, err := container.InspectContainer(id)
+	if err != nil {
+		return err
+	}
+	if !info.State.Running && !info.State.Restarting {
+		return nil
+	}
+	return container.StopContainer(info.ID, timeout)
+}
+
+func (e *localEngine) remove(ctx context.Context, id string, force bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return container.RemoveContainer(id, force)
+}
+
+func (e *localEngine) list(ctx context.Context, all bool, filters map[string][]string) ([]ContainerState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	summaries, err := container.ListContainers(all, container.Filters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	states := []ContainerState{}
+	for _, s := range summaries {
+		info, err := container.InspectContainer(s.ID)
+		if err != nil {
+			// Container dihapus di antara list dan inspect
+			continue
+		}
+		states = append(states, localState(info))
+	}
+	return states, nil
+}
+
+func (e *localEngine) inspect(ctx context.Context, id string) (ContainerState, error) {
+	if err := ctx.Err(); err != nil {
+		return ContainerState{}, err
+	}
+	info, err := container.InspectContainer(id)
+	if err != nil {
+		return ContainerState{}, err
+	}
+	return localState(info), nil
+}
+
+// localState mengubah hasil inspect paket container menjadi ContainerState
+func localState(info *container.ContainerInspect) ContainerState {
+	return ContainerState{
+		ID:           info.ID,
+		Name:         info.Name,
+		Image:        info.Image,
+		Command:      info.Config.Cmd,
+		Status:       info.State.Status,
+		Running:      info.State.Running,
+		Paused:       info.State.Paused,
+		Restarting:   info.State.Restarting,
+		OOMKilled:    info.State.OOMKilled,
+		Pid:          info.State.Pid,
+		ExitCode:     info.State.ExitCode,
+		RestartCount: info.RestartCount,
+		Labels:       info.Config.Labels,
+		Ports:        info.HostConfig.PortBindings,
+		CreatedAt:    info.Created,
+		StartedAt:    info.State.StartedAt,
+		FinishedAt:   info.State.FinishedAt,
+	}
+}
+
+func (e *localEngine) wait(ctx context.Context, id string) (int, error) {
+	info, err := container.InspectContainer(id)
+	if err != nil {
+		return 0, err
+	}
+	id = info.ID
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if !info.State.Running && !info.State.Restarting {
+			return info.State.ExitCode, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := container.InspectContainer(id)
+		if errors.Is(err, container.ErrNotFound) {
+			// Container dengan AutoRemove langsung dihapus setelah berhenti
+			return info.State.ExitCode, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		info = current
+	}
+}
+
+func (e *localEngine) logs(ctx context.Context, id string, follow bool, stdout, _ io.Writer) error {
+	return container.WriteContainerLogs(ctx, id, follow, stdout)
+}
+
+func (e *localEngine) exec(ctx context.Context, id string, opts ExecOptions) (int, error) {
+	cmd, err := container.ExecCommand(id, container.ExecOptions{
+		Cmd:        opts.Cmd,
+		Env:        opts.Env,
+		WorkingDir: opts.WorkingDir,
+		Stdin:      opts.Stdin,
+		Stdout:     opts.Stdout,
+		Stderr:     opts.Stderr,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("gagal menjalankan exec: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		return 0, ctx.Err()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("gagal menjalankan exec: %v", err)
+	}
+	return 0, nil
+}
+
+func (e *localEngine) images(ctx context.Context, filters map[string][]string) ([]ImageSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	images, err := container.ListImages()
+	if err != nil {
+		return nil, err
+	}
+	images, err = container.FilterImages(images, container.Filters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []ImageSummary{}
+	for _, img := range images {
+		summaries = append(summaries, ImageSummary{
+			Name:      img.Name,
+			Tag:       img.Tag,
+			Digest:    img.Digest,
+			Size:      img.Size,
+			CreatedAt: img.CreatedAt,
+			Labels:    img.Labels,
+		})
+	}
+	return summaries, nil
+}
+
+func (e *localEngine) volumeCreate(ctx context.Context, name string, labels map[string]string) (VolumeSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return VolumeSummary{}, err
+	}
+	// Seperti daemon, volume tanpa nama mendapat nama acak
+	if name == "" {
+		name = utils.GenerateID(64)
+	}
+	volume, err := container.CreateVolume(name, labels)
+	if err != nil {
+		return VolumeSummary{}, err
+	}
+	return localVolume(*volume), nil
+}
+
+func (e *localEngine) volumes(ctx context.Context, filters map[string][]string) ([]VolumeSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	volumes, err := container.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+	volumes, err = container.FilterVolumes(volumes, container.Filters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []VolumeSummary{}
+	for _, v := range volumes {
+		summaries = append(summaries, localVolume(v))
+	}
+	return summaries, nil
+}
+
+// localVolume mengubah volume paket container menjadi VolumeSummary
+func localVolume(v container.Volume) VolumeSummary {
+	return VolumeSummary{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  v.CreatedAt,
+		Labels:     v.Labels,
+	}
+}
+
+func (e *localEngine) events(ctx context.Context, opts EventsOptions, fn func(Event) error) error {
+	now := time.Now()
+	since := opts.Since
+	if since.IsZero() && opts.Until.IsZero() {
+		since = now
+	}
+	return container.Events(ctx, container.EventOptions{
+		Since:   since,
+		Until:   opts.Until,
+		Filters: container.Filters(opts.Filters),
+		Follow:  opts.Until.IsZero() || opts.Until.After(now),
+	}, func(event container.Event) error {
+		return fn(Event{
+			Type:       event.Type,
+			Action:     event.Action,
+			ID:         event.ID,
+			Attributes: event.Attributes,
+			Time:       event.Time,
+		})
+	})
+}
+
+func (e *localEngine) volumeRemove(ctx context.Context, name string, force bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return container.RemoveVolume(name, force)
+}
@@ -0,0 +1,383 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+)
+
+// remoteEngine menjalankan operasi lewat API daemon minidockerd
+type remoteEngine struct {
+	api *api.Client
+}
+
+// apiError menandai error dari daemon dengan jenisnya sehingga errors.Is
+// bisa dipakai untuk ErrNotFound dan ErrConflict, dan errors.As tetap bisa
+// mengambil *api.Error
+type apiError struct {
+	kind error
+	err  *api.Error
+}
+
+func (e *apiError) Error() string { return e.err.Message }
+
+func (e *apiError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// convertError mengubah kode status response daemon menjadi jenis error client
+func convertError(err error) error {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		return &apiError{kind: ErrNotFound, err: apiErr}
+	case http.StatusConflict:
+		return &apiError{kind: ErrConflict, err: apiErr}
+	}
+	return err
+}
+
+func (e *remoteEngine) ping(ctx context.Context) error {
+	return convertError(e.api.Ping(ctx))
+}
+
+func (e *remoteEngine) create(ctx context.Context, opts RunOptions) (string, error) {
+	// Restart policy divalidasi di sisi client agar formatnya sama dengan
+	// client in-process
+	restartPolicy, err := container.ParseRestartPolicy(opts.RestartPolicy)
+	if err != nil {
+		return "", err
+	}
+
+	host := &api.HostConfig{
+		Binds:        opts.Volumes,
+		PortBindings: map[string][]api.PortBinding{},
+		RestartPolicy: api.RestartPolicy{
+			Name:              restartPolicy.Name,
+			MaximumRetryCount: restartPolicy.MaximumRetryCount,
+		},
+		AutoRemove:     opts.AutoRemove,
+		ReadonlyRootfs: opts.ReadOnlyRootfs,
+		CapAdd:         opts.CapAdd,
+		CapDrop:        opts.CapDrop,
+		Tmpfs:          opts.Tmpfs,
+		LogConfig:      api.LogConfig{Type: opts.LogDriver},
+		Annotations:    opts.Annotations,
+		Resources:      api.Resources{Memory: opts.Memory, NanoCPUs: opts.NanoCPUs},
+	}
+	if opts.SecurityProfile != "" {
+		host.SecurityOpt = []string{"profile=" + opts.SecurityProfile}
+	}
+	host.SecurityOpt = append(host.SecurityOpt, opts.SecurityOpt...)
+	if opts.PidsLimit != 0 {
+		limit := opts.PidsLimit
+		host.PidsLimit = &limit
+	}
+	for _, port := range opts.Ports {
+		parts := strings.SplitN(port, ":", 2)
+		if len(parts) != 2 {
+			return "", fmt.Errorf("format port tidak valid: %q (gunakan HOST:CONTAINER[/PROTO])", port)
+		}
+		key := parts[1]
+		if !strings.Contains(key, "/") {
+			key += "/tcp"
+		}
+		host.PortBindings[key] = append(host.PortBindings[key], api.PortBinding{HostPort: parts[0]})
+	}
+
+	resp, err := e.api.ContainerCreate(ctx, opts.Name, api.ContainerCreateRequest{
+		ContainerConfig: api.ContainerConfig{
+			Image:  opts.Image,
+			Cmd:    opts.Cmd,
+			Env:    opts.Env,
+			Labels: opts.Labels,
+		},
+		HostConfig: host,
+	})
+	if err != nil {
+		return "", convertError(err)
+	}
+	return resp.ID, nil
+}
+
+func (e *remoteEngine) start(ctx context.Context, id string) error {
+	return convertError(e.api.ContainerStart(ctx, id))
+}
+
+func (e *remoteEngine) stop(ctx context.Context, id string, timeout time.Duration) error {
+	return convertError(e.api.ContainerStop(ctx, id, int(timeout/time.Second)))
+}
+
+func (e *remoteEngine) remove(ctx context.Context, id string, force bool) error {
+	return convertError(e.api.ContainerRemove(ctx, id, force))
+}
+
+func (e *remoteEngine) list(ctx context.Context, all bool, filters map[string][]string) ([]ContainerState, error) {
+	list, err := e.api.ContainerList(ctx, all, filters)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	states := []ContainerState{}
+	for _, c := range list {
+		info, err := e.api.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			// Container dihapus di antara list dan inspect
+			continue
+		}
+		states = append(states, remoteState(info))
+	}
+	return states, nil
+}
+
+func (e *remoteEngine) inspect(ctx context.Context, id string) (ContainerState, error) {
+	info, err := e.api.ContainerInspect(ctx, id)
+	if err != nil {
+		return ContainerState{}, convertError(err)
+	}
+	return remoteState(info), nil
+}
+
+// remoteState mengubah response inspect Docker menjadi ContainerState
+func remoteState(info api.ContainerJSON) ContainerState {
+	state := ContainerState{
+		ID:           info.ID,
+		Name:         strings.TrimPrefix(info.Name, "/"),
+		Image:        info.Image,
+		Command:      append([]string{info.Path}, info.Args...),
+		RestartCount: info.RestartCount,
+		Ports:        []string{},
+		CreatedAt:    parseTime(info.Created),
+	}
+	if info.Path == "" {
+		state.Command = nil
+	}
+	if info.Config != nil {
+		state.Labels = info.Config.Labels
+	}
+	if s := info.State; s != nil {
+		state.Status = s.Status
+		if state.Status == "exited" {
+			state.Status = container.StateStopped
+		}
+		state.Running = s.Running
+		state.Paused = s.Paused
+		state.Restarting = s.Restarting
+		state.OOMKilled = s.OOMKilled
+		state.Pid = s.Pid
+		state.ExitCode = s.ExitCode
+		state.StartedAt = parseTime(s.StartedAt)
+		state.FinishedAt = parseTime(s.FinishedAt)
+	}
+	if info.HostConfig != nil {
+		for key, bindings := range info.HostConfig.PortBindings {
+			containerPort := strings.TrimSuffix(key, "/tcp")
+			for _, b := range bindings {
+				state.Ports = append(state.Ports, b.HostPort+":"+containerPort)
+			}
+		}
+		sort.Strings(state.Ports)
+	}
+	return state
+}
+
+// parseTime membaca waktu RFC 3339 dari daemon, nilai tidak valid menjadi zero time
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.IsZero() {
+		return time.Time{}
+	}
+	return t
+}
+
+func (e *remoteEngine) wait(ctx context.Context, id string) (int, error) {
+	resp, err := e.api.ContainerWait(ctx, id, "")
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return int(resp.StatusCode), nil
+}
+
+func (e *remoteEngine) logs(ctx context.Context, id string, follow bool, stdout, stderr io.Writer) error {
+	info, err := e.api.ContainerInspect(ctx, id)
+	if err != nil {
+		return convertError(err)
+	}
+	logs, err := e.api.ContainerLogs(ctx, id, follow)
+	if err != nil {
+		return convertError(err)
+	}
+	defer logs.Close()
+
+	if info.Config != nil && info.Config.Tty {
+		_, err = io.Copy(stdout, logs)
+	} else {
+		err = api.StdCopy(stdout, stderr, logs)
+	}
+	// Stream yang diputus karena ctx selesai bukan error
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func (e *remoteEngine) exec(ctx context.Context, id string, opts ExecOptions) (int, error) {
+	execID, err := e.api.ExecCreate(ctx, id, api.ExecConfig{
+		AttachStdin:  opts.Stdin != nil,
+		AttachStdout: opts.Stdout != nil,
+		AttachStderr: opts.Stderr != nil,
+		Env:          opts.Env,
+		WorkingDir:   opts.WorkingDir,
+		Cmd:          opts.Cmd,
+	})
+	if err != nil {
+		return 0, convertError(err)
+	}
+
+	conn, err := e.api.ExecAttach(ctx, execID, api.ExecStartConfig{})
+	if err != nil {
+		return 0, convertError(err)
+	}
+	defer conn.Close()
+
+	// Koneksi ditutup jika ctx selesai agar StdCopy berhenti
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if opts.Stdin != nil {
+		go func() {
+			io.Copy(conn.Conn, opts.Stdin)
+			conn.CloseWrite()
+		}()
+	}
+
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	if err := api.StdCopy(stdout, stderr, conn.Reader); err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+
+	info, err := e.api.ExecInspect(ctx, execID)
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return info.ExitCode, nil
+}
+
+func (e *remoteEngine) images(ctx context.Context, filters map[string][]string) ([]ImageSummary, error) {
+	list, err := e.api.ImageList(ctx, filters)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	images := []ImageSummary{}
+	for _, img := range list {
+		for _, ref := range img.RepoTags {
+			name, tag := ref, "latest"
+			if idx := strings.LastIndex(ref, ":"); idx > 0 {
+				name, tag = ref[:idx], ref[idx+1:]
+			}
+			images = append(images, ImageSummary{
+				Name:      name,
+				Tag:       tag,
+				Digest:    img.ID,
+				Size:      img.Size,
+				CreatedAt: time.Unix(img.Created, 0),
+				Labels:    img.Labels,
+			})
+		}
+	}
+	return images, nil
+}
+
+func (e *remoteEngine) volumeCreate(ctx context.Context, name string, labels map[string]string) (VolumeSummary, error) {
+	volume, err := e.api.VolumeCreate(ctx, api.VolumeCreateRequest{Name: name, Labels: labels})
+	if err != nil {
+		return VolumeSummary{}, convertError(err)
+	}
+	return remoteVolume(volume), nil
+}
+
+func (e *remoteEngine) volumes(ctx context.Context, filters map[string][]string) ([]VolumeSummary, error) {
+	resp, err := e.api.VolumeList(ctx, filters)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	volumes := []VolumeSummary{}
+	for _, v := range resp.Volumes {
+		volumes = append(volumes, remoteVolume(v))
+	}
+	return volumes, nil
+}
+
+// remoteVolume mengubah volume dari daemon menjadi VolumeSummary
+func remoteVolume(v api.Volume) VolumeSummary {
+	return VolumeSummary{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  parseTime(v.CreatedAt),
+		Labels:     v.Labels,
+	}
+}
+
+func (e *remoteEngine) events(ctx context.Context, opts EventsOptions, fn func(Event) error) error {
+	stream, err := e.api.Events(ctx, opts.Since, opts.Until, opts.Filters)
+	if err != nil {
+		return convertError(err)
+	}
+	defer stream.Close()
+
+	dec := json.NewDecoder(stream)
+	for {
+		var msg api.EventMessage
+		if err := dec.Decode(&msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := fn(Event{
+			Type:       msg.Type,
+			Action:     msg.Action,
+			ID:         msg.Actor.ID,
+			Attributes: msg.Actor.Attributes,
+			Time:       time.Unix(0, msg.TimeNano),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *remoteEngine) volumeRemove(ctx context.Context, name string, force bool) error {
+	return convertError(e.api.VolumeRemove(ctx, name, force))
+}
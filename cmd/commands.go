@@ -1,11 +1,25 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
+	"text/template"
+	"time"
 
 	"github.com/urfave/cli/v2"
+	"github.com/user/minidocker/api"
 	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/daemon"
+	"github.com/user/minidocker/pkg/seccomp"
+	"github.com/user/minidocker/pkg/utils"
 )
 
 // RunCommand - Perintah untuk menjalankan container
@@ -13,12 +27,32 @@ func RunCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "run",
 		Usage: "Jalankan container dengan image tertentu",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "image",
+		ArgsUsage: "IMAGE [COMMAND] [ARGS...]",
+		UseShortOptionHandling: true,
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:    "detach",
+				Aliases: []string{"d"},
+				Usage:   "Jalankan container di background, output hanya ditulis ke log",
+			},
+			&cli.BoolFlag{
+				Name:    "interactive",
 				Aliases: []string{"i"},
-				Usage:   "Image yang digunakan (contoh: alpine)",
-				Required: true,
+				Usage:   "Hubungkan stdin ke container",
+			},
+			&cli.BoolFlag{
+				Name:    "tty",
+				Aliases: []string{"t"},
+				Usage:   "Alokasikan pseudo-terminal di dalam container",
+			},
+			&cli.BoolFlag{
+				Name:  "rm",
+				Usage: "Hapus container secara otomatis ketika berhenti",
+			},
+			&cli.StringFlag{
+				Name:  "restart",
+				Usage: "Restart policy: no, on-failure[:N], always, atau unless-stopped",
+				Value: container.RestartNo,
 			},
 			&cli.StringFlag{
 				Name:    "name",
@@ -30,28 +64,41 @@ func RunCommand() *cli.Command {
 				Aliases: []string{"v"},
 				Usage:   "Mount volume (format: host-path:container-path)",
 			},
+			&cli.StringSliceFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Set environment variable (format: KEY=VALUE atau KEY untuk mengambil nilai dari host)",
+			},
 			&cli.StringSliceFlag{
 				Name:    "port",
 				Aliases: []string{"p"},
 				Usage:   "Map port (format: host-port:container-port)",
 			},
-			&cli.StringFlag{
-				Name:    "memory",
-				Aliases: []string{"m"},
-				Usage:   "Batasan memory (format: 64m, 128m, 256m)",
-				Value:   "64m",
+			&cli.StringSliceFlag{
+				Name:    "label",
+				Aliases: []string{"l"},
+				Usage:   "Tambahkan label ke container (format: KEY=VALUE)",
 			},
-			&cli.StringFlag{
-				Name:    "cpu",
-				Aliases: []string{"c"},
-				Usage:   "Batasan CPU dalam persentase (0-100)",
-				Value:   "10",
+			&cli.StringSliceFlag{
+				Name:  "label-file",
+				Usage: "Baca label dari file berisi KEY=VALUE per baris",
+			},
+			&cli.StringSliceFlag{
+				Name:  "annotation",
+				Usage: "Tambahkan anotasi ke container (format: KEY=VALUE)",
 			},
 			&cli.StringFlag{
 				Name:    "security-profile",
 				Aliases: []string{"s"},
-				Usage:   "Profil keamanan (default, restricted, privileged)",
-				Value:   "default",
+				Usage:   "Profil keamanan (default, restricted, privileged, atau profil di profiles_dir), default dari security_profile di config.json",
+			},
+			&cli.StringSliceFlag{
+				Name:  "security-opt",
+				Usage: "Timpa opsi profil keamanan: seccomp=PATH|unconfined, apparmor=NAMA, no-new-privileges, atau label=...",
+			},
+			&cli.StringFlag{
+				Name:  "log-driver",
+				Usage: "Tujuan output container: file atau none, default dari log_driver di config.json",
 			},
 			&cli.BoolFlag{
 				Name:    "read-only",
@@ -63,18 +110,91 @@ func RunCommand() *cli.Command {
 				Usage:   "Jalankan container dalam mode privileged (mengesampingkan security-profile)",
 				Value:   false,
 			},
-		},
+			&cli.StringSliceFlag{
+				Name:  "cap-add",
+				Usage: "Tambahkan Linux capability ke profil keamanan (misalnya NET_ADMIN, atau ALL)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "cap-drop",
+				Usage: "Hapus Linux capability dari profil keamanan (misalnya NET_RAW, atau ALL)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "tmpfs",
+				Usage: "Pasang tmpfs writable di container (format: PATH[:OPSI], misalnya /run:size=64m)",
+			},
+		}, resourceFlags()...),
 		Action: func(ctx *cli.Context) error {
-			imageName := ctx.String("image")
-			containerName := ctx.String("name")
-			volumes := ctx.StringSlice("volume")
-			ports := ctx.StringSlice("port")
-			memory := ctx.String("memory")
-			cpu := ctx.String("cpu")
+			if ctx.NArg() < 1 {
+				return fmt.Errorf("Diperlukan nama image")
+			}
+
+			// Default resource dari config.json, ditimpa flag yang diberikan
+			resources := container.Resources{
+				Memory: currentConfig.DefaultMemory,
+				CPU:    currentConfig.DefaultCPU,
+			}
+			if err := mergeResourceFlags(ctx, &resources); err != nil {
+				return err
+			}
+
+			restartPolicy, err := container.ParseRestartPolicy(ctx.String("restart"))
+			if err != nil {
+				return err
+			}
+
+			// Label dari file diterapkan lebih dulu sehingga bisa ditimpa --label
+			var labelValues []string
+			for _, path := range ctx.StringSlice("label-file") {
+				values, err := container.ReadLabelFile(path)
+				if err != nil {
+					return err
+				}
+				labelValues = append(labelValues, values...)
+			}
+			labels, err := container.ParseLabels(append(labelValues, ctx.StringSlice("label")...))
+			if err != nil {
+				return err
+			}
+			annotations, err := container.ParseLabels(ctx.StringSlice("annotation"))
+			if err != nil {
+				return fmt.Errorf("anotasi tidak valid: %v", err)
+			}
+			tmpfs, err := container.ParseTmpfs(joinTmpfsFlags(ctx.StringSlice("tmpfs")))
+			if err != nil {
+				return err
+			}
+
+			opts := container.RunOptions{
+				Image:   ctx.Args().First(),
+				Name:    ctx.String("name"),
+				Command: ctx.Args().Slice()[1:],
+				Env:     parseEnvFlags(ctx.StringSlice("env")),
+				Volumes: ctx.StringSlice("volume"),
+				Ports:   ctx.StringSlice("port"),
+				Tmpfs:   tmpfs,
+
+				Resources: resources,
+
+				Labels:      labels,
+				Annotations: annotations,
+
+				Detach:      ctx.Bool("detach"),
+				Interactive: ctx.Bool("interactive"),
+				TTY:         ctx.Bool("tty"),
+				AutoRemove:  ctx.Bool("rm"),
+
+				RestartPolicy: restartPolicy,
+				LogDriver:     currentConfig.LogDriver,
+			}
+			if ctx.IsSet("log-driver") {
+				opts.LogDriver = ctx.String("log-driver")
+			}
 			
 			// Security options
-			securityProfile := ctx.String("security-profile")
-			readOnly := ctx.Bool("read-only")
+			securityProfile := currentConfig.SecurityProfile
+			if ctx.IsSet("security-profile") {
+				securityProfile = ctx.String("security-profile")
+			}
 			privileged := ctx.Bool("privileged")
 			
 			// Jika privileged, override security profile
@@ -82,50 +202,916 @@ func RunCommand() *cli.Command {
 				securityProfile = "privileged"
 			}
 			
-			// Dapatkan profil keamanan berdasarkan nama
-			secProfile, err := container.GetSecurityProfile(securityProfile)
+			// Profil keamanan beserta --read-only, --security-opt, --cap-add,
+			// dan --cap-drop
+			secProfile, err := container.BuildSecurityProfile(securityProfile, ctx.Bool("read-only"),
+				ctx.StringSlice("security-opt"), ctx.StringSlice("cap-add"), ctx.StringSlice("cap-drop"))
 			if err != nil {
 				return err
 			}
-			
-			// Override read-only flag jika diberikan
-			if readOnly {
-				secProfile.ReadOnlyRootfs = true
+
+			// Container detached dibuat oleh daemon jika daemon berjalan,
+			// container foreground tetap dijalankan langsung oleh CLI
+			if opts.Detach && !opts.Interactive && !opts.TTY {
+				client, err := daemonClient(ctx)
+				if err != nil {
+					return err
+				}
+				if client != nil {
+					return remoteRun(ctx, client, opts, secProfile)
+				}
 			}
 			
-			return container.RunContainerWithSecurity(imageName, containerName, volumes, ports, memory, cpu, secProfile)
+			return exitError(container.RunContainerWithSecurity(opts, secProfile))
+		},
+	}
+}
+
+// exitError meneruskan exit code container sebagai exit code minidocker
+func exitError(err error) error {
+	var exitErr *container.ExitCodeError
+	if errors.As(err, &exitErr) {
+		return cli.Exit("", exitErr.Code)
+	}
+	return err
+}
+
+// resourceFlags mengembalikan flag resource limits untuk container. Default
+// memory dan CPU container baru diambil dari config.json, bukan dari flag.
+func resourceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "memory",
+			Aliases: []string{"m"},
+			Usage:   "Batasan memory (format: 64m, 128m, 256m)",
+		},
+		&cli.StringFlag{
+			Name:    "cpu",
+			Aliases: []string{"c"},
+			Usage:   "Batasan CPU dalam persentase (100 = 1 core, 250 = 2,5 core)",
+		},
+		&cli.StringFlag{
+			Name:  "cpus",
+			Usage: "Jumlah CPU, boleh pecahan (misalnya 1.5), menggantikan --cpu",
+		},
+		&cli.Uint64Flag{
+			Name:  "cpu-shares",
+			Usage: "Bobot CPU relatif (2-262144, default kernel 1024)",
+		},
+		&cli.StringFlag{
+			Name:  "cpuset-cpus",
+			Usage: "CPU yang boleh dipakai (misalnya 0-3 atau 0,1)",
+		},
+		&cli.StringFlag{
+			Name:  "cpuset-mems",
+			Usage: "Node memori NUMA yang boleh dipakai (misalnya 0-1)",
+		},
+		&cli.Int64Flag{
+			Name:  "pids-limit",
+			Usage: "Jumlah maksimum proses di container (-1 untuk tanpa batas)",
+		},
+		&cli.StringFlag{
+			Name:  "memory-swap",
+			Usage: "Batas memory ditambah swap (misalnya 128m, -1 untuk swap tanpa batas)",
+		},
+		&cli.StringFlag{
+			Name:  "memory-reservation",
+			Usage: "Batas memory lunak (misalnya 32m)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "device-read-bps",
+			Usage: "Batas kecepatan baca dari device (format: /dev/sda:10mb)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "device-write-bps",
+			Usage: "Batas kecepatan tulis ke device (format: /dev/sda:10mb)",
 		},
 	}
 }
 
+// mergeResourceFlags menimpa resources dengan flag resource yang diberikan
+// user. Flag yang tidak diberikan tidak mengubah nilai yang sudah ada.
+func mergeResourceFlags(ctx *cli.Context, resources *container.Resources) error {
+	if ctx.IsSet("cpu") && ctx.IsSet("cpus") {
+		return fmt.Errorf("--cpu dan --cpus tidak bisa dipakai bersamaan")
+	}
+
+	if ctx.IsSet("memory") {
+		resources.Memory = ctx.String("memory")
+	}
+	if ctx.IsSet("cpu") {
+		resources.CPU = ctx.String("cpu")
+		resources.NanoCPUs = 0
+	}
+	if ctx.IsSet("cpus") {
+		nanoCPUs, err := container.ParseCPUs(ctx.String("cpus"))
+		if err != nil {
+			return fmt.Errorf("--cpus: %v", err)
+		}
+		resources.NanoCPUs = nanoCPUs
+		resources.CPU = ""
+	}
+	if ctx.IsSet("cpu-shares") {
+		resources.CPUShares = ctx.Uint64("cpu-shares")
+	}
+	if ctx.IsSet("cpuset-cpus") {
+		resources.CpusetCpus = ctx.String("cpuset-cpus")
+	}
+	if ctx.IsSet("cpuset-mems") {
+		resources.CpusetMems = ctx.String("cpuset-mems")
+	}
+	if ctx.IsSet("pids-limit") {
+		resources.PidsLimit = ctx.Int64("pids-limit")
+	}
+
+	if ctx.IsSet("memory-swap") {
+		swap, err := container.ParseMemorySwap(ctx.String("memory-swap"))
+		if err != nil {
+			return fmt.Errorf("--memory-swap: %v", err)
+		}
+		resources.MemorySwap = swap
+	}
+	if ctx.IsSet("memory-reservation") {
+		reservation, err := container.ParseByteSize(ctx.String("memory-reservation"))
+		if err != nil {
+			return fmt.Errorf("--memory-reservation: %v", err)
+		}
+		resources.MemoryReservation = reservation
+	}
+
+	for _, value := range ctx.StringSlice("device-read-bps") {
+		device, err := container.ParseThrottleDevice(value)
+		if err != nil {
+			return fmt.Errorf("--device-read-bps: %v", err)
+		}
+		resources.DeviceReadBps = container.SetThrottleDevice(resources.DeviceReadBps, device)
+	}
+	for _, value := range ctx.StringSlice("device-write-bps") {
+		device, err := container.ParseThrottleDevice(value)
+		if err != nil {
+			return fmt.Errorf("--device-write-bps: %v", err)
+		}
+		resources.DeviceWriteBps = container.SetThrottleDevice(resources.DeviceWriteBps, device)
+	}
+	return nil
+}
+
+// parseEnvFlags mengubah nilai flag --env menjadi daftar KEY=VALUE.
+// Jika hanya KEY yang diberikan, nilainya diambil dari environment host.
+func parseEnvFlags(values []string) []string {
+	env := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.Contains(value, "=") {
+			env = append(env, value)
+			continue
+		}
+		if hostValue, ok := os.LookupEnv(value); ok {
+			env = append(env, value+"="+hostValue)
+		}
+	}
+	return env
+}
+
+// joinTmpfsFlags menyatukan kembali opsi --tmpfs yang dipecah urfave/cli di
+// koma, misalnya "/run:size=64m,mode=1777". Opsi tidak pernah diawali "/",
+// jadi nilai seperti itu adalah lanjutan dari nilai sebelumnya.
+func joinTmpfsFlags(values []string) []string {
+	var specs []string
+	for _, value := range values {
+		if len(specs) > 0 && !strings.HasPrefix(value, "/") {
+			specs[len(specs)-1] += "," + value
+			continue
+		}
+		specs = append(specs, value)
+	}
+	return specs
+}
+
 // ListCommand - Perintah untuk melihat daftar container
 func ListCommand() *cli.Command {
 	return &cli.Command{
 		Name:    "ps",
 		Aliases: []string{"list"},
-		Usage:   "Daftar semua container",
+		Usage:   "Daftar container yang berjalan",
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Tampilkan semua container, termasuk yang sudah berhenti",
+			},
+			&cli.BoolFlag{
+				Name:  "no-trunc",
+				Usage: "Tampilkan ID container lengkap",
+			},
+		}, formatFlags()...),
 		Action: func(ctx *cli.Context) error {
-			return container.ListContainers()
+			filters, err := container.ParseFilters(ctx.StringSlice("filter"))
+			if err != nil {
+				return err
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			var containers []container.ContainerSummary
+			if client != nil {
+				containers, err = remoteContainers(ctx, client, ctx.Bool("all"), filters)
+			} else {
+				containers, err = container.ListContainers(ctx.Bool("all"), filters)
+			}
+			if err != nil {
+				return err
+			}
+
+			if ctx.Bool("quiet") {
+				for _, c := range containers {
+					if ctx.Bool("no-trunc") {
+						fmt.Println(c.ID)
+					} else {
+						fmt.Println(c.ShortID())
+					}
+				}
+				return nil
+			}
+
+			idColumn := "{{.ShortID}}"
+			if ctx.Bool("no-trunc") {
+				idColumn = "{{.ID}}"
+			}
+			return printList(ctx.String("format"),
+				idColumn+"\t{{.Name}}\t{{.Image}}\t{{.Status}}\t{{.Pid}}\t{{.RestartCount}}\t{{join .Ports \", \"}}\t{{ago .CreatedAt}}",
+				containerHeaders, containers)
 		},
 	}
 }
 
+// containerHeaders adalah judul kolom tabel ps
+var containerHeaders = map[string]string{
+	"ID":           "CONTAINER ID",
+	"ShortID":      "CONTAINER ID",
+	"Name":         "NAME",
+	"Image":        "IMAGE",
+	"Command":      "COMMAND",
+	"State":        "STATE",
+	"Status":       "STATUS",
+	"ExitCode":     "EXIT CODE",
+	"Pid":          "PID",
+	"RestartCount": "RESTARTS",
+	"Ports":        "PORTS",
+	"CreatedAt":    "CREATED",
+}
+
 // StopCommand - Perintah untuk menghentikan container
 func StopCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "stop",
 		Usage: "Hentikan container yang sedang berjalan",
-		ArgsUsage: "CONTAINER_ID",
+		ArgsUsage: "[CONTAINER_ID...]",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "time",
+				Aliases: []string{"t"},
+				Usage:   "Detik menunggu container berhenti sebelum SIGKILL",
+				Value:   int(container.DefaultStopTimeout / time.Second),
+			},
+			labelFilterFlag(),
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.Int("time") < 0 {
+				return fmt.Errorf("--time tidak boleh negatif")
+			}
+			ids, err := containerTargets(ctx, false)
+			if err != nil {
+				return err
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			timeout := time.Duration(ctx.Int("time")) * time.Second
+			return forEachContainer(ids, func(id string) error {
+				if client != nil {
+					return client.ContainerStop(ctx.Context, id, ctx.Int("time"))
+				}
+				return container.StopContainer(id, timeout)
+			})
+		},
+	}
+}
+
+// KillCommand - Perintah untuk mengirim sinyal ke container
+func KillCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "kill",
+		Usage:     "Kirim sinyal ke proses utama container",
+		ArgsUsage: "CONTAINER_ID [CONTAINER_ID...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "signal",
+				Aliases: []string{"s"},
+				Usage:   "Nama atau nomor sinyal (misalnya SIGHUP, HUP, atau 1)",
+				Value:   "KILL",
+			},
+		},
 		Action: func(ctx *cli.Context) error {
 			if ctx.NArg() < 1 {
 				return fmt.Errorf("Diperlukan ID container")
 			}
-			containerId := ctx.Args().First()
-			return container.StopContainer(containerId)
+			if _, err := container.ParseSignal(ctx.String("signal")); err != nil {
+				return err
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			return forEachContainer(ctx.Args().Slice(), func(id string) error {
+				if client != nil {
+					return client.ContainerKill(ctx.Context, id, ctx.String("signal"))
+				}
+				return container.KillContainer(id, ctx.String("signal"))
+			})
+		},
+	}
+}
+
+// RemoveCommand - Perintah untuk menghapus container
+func RemoveCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "rm",
+		Usage:     "Hapus satu atau lebih container",
+		ArgsUsage: "[CONTAINER_ID...]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Paksa penghapusan container yang sedang berjalan (SIGKILL)",
+			},
+			labelFilterFlag(),
+		},
+		Action: func(ctx *cli.Context) error {
+			ids, err := containerTargets(ctx, true)
+			if err != nil {
+				return err
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			return forEachContainer(ids, func(id string) error {
+				if client != nil {
+					return client.ContainerRemove(ctx.Context, id, ctx.Bool("force"))
+				}
+				return container.RemoveContainer(id, ctx.Bool("force"))
+			})
+		},
+	}
+}
+
+// ResumeCommand - Perintah untuk menjalankan kembali container setelah boot
+func ResumeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "resume",
+		Usage: "Jalankan kembali container dengan restart policy always/unless-stopped (misalnya saat boot)",
+		Action: func(ctx *cli.Context) error {
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			var resumed []string
+			if client != nil {
+				report, err := client.ContainersResume(ctx.Context)
+				if err != nil {
+					return err
+				}
+				resumed = report.ContainersResumed
+			} else if resumed, err = container.ResumeContainers(); err != nil {
+				return err
+			}
+			for _, id := range resumed {
+				fmt.Println(id)
+			}
+			return nil
+		},
+	}
+}
+
+// ContainerCommand - Perintah untuk mengelola container
+func ContainerCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "container",
+		Usage: "Kelola container",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "prune",
+				Usage: "Hapus semua container yang sudah berhenti",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "force",
+						Aliases: []string{"f"},
+						Usage:   "Jangan minta konfirmasi",
+					},
+					&cli.StringSliceFlag{
+						Name:  "filter",
+						Usage: "Hanya hapus container dengan label tertentu (label=KEY[=VALUE] atau label!=KEY[=VALUE])",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					filters, err := container.ParseFilters(ctx.StringSlice("filter"))
+					if err != nil {
+						return err
+					}
+					if !ctx.Bool("force") && !confirm("Semua container yang sudah berhenti akan dihapus. Lanjutkan?") {
+						return nil
+					}
+
+					client, err := daemonClient(ctx)
+					if err != nil {
+						return err
+					}
+					var removed []string
+					if client != nil {
+						report, err := client.ContainersPrune(ctx.Context, filters)
+						if err != nil {
+							return err
+						}
+						removed = report.ContainersDeleted
+					} else if removed, err = container.PruneContainers(filters); err != nil {
+						return err
+					}
+					fmt.Println("Container yang dihapus:")
+					for _, id := range removed {
+						fmt.Println(id)
+					}
+					fmt.Printf("Total: %d container\n", len(removed))
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// confirm meminta konfirmasi y/N dari pengguna
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// PauseCommand - Perintah untuk membekukan container
+func PauseCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "pause",
+		Usage:     "Bekukan semua proses di container",
+		ArgsUsage: "CONTAINER_ID [CONTAINER_ID...]",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() < 1 {
+				return fmt.Errorf("Diperlukan ID container")
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			return forEachContainer(ctx.Args().Slice(), func(id string) error {
+				if client != nil {
+					return client.ContainerPause(ctx.Context, id)
+				}
+				return container.PauseContainer(id)
+			})
+		},
+	}
+}
+
+// UnpauseCommand - Perintah untuk melanjutkan container yang dibekukan
+func UnpauseCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "unpause",
+		Usage:     "Lanjutkan container yang di-pause",
+		ArgsUsage: "CONTAINER_ID [CONTAINER_ID...]",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() < 1 {
+				return fmt.Errorf("Diperlukan ID container")
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			return forEachContainer(ctx.Args().Slice(), func(id string) error {
+				if client != nil {
+					return client.ContainerUnpause(ctx.Context, id)
+				}
+				return container.UnpauseContainer(id)
+			})
+		},
+	}
+}
+
+// labelFilterFlag mengembalikan flag --filter untuk memilih container
+// berdasarkan label pada perintah yang menerima banyak container
+func labelFilterFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:  "filter",
+		Usage: "Pilih container dengan filter seperti pada ps, misalnya label=app=web",
+	}
+}
+
+// containerTargets menggabungkan ID dari argumen dengan container yang cocok
+// dengan --filter. Tanpa all, hanya container yang aktif yang dipilih filter.
+func containerTargets(ctx *cli.Context, all bool) ([]string, error) {
+	ids := ctx.Args().Slice()
+	if !ctx.IsSet("filter") {
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("Diperlukan ID container atau --filter")
+		}
+		return ids, nil
+	}
+
+	filters, err := container.ParseFilters(ctx.StringSlice("filter"))
+	if err != nil {
+		return nil, err
+	}
+	client, err := daemonClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var containers []container.ContainerSummary
+	if client != nil {
+		containers, err = remoteContainers(ctx, client, all, filters)
+	} else {
+		containers, err = container.ListContainers(all, filters)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		found := false
+		for _, id := range ids {
+			if id == c.ID || id == c.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+// forEachContainer menjalankan fn untuk setiap container dan mencetak ID
+// yang berhasil. Error dicetak per container tanpa menghentikan sisanya.
+func forEachContainer(ids []string, fn func(string) error) error {
+	var failed bool
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+			continue
+		}
+		fmt.Println(id)
+	}
+	if failed {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+// UpdateCommand - Perintah untuk mengubah resource limits container
+func UpdateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "update",
+		Usage:     "Ubah resource limits container tanpa restart",
+		ArgsUsage: "CONTAINER_ID [CONTAINER_ID...]",
+		Flags:     resourceFlags(),
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() < 1 {
+				return fmt.Errorf("Diperlukan ID container")
+			}
+			if ctx.NumFlags() == 0 {
+				return fmt.Errorf("Tidak ada resource limit yang diubah")
+			}
+
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				// Daemon hanya mengubah field yang diisi, jadi kirim flag
+				// yang di-set saja
+				var changed container.Resources
+				if err := mergeResourceFlags(ctx, &changed); err != nil {
+					return err
+				}
+				resources, err := remoteResources(changed)
+				if err != nil {
+					return err
+				}
+				return forEachContainer(ctx.Args().Slice(), func(id string) error {
+					_, err := client.ContainerUpdate(ctx.Context, id, resources)
+					return err
+				})
+			}
+
+			return forEachContainer(ctx.Args().Slice(), func(id string) error {
+				return container.UpdateContainerResources(id, func(r *container.Resources) error {
+					return mergeResourceFlags(ctx, r)
+				})
+			})
+		},
+	}
+}
+
+// StatsCommand - Perintah untuk melihat penggunaan resource container
+func StatsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "stats",
+		Usage:     "Tampilkan penggunaan CPU, memory, IO, dan PID container secara live",
+		ArgsUsage: "[CONTAINER_ID...]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-stream",
+				Usage: "Tampilkan satu kali lalu keluar",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Format output (table atau json)",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			format := ctx.String("format")
+			if format != "table" && format != "json" {
+				return fmt.Errorf("format %q tidak didukung, gunakan table atau json", format)
+			}
+
+			ids := ctx.Args().Slice()
+			if ctx.Bool("no-stream") {
+				stats, err := container.CollectContainerStats(ids, time.Second)
+				if err != nil {
+					return err
+				}
+				return printStats(stats, format, false)
+			}
+
+			// Ctrl+C menghentikan streaming dengan rapi
+			runCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return container.StreamContainerStats(runCtx, ids, time.Second, func(s []container.ContainerStats) error {
+				return printStats(s, format, true)
+			})
+		},
+	}
+}
+
+// printStats menampilkan statistik container sebagai tabel atau JSON.
+// Pada mode streaming, tabel dicetak ulang dari atas layar.
+func printStats(stats []container.ContainerStats, format string, stream bool) error {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		for _, s := range stats {
+			if err := enc.Encode(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if stream {
+		fmt.Print("\033[2J\033[H")
+	}
+	fmt.Printf("%-12s %-15s %-8s %-22s %-8s %-22s %s\n",
+		"ID", "NAME", "CPU %", "MEM USAGE / LIMIT", "MEM %", "BLOCK I/O", "PIDS")
+	for _, s := range stats {
+		fmt.Printf("%-12s %-15s %-8s %-22s %-8s %-22s %d\n",
+			s.ID, s.Name,
+			fmt.Sprintf("%.2f%%", s.CPUPercent),
+			utils.FormatBytes(s.MemoryUsage)+" / "+utils.FormatBytes(s.MemoryLimit),
+			fmt.Sprintf("%.2f%%", s.MemoryPercent),
+			utils.FormatBytes(s.BlockRead)+" / "+utils.FormatBytes(s.BlockWrite),
+			s.PidsCurrent)
+	}
+	return nil
+}
+
+// EventsCommand - Perintah untuk menampilkan event dari jurnal event
+func EventsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "events",
+		Usage: "Tampilkan event container, image, dan volume secara real-time",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Tampilkan event sejak waktu ini (RFC 3339, Unix timestamp, atau durasi seperti 10m)",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Berhenti setelah waktu ini",
+			},
+			&cli.StringSliceFlag{
+				Name:    "filter",
+				Aliases: []string{"f"},
+				Usage:   "Filter event (type, event, container, image, volume, network, label)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Format output: json atau Go template",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			filters, err := container.ParseFilters(ctx.StringSlice("filter"))
+			if err != nil {
+				return err
+			}
+			if err := container.ValidateEventFilters(filters); err != nil {
+				return err
+			}
+			now := time.Now()
+			since, err := container.ParseEventTime(ctx.String("since"), now)
+			if err != nil {
+				return fmt.Errorf("--since: %v", err)
+			}
+			until, err := container.ParseEventTime(ctx.String("until"), now)
+			if err != nil {
+				return fmt.Errorf("--until: %v", err)
+			}
+			print, err := eventPrinter(ctx.String("format"))
+			if err != nil {
+				return err
+			}
+
+			// Ctrl+C menghentikan streaming dengan rapi
+			runCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				return remoteEvents(runCtx, client, since, until, filters, print)
+			}
+
+			// Seperti Docker, tanpa --since dan --until hanya event baru
+			// yang ditampilkan
+			if since.IsZero() && until.IsZero() {
+				since = now
+			}
+			return container.Events(runCtx, container.EventOptions{
+				Since:   since,
+				Until:   until,
+				Filters: filters,
+				Follow:  until.IsZero() || until.After(now),
+			}, print)
+		},
+	}
+}
+
+// eventPrinter mengembalikan fungsi yang mencetak satu event sesuai
+// --format: kosong untuk format baris seperti Docker, json untuk satu
+// objek JSON per baris, atau Go template
+func eventPrinter(format string) (func(container.Event) error, error) {
+	switch format {
+	case "":
+		return func(e container.Event) error {
+			var attributes []string
+			for key, value := range e.Attributes {
+				attributes = append(attributes, key+"="+value)
+			}
+			sort.Strings(attributes)
+			line := fmt.Sprintf("%s %s %s %s", e.Time.Format("2006-01-02T15:04:05.000000000Z07:00"), e.Type, e.Action, e.ID)
+			if len(attributes) > 0 {
+				line += " (" + strings.Join(attributes, ", ") + ")"
+			}
+			fmt.Println(line)
+			return nil
+		}, nil
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		return func(e container.Event) error {
+			return enc.Encode(e)
+		}, nil
+	}
+
+	tmpl, err := parseTemplate(format, templateFuncs)
+	if err != nil {
+		return nil, err
+	}
+	return func(e container.Event) error {
+		if err := tmpl.Execute(os.Stdout, e); err != nil {
+			return fmt.Errorf("gagal menjalankan template: %v", err)
+		}
+		fmt.Println()
+		return nil
+	}, nil
+}
+
+// InspectCommand - Perintah untuk menampilkan detail container, image, atau volume
+func InspectCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "inspect",
+		Usage:     "Tampilkan detail lengkap container, image, atau volume dalam JSON",
+		ArgsUsage: "NAME|ID [NAME|ID...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Format output dengan Go template, misalnya '{{.State.Pid}}'",
+			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Batasi pencarian ke container, image, atau volume",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() < 1 {
+				return fmt.Errorf("minimal satu nama atau ID diperlukan")
+			}
+
+			kind := ctx.String("type")
+			switch kind {
+			case "", "container", "image", "volume":
+			default:
+				return fmt.Errorf("tipe %q tidak didukung, gunakan container, image, atau volume", kind)
+			}
+
+			var tmpl *template.Template
+			if format := ctx.String("format"); format != "" {
+				var err error
+				if tmpl, err = parseTemplate(format, templateFuncs); err != nil {
+					return err
+				}
+			}
+
+			var objects []interface{}
+			failed := false
+			for _, name := range ctx.Args().Slice() {
+				object, err := inspectObject(name, kind)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					failed = true
+					continue
+				}
+				objects = append(objects, object)
+			}
+
+			if tmpl != nil {
+				for _, object := range objects {
+					if err := tmpl.Execute(os.Stdout, object); err != nil {
+						return fmt.Errorf("gagal menjalankan template: %v", err)
+					}
+					fmt.Println()
+				}
+			} else {
+				if objects == nil {
+					objects = []interface{}{}
+				}
+				data, err := json.MarshalIndent(objects, "", "    ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+			}
+
+			if failed {
+				return cli.Exit("", 1)
+			}
+			return nil
 		},
 	}
 }
 
+// inspectObject mencari container, image, lalu volume dengan nama atau ID
+// tersebut. Jika kind diisi, hanya tipe itu yang dicari.
+func inspectObject(name, kind string) (interface{}, error) {
+	if kind == "" || kind == "container" {
+		if c, err := container.InspectContainer(name); err == nil {
+			return c, nil
+		} else if kind != "" || errors.Is(err, container.ErrAmbiguousID) {
+			return nil, err
+		}
+	}
+	if kind == "" || kind == "image" {
+		if img, err := container.InspectImage(name); err == nil {
+			return img, nil
+		} else if kind != "" {
+			return nil, err
+		}
+	}
+	if kind == "" || kind == "volume" {
+		if v, err := container.GetVolume(name); err == nil {
+			return v, nil
+		} else if kind != "" {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("tidak ada container, image, atau volume bernama %s", name)
+}
+
 // LogsCommand - Perintah untuk melihat logs container
 func LogsCommand() *cli.Command {
 	return &cli.Command{
@@ -145,6 +1131,13 @@ func LogsCommand() *cli.Command {
 			}
 			containerId := ctx.Args().First()
 			follow := ctx.Bool("follow")
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				return remoteLogs(ctx, client, containerId, follow)
+			}
 			return container.ContainerLogs(containerId, follow)
 		},
 	}
@@ -156,13 +1149,28 @@ func ExecCommand() *cli.Command {
 		Name:  "exec",
 		Usage: "Jalankan perintah di dalam container yang sedang berjalan",
 		ArgsUsage: "CONTAINER_ID COMMAND [ARGS...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "workdir",
+				Aliases: []string{"w"},
+				Usage:   "Direktori kerja di dalam container (default: /)",
+			},
+		},
 		Action: func(ctx *cli.Context) error {
 			if ctx.NArg() < 2 {
 				return fmt.Errorf("Diperlukan ID container dan perintah")
 			}
 			containerId := ctx.Args().First()
 			command := ctx.Args().Slice()[1:]
-			return container.ExecInContainer(containerId, command)
+			workDir := ctx.String("workdir")
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				return exitError(remoteExec(ctx, client, containerId, workDir, command))
+			}
+			return exitError(container.ExecInContainer(containerId, workDir, command))
 		},
 	}
 }
@@ -197,8 +1205,21 @@ func VolumeCreateCommand() *cli.Command {
 					labels[parts[0]] = parts[1]
 				}
 			}
+
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				volume, err := client.VolumeCreate(ctx.Context, api.VolumeCreateRequest{Name: name, Labels: labels})
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Volume %s berhasil dibuat di %s\n", volume.Name, volume.Mountpoint)
+				return nil
+			}
 			
-			_, err := container.CreateVolume(name, labels)
+			_, err = container.CreateVolume(name, labels)
 			return err
 		},
 	}
@@ -209,40 +1230,57 @@ func VolumeListCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "volume-list",
 		Usage: "Daftar semua volume",
+		Flags: formatFlags(),
 		Action: func(ctx *cli.Context) error {
-			volumes, err := container.ListVolumes()
+			filters, err := container.ParseFilters(ctx.StringSlice("filter"))
 			if err != nil {
 				return err
 			}
-			
-			fmt.Printf("%-20s %-20s %-40s %-20s\n", "VOLUME NAME", "DRIVER", "MOUNTPOINT", "CREATED")
-			for _, v := range volumes {
-				createdAgo := ""
-				if !v.CreatedAt.IsZero() {
-					createdAgo = fmt.Sprintf("%s ago", strings.TrimSpace(
-						strings.Replace(
-							strings.Replace(
-								v.CreatedAt.String(), 
-								v.CreatedAt.Format("15:04:05"), 
-								"", 
-								1,
-							), 
-							v.CreatedAt.Format("2006-01-02"), 
-							"", 
-							1,
-						),
-					))
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			var volumes []container.Volume
+			if client != nil {
+				resp, err := client.VolumeList(ctx.Context, filters)
+				if err != nil {
+					return err
+				}
+				for _, v := range resp.Volumes {
+					volumes = append(volumes, localVolume(v))
+				}
+			} else {
+				if volumes, err = container.ListVolumes(); err != nil {
+					return err
+				}
+				if volumes, err = container.FilterVolumes(volumes, filters); err != nil {
+					return err
 				}
-				
-				fmt.Printf("%-20s %-20s %-40s %-20s\n", 
-					v.Name, v.Driver, v.Mountpoint, createdAgo)
 			}
-			
-			return nil
+
+			if ctx.Bool("quiet") {
+				for _, v := range volumes {
+					fmt.Println(v.Name)
+				}
+				return nil
+			}
+			return printList(ctx.String("format"),
+				"{{.Name}}\t{{.Driver}}\t{{.Mountpoint}}\t{{ago .CreatedAt}}",
+				volumeHeaders, volumes)
 		},
 	}
 }
 
+// volumeHeaders adalah judul kolom tabel volume-list
+var volumeHeaders = map[string]string{
+	"ID":         "ID",
+	"Name":       "VOLUME NAME",
+	"Driver":     "DRIVER",
+	"Mountpoint": "MOUNTPOINT",
+	"CreatedAt":  "CREATED",
+	"Labels":     "LABELS",
+}
+
 // VolumeRemoveCommand - Perintah untuk menghapus volume
 func VolumeRemoveCommand() *cli.Command {
 	return &cli.Command{
@@ -262,6 +1300,17 @@ func VolumeRemoveCommand() *cli.Command {
 			}
 			volumeName := ctx.Args().First()
 			force := ctx.Bool("force")
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				if err := client.VolumeRemove(ctx.Context, volumeName, force); err != nil {
+					return err
+				}
+				fmt.Printf("Volume %s berhasil dihapus\n", volumeName)
+				return nil
+			}
 			return container.RemoveVolume(volumeName, force)
 		},
 	}
@@ -278,7 +1327,17 @@ func VolumeBackupCommand() *cli.Command {
 				return fmt.Errorf("Diperlukan nama volume dan path backup")
 			}
 			volumeName := ctx.Args().First()
-			backupPath := ctx.Args().Get(1)
+			backupPath, err := filepath.Abs(ctx.Args().Get(1))
+			if err != nil {
+				return err
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				return client.VolumeBackup(ctx.Context, volumeName, backupPath)
+			}
 			return container.BackupVolumeData(volumeName, backupPath)
 		},
 	}
@@ -295,7 +1354,17 @@ func VolumeRestoreCommand() *cli.Command {
 				return fmt.Errorf("Diperlukan nama volume dan path backup")
 			}
 			volumeName := ctx.Args().First()
-			backupPath := ctx.Args().Get(1)
+			backupPath, err := filepath.Abs(ctx.Args().Get(1))
+			if err != nil {
+				return err
+			}
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			if client != nil {
+				return client.VolumeRestore(ctx.Context, volumeName, backupPath)
+			}
 			return container.RestoreVolumeData(volumeName, backupPath)
 		},
 	}
@@ -360,44 +1429,49 @@ func ImagesCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "images",
 		Usage: "Daftar semua image",
+		Flags: formatFlags(),
 		Action: func(ctx *cli.Context) error {
-			images, err := container.ListImages()
+			filters, err := container.ParseFilters(ctx.StringSlice("filter"))
 			if err != nil {
 				return err
 			}
-			
-			fmt.Printf("%-30s %-15s %-15s %-25s\n", "REPOSITORY", "TAG", "SIZE", "CREATED")
-			for _, img := range images {
-				// Format created time
-				createdAgo := ""
-				if !img.CreatedAt.IsZero() {
-					createdAgo = fmt.Sprintf("%s ago", strings.TrimSpace(
-						strings.Replace(
-							strings.Replace(
-								img.CreatedAt.String(), 
-								img.CreatedAt.Format("15:04:05"), 
-								"", 
-								1,
-							), 
-							img.CreatedAt.Format("2006-01-02"), 
-							"", 
-							1,
-						),
-					))
+			client, err := daemonClient(ctx)
+			if err != nil {
+				return err
+			}
+			var images []container.ImageInfo
+			if client != nil {
+				images, err = remoteImages(ctx, client, filters)
+			} else if images, err = container.ListImages(); err == nil {
+				images, err = container.FilterImages(images, filters)
+			}
+			if err != nil {
+				return err
+			}
+
+			if ctx.Bool("quiet") {
+				for _, img := range images {
+					fmt.Println(img.Name + ":" + img.Tag)
 				}
-				
-				// Format size
-				sizeStr := fmt.Sprintf("%.2f MB", float64(img.Size)/(1024*1024))
-				
-				fmt.Printf("%-30s %-15s %-15s %-25s\n", 
-					img.Name, img.Tag, sizeStr, createdAgo)
+				return nil
 			}
-			
-			return nil
+			return printList(ctx.String("format"),
+				"{{.Name}}\t{{.Tag}}\t{{size .Size}}\t{{ago .CreatedAt}}",
+				imageHeaders, images)
 		},
 	}
 }
 
+// imageHeaders adalah judul kolom tabel images
+var imageHeaders = map[string]string{
+	"Name":      "REPOSITORY",
+	"Tag":       "TAG",
+	"Size":      "SIZE",
+	"Digest":    "DIGEST",
+	"CreatedAt": "CREATED",
+	"Labels":    "LABELS",
+}
+
 // TagCommand - Perintah untuk membuat tag image
 func TagCommand() *cli.Command {
 	return &cli.Command{
@@ -413,4 +1487,216 @@ func TagCommand() *cli.Command {
 			return container.TagImage(sourceImage, targetImage)
 		},
 	}
-} 
\ No newline at end of file
+} 
+// DaemonCommand - Perintah untuk menjalankan daemon minidockerd
+func DaemonCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "daemon",
+		Usage: "Jalankan minidockerd yang melayani Docker Engine API lewat Unix socket",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "host",
+				Aliases: []string{"H"},
+				Usage:   "Socket tempat API dilayani (default: " + api.DefaultSocket + ")",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			host := ctx.String("host")
+			if host == "" {
+				host = api.DefaultSocket
+			}
+			socket, err := api.SocketPath(host)
+			if err != nil {
+				return err
+			}
+
+			d := daemon.New(daemon.Options{
+				Socket:  socket,
+				PidFile: filepath.Join(currentConfig.ExecRoot, "minidockerd.pid"),
+				Config:  currentConfig,
+			})
+			return d.Run()
+		},
+	}
+}
+
+// SeccompCommand - Perintah untuk memeriksa profil seccomp
+func SeccompCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "seccomp",
+		Usage: "Kelola profil seccomp",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "check",
+				Usage:     "Validasi profil seccomp dan tampilkan program BPF hasil kompilasinya",
+				ArgsUsage: "PROFILE",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "arch",
+						Usage: "Arsitektur target, misalnya SCMP_ARCH_AARCH64 (default: arsitektur host)",
+					},
+					&cli.BoolFlag{
+						Name:    "quiet",
+						Aliases: []string{"q"},
+						Usage:   "Hanya validasi, tanpa menampilkan program BPF",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() < 1 {
+						return fmt.Errorf("Diperlukan nama atau path profil seccomp")
+					}
+					path, err := container.GetSeccompProfile(ctx.Args().First())
+					if err != nil {
+						return err
+					}
+					if path == "" {
+						fmt.Println("Profil unconfined tidak memasang filter seccomp")
+						return nil
+					}
+
+					profile, err := seccomp.LoadProfile(path)
+					if err != nil {
+						return err
+					}
+					arch := ctx.String("arch")
+					if arch == "" {
+						arch = seccomp.NativeArch()
+					}
+					prog, err := seccomp.Compile(profile, arch)
+					if err != nil {
+						return fmt.Errorf("gagal mengompilasi profil seccomp: %v", err)
+					}
+
+					fmt.Printf("Profil: %s\n", path)
+					fmt.Printf("Default action: %s\n", profile.DefaultAction)
+					fmt.Printf("Aturan: %d\n", len(profile.Syscalls))
+					fmt.Printf("Arsitektur: %s\n", arch)
+					fmt.Printf("Program BPF: %d instruksi\n", len(prog))
+					if unknown := profile.UnknownSyscalls(); len(unknown) > 0 {
+						fmt.Printf("Peringatan: syscall tidak dikenal diabaikan: %s\n", strings.Join(unknown, ", "))
+					}
+					if !ctx.Bool("quiet") {
+						fmt.Println()
+						fmt.Print(seccomp.Disassemble(prog))
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// SecurityProfileCommand - Perintah untuk melihat profil keamanan
+func SecurityProfileCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "security-profile",
+		Usage: "Lihat profil keamanan bawaan dan profil di profiles_dir",
+		Subcommands: []*cli.Command{
+			{
+				Name:    "ls",
+				Aliases: []string{"list"},
+				Usage:   "Daftar profil keamanan",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Format output: table, json, 'table TEMPLATE', atau Go template",
+					},
+					&cli.BoolFlag{
+						Name:    "quiet",
+						Aliases: []string{"q"},
+						Usage:   "Hanya tampilkan nama profil",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					profiles, err := container.ListSecurityProfiles()
+					if err != nil {
+						return err
+					}
+
+					if ctx.Bool("quiet") {
+						for _, p := range profiles {
+							fmt.Println(p.Name)
+						}
+					} else if err := printList(ctx.String("format"),
+						"{{.Name}}\t{{.Inherits}}\t{{.Source}}\t{{.Description}}",
+						securityProfileHeaders, profiles); err != nil {
+						return err
+					}
+
+					for _, p := range profiles {
+						if p.Error != "" {
+							fmt.Fprintf(os.Stderr, "Peringatan: profil %s tidak bisa dipakai: %s\n", p.Name, p.Error)
+						}
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "inspect",
+				Usage:     "Tampilkan isi profil keamanan setelah pewarisan dalam JSON",
+				ArgsUsage: "NAME [NAME...]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Format output dengan Go template, misalnya '{{.Capabilities}}'",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() < 1 {
+						return fmt.Errorf("minimal satu nama profil diperlukan")
+					}
+
+					var tmpl *template.Template
+					if format := ctx.String("format"); format != "" {
+						var err error
+						if tmpl, err = parseTemplate(format, templateFuncs); err != nil {
+							return err
+						}
+					}
+
+					profiles := []container.SecurityProfile{}
+					failed := false
+					for _, name := range ctx.Args().Slice() {
+						profile, err := container.GetSecurityProfile(name)
+						if err != nil {
+							fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+							failed = true
+							continue
+						}
+						profiles = append(profiles, profile)
+					}
+
+					if tmpl != nil {
+						for _, profile := range profiles {
+							if err := tmpl.Execute(os.Stdout, profile); err != nil {
+								return fmt.Errorf("gagal menjalankan template: %v", err)
+							}
+							fmt.Println()
+						}
+					} else {
+						data, err := json.MarshalIndent(profiles, "", "    ")
+						if err != nil {
+							return err
+						}
+						fmt.Println(string(data))
+					}
+
+					if failed {
+						return cli.Exit("", 1)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// securityProfileHeaders adalah judul kolom tabel security-profile ls
+var securityProfileHeaders = map[string]string{
+	"Name":        "NAME",
+	"Inherits":    "INHERITS",
+	"Description": "DESCRIPTION",
+	"Source":      "SOURCE",
+	"Error":       "ERROR",
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/config"
+)
+
+// currentConfig adalah konfigurasi yang dimuat oleh Setup
+var currentConfig = config.Default()
+
+// localOnly bernilai true jika CLI diarahkan ke direktori data tertentu
+// lewat --root, --exec-root, atau --config. Dalam kondisi ini daemon di
+// socket default tidak dipakai karena bisa saja melayani root yang lain.
+var localOnly bool
+
+// GlobalFlags mengembalikan flag yang berlaku untuk semua perintah
+func GlobalFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "Lokasi file konfigurasi",
+			Value:   config.DefaultFile,
+			EnvVars: []string{config.EnvConfig},
+		},
+		&cli.StringFlag{
+			Name:    "root",
+			Usage:   "Direktori data persisten (container, image, volume, registry) (default: " + config.DefaultRoot + ")",
+			EnvVars: []string{config.EnvRoot},
+		},
+		&cli.StringFlag{
+			Name:    "exec-root",
+			Usage:   "Direktori state runtime (default: " + config.DefaultExecRoot + ")",
+			EnvVars: []string{config.EnvExecRoot},
+		},
+		&cli.StringFlag{
+			Name:    "host",
+			Aliases: []string{"H"},
+			Usage:   "Socket daemon minidockerd (default: " + api.DefaultSocket + " jika daemon berjalan)",
+			EnvVars: []string{api.EnvHost},
+		},
+	}
+}
+
+// Setup memuat file konfigurasi dan mengarahkan direktori data sesuai
+// urutan prioritas: flag, environment variable, config.json, lalu default
+func Setup(ctx *cli.Context) error {
+	// Child internal-start dan internal-exec-init tidak mewarisi environment
+	// dan tidak memakai direktori data, semua konfigurasinya dikirim lewat
+	// init pipe
+	if cmd := ctx.Args().First(); cmd == "internal-start" || cmd == "internal-exec-init" {
+		return nil
+	}
+
+	cfg, err := config.Load(ctx.String("config"), ctx.IsSet("config"))
+	if err != nil {
+		return err
+	}
+	if ctx.IsSet("root") {
+		cfg.Root = ctx.String("root")
+	}
+	if ctx.IsSet("exec-root") {
+		cfg.ExecRoot = ctx.String("exec-root")
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Proses shim dijalankan ulang dari binary yang sama, jadi lokasi data
+	// diteruskan lewat environment agar shim memakai root yang sama
+	os.Setenv(config.EnvRoot, cfg.Root)
+	os.Setenv(config.EnvExecRoot, cfg.ExecRoot)
+	if ctx.IsSet("config") {
+		os.Setenv(config.EnvConfig, ctx.String("config"))
+	}
+
+	container.Configure(cfg)
+	currentConfig = cfg
+	localOnly = ctx.IsSet("root") || ctx.IsSet("exec-root") || ctx.IsSet("config")
+	return nil
+}
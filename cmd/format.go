@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// templateFuncs adalah fungsi tambahan untuk --format, sama seperti Docker
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// ago menampilkan waktu relatif, misalnya "3 minutes ago"
+	"ago": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return utils.HumanDuration(time.Since(t)) + " ago"
+	},
+	"size": func(size int64) string {
+		return utils.FormatBytes(uint64(size))
+	},
+}
+
+// headerFuncs menggantikan templateFuncs saat mencetak header tabel: setiap
+// fungsi hanya meneruskan argumen pertamanya, yaitu judul kolom
+var headerFuncs = func() template.FuncMap {
+	funcs := template.FuncMap{}
+	for name := range templateFuncs {
+		funcs[name] = func(v interface{}, _ ...interface{}) interface{} { return v }
+	}
+	return funcs
+}()
+
+// formatFlags mengembalikan flag --format, --filter, dan --quiet yang
+// dipakai semua perintah daftar
+func formatFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Format output: table, json, 'table TEMPLATE', atau Go template",
+		},
+		&cli.StringSliceFlag{
+			Name:    "filter",
+			Aliases: []string{"f"},
+			Usage:   "Filter output berdasarkan kondisi (KEY=VALUE)",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "Hanya tampilkan ID",
+		},
+	}
+}
+
+// parseTemplate mem-parse Go template dari --format. Escape \t dan \n
+// yang diketik di shell diubah menjadi tab dan newline.
+func parseTemplate(format string, funcs template.FuncMap) (*template.Template, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	tmpl, err := template.New("format").Funcs(funcs).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("format template tidak valid: %v", err)
+	}
+	return tmpl, nil
+}
+
+// printList menampilkan daftar objek sesuai --format:
+//   - "" atau "table": tabel dengan template bawaan defaultTable
+//   - "json": satu objek JSON per baris
+//   - "table TEMPLATE": tabel dengan kolom dari TEMPLATE
+//   - selain itu: Go template untuk setiap objek, tanpa header
+//
+// headers memetakan nama field ke judul kolom tabel.
+func printList[T any](format, defaultTable string, headers map[string]string, items []T) error {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	table := false
+	switch {
+	case format == "" || format == "table":
+		format, table = defaultTable, true
+	case strings.HasPrefix(format, "table "):
+		format, table = strings.TrimPrefix(format, "table "), true
+	}
+
+	tmpl, err := parseTemplate(format, templateFuncs)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if table {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+		defer tw.Flush()
+		out = tw
+
+		header, err := parseTemplate(format, headerFuncs)
+		if err != nil {
+			return err
+		}
+		// Kolom yang tidak punya judul (misalnya field bertingkat) dibiarkan
+		// kosong; jika header tidak bisa dibuat, tabel dicetak tanpa header
+		var buf bytes.Buffer
+		if err := header.Option("missingkey=zero").Execute(&buf, headers); err == nil {
+			fmt.Fprintln(out, buf.String())
+		}
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(out, item); err != nil {
+			return fmt.Errorf("gagal menjalankan template: %v", err)
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
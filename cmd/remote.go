@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// daemonClient mengembalikan client minidockerd jika perintah harus
+// dijalankan lewat daemon, atau nil jika perintah dijalankan langsung.
+// Dengan --host daemon wajib bisa dihubungi. Tanpa --host, socket default
+// dipakai hanya jika daemon menjawab dan CLI tidak diarahkan ke direktori
+// data lain.
+func daemonClient(ctx *cli.Context) (*api.Client, error) {
+	host := ctx.String("host")
+	explicit := host != ""
+	if !explicit {
+		if localOnly || !utils.Exists(api.DefaultSocket) {
+			return nil, nil
+		}
+		host = api.DefaultSocket
+	}
+
+	client, err := api.NewClient(host)
+	if err != nil {
+		return nil, err
+	}
+	pingCtx, cancel := context.WithTimeout(ctx.Context, time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx); err != nil {
+		if explicit {
+			return nil, err
+		}
+		// Socket sisa daemon yang sudah berhenti
+		return nil, nil
+	}
+	return client, nil
+}
+
+// remoteRun membuat dan menjalankan container detached lewat daemon
+func remoteRun(ctx *cli.Context, client *api.Client, opts container.RunOptions, secProfile container.SecurityProfile) error {
+	host := &api.HostConfig{
+		Binds:          opts.Volumes,
+		PortBindings:   map[string][]api.PortBinding{},
+		RestartPolicy:  api.RestartPolicy{Name: opts.RestartPolicy.Name, MaximumRetryCount: opts.RestartPolicy.MaximumRetryCount},
+		AutoRemove:     opts.AutoRemove,
+		ReadonlyRootfs: secProfile.ReadOnlyRootfs,
+		LogConfig:      api.LogConfig{Type: opts.LogDriver},
+		SecurityOpt:    append([]string{"profile=" + secProfile.Name}, secProfile.SecurityOpt...),
+		CapAdd:         secProfile.CapAdd,
+		CapDrop:        secProfile.CapDrop,
+		Tmpfs:          opts.Tmpfs,
+		Annotations:    opts.Annotations,
+	}
+	resources, err := remoteResources(opts.Resources)
+	if err != nil {
+		return err
+	}
+	host.Resources = resources
+	for _, port := range opts.Ports {
+		parts := strings.SplitN(port, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("format port tidak valid: %q (gunakan host-port:container-port)", port)
+		}
+		key := parts[1]
+		if !strings.Contains(key, "/") {
+			key += "/tcp"
+		}
+		host.PortBindings[key] = append(host.PortBindings[key], api.PortBinding{HostPort: parts[0]})
+	}
+
+	req := api.ContainerCreateRequest{
+		ContainerConfig: api.ContainerConfig{
+			Image:     opts.Image,
+			Cmd:       opts.Command,
+			Env:       opts.Env,
+			Labels:    opts.Labels,
+			Tty:       opts.TTY,
+			OpenStdin: opts.Interactive,
+		},
+		HostConfig: host,
+	}
+	created, err := client.ContainerCreate(ctx.Context, opts.Name, req)
+	if err != nil {
+		return err
+	}
+	if err := client.ContainerStart(ctx.Context, created.ID); err != nil {
+		return err
+	}
+	info, err := client.ContainerInspect(ctx.Context, created.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Container %s berhasil dibuat dan dijalankan dengan PID %d\n", created.ID, info.State.Pid)
+	fmt.Printf("Profil keamanan: %s\n", secProfile.Name)
+	return nil
+}
+
+// remoteResources mengubah resource limits menjadi format Docker. Field yang
+// kosong dibiarkan nol agar tidak mengubah nilai di daemon.
+func remoteResources(resources container.Resources) (api.Resources, error) {
+	res := api.Resources{
+		NanoCPUs:          resources.NanoCPUs,
+		CPUShares:         int64(resources.CPUShares),
+		CpusetCpus:        resources.CpusetCpus,
+		CpusetMems:        resources.CpusetMems,
+		MemorySwap:        resources.MemorySwap,
+		MemoryReservation: resources.MemoryReservation,
+	}
+	if resources.Memory != "" {
+		memory, err := container.ParseByteSize(resources.Memory)
+		if err != nil {
+			return res, fmt.Errorf("batas memory tidak valid: %v", err)
+		}
+		res.Memory = memory
+	}
+	if resources.CPU != "" && resources.NanoCPUs == 0 {
+		pct, err := strconv.ParseInt(resources.CPU, 10, 64)
+		if err != nil {
+			return res, fmt.Errorf("batas CPU tidak valid: %q", resources.CPU)
+		}
+		res.CPUPeriod = 100000
+		res.CPUQuota = pct * 1000
+	}
+	if resources.PidsLimit != 0 {
+		limit := resources.PidsLimit
+		res.PidsLimit = &limit
+	}
+	for _, d := range resources.DeviceReadBps {
+		res.BlkioDeviceReadBps = append(res.BlkioDeviceReadBps, api.ThrottleDevice{Path: d.Path, Rate: d.Rate})
+	}
+	for _, d := range resources.DeviceWriteBps {
+		res.BlkioDeviceWriteBps = append(res.BlkioDeviceWriteBps, api.ThrottleDevice{Path: d.Path, Rate: d.Rate})
+	}
+	return res, nil
+}
+
+// remoteContainers mengambil daftar container dari daemon dalam bentuk
+// yang sama dengan container.ListContainers
+func remoteContainers(ctx *cli.Context, client *api.Client, all bool, filters container.Filters) ([]container.ContainerSummary, error) {
+	list, err := client.ContainerList(ctx.Context, all, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []container.ContainerSummary{}
+	for _, c := range list {
+		info, err := client.ContainerInspect(ctx.Context, c.ID)
+		if err != nil {
+			continue
+		}
+
+		state := c.State
+		if state == "exited" {
+			state = container.StateStopped
+		}
+		summary := container.ContainerSummary{
+			ID:           c.ID,
+			Name:         strings.TrimPrefix(info.Name, "/"),
+			Image:        c.Image,
+			Command:      append([]string{info.Path}, info.Args...),
+			State:        state,
+			Status:       state,
+			ExitCode:     info.State.ExitCode,
+			Pid:          info.State.Pid,
+			RestartCount: info.RestartCount,
+			Ports:        []string{},
+			Labels:       c.Labels,
+			CreatedAt:    time.Unix(c.Created, 0),
+		}
+		if created, err := time.Parse(time.RFC3339Nano, info.Created); err == nil {
+			summary.CreatedAt = created
+		}
+		finished, _ := time.Parse(time.RFC3339Nano, info.State.FinishedAt)
+		if state == container.StateStopped && !finished.IsZero() {
+			summary.Status = fmt.Sprintf("%s (%d)", state, info.State.ExitCode)
+		}
+		for _, p := range c.Ports {
+			port := fmt.Sprintf("%d:%d", p.PublicPort, p.PrivatePort)
+			if p.Type != "" && p.Type != "tcp" {
+				port += "/" + p.Type
+			}
+			summary.Ports = append(summary.Ports, port)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// remoteLogs menampilkan log container dari daemon
+func remoteLogs(ctx *cli.Context, client *api.Client, id string, follow bool) error {
+	info, err := client.ContainerInspect(ctx.Context, id)
+	if err != nil {
+		return err
+	}
+	logs, err := client.ContainerLogs(ctx.Context, id, follow)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	if follow {
+		fmt.Printf("Menampilkan logs untuk container %s (CTRL+C untuk keluar):\n", id)
+	}
+	if info.Config != nil && info.Config.Tty {
+		_, err = io.Copy(os.Stdout, logs)
+		return err
+	}
+	return api.StdCopy(os.Stdout, os.Stderr, logs)
+}
+
+// remoteExec menjalankan perintah di container lewat daemon dan meneruskan
+// exit code-nya
+func remoteExec(ctx *cli.Context, client *api.Client, id, workDir string, command []string) error {
+	execID, err := client.ExecCreate(ctx.Context, id, api.ExecConfig{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		WorkingDir:   workDir,
+		Cmd:          command,
+	})
+	if err != nil {
+		return err
+	}
+
+	conn, err := client.ExecAttach(ctx.Context, execID, api.ExecStartConfig{})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		io.Copy(conn.Conn, os.Stdin)
+		conn.CloseWrite()
+	}()
+	if err := api.StdCopy(os.Stdout, os.Stderr, conn.Reader); err != nil {
+		return err
+	}
+
+	info, err := client.ExecInspect(ctx.Context, execID)
+	if err != nil {
+		return err
+	}
+	if info.ExitCode != 0 {
+		return &container.ExitCodeError{Code: info.ExitCode}
+	}
+	return nil
+}
+
+// remoteEvents menampilkan event dari daemon sampai until atau ctx selesai
+func remoteEvents(ctx context.Context, client *api.Client, since, until time.Time, filters container.Filters, fn func(container.Event) error) error {
+	stream, err := client.Events(ctx, since, until, filters)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	dec := json.NewDecoder(stream)
+	for {
+		var msg api.EventMessage
+		if err := dec.Decode(&msg); err != nil {
+			// Stream yang diputus karena Ctrl+C bukan error
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := fn(container.Event{
+			Type:       msg.Type,
+			Action:     msg.Action,
+			ID:         msg.Actor.ID,
+			Attributes: msg.Actor.Attributes,
+			Time:       time.Unix(0, msg.TimeNano),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// remoteImages mengambil daftar image dari daemon
+func remoteImages(ctx *cli.Context, client *api.Client, filters container.Filters) ([]container.ImageInfo, error) {
+	list, err := client.ImageList(ctx.Context, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	images := []container.ImageInfo{}
+	for _, img := range list {
+		for _, ref := range img.RepoTags {
+			name, tag := ref, "latest"
+			if idx := strings.LastIndex(ref, ":"); idx > 0 {
+				name, tag = ref[:idx], ref[idx+1:]
+			}
+			images = append(images, container.ImageInfo{
+				Name:      name,
+				Tag:       tag,
+				Size:      img.Size,
+				Digest:    img.ID,
+				CreatedAt: time.Unix(img.Created, 0),
+				Labels:    img.Labels,
+			})
+		}
+	}
+	return images, nil
+}
+
+// localVolume mengubah volume dari daemon ke bentuk container.Volume
+func localVolume(v api.Volume) container.Volume {
+	created, _ := time.Parse(time.RFC3339, v.CreatedAt)
+	return container.Volume{
+		ID:         v.Name,
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  created,
+		Labels:     v.Labels,
+	}
+}
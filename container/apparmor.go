@@ -0,0 +1,229 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/user/minidocker/pkg/config"
+)
+
+const (
+	// appArmorEnabledFile berisi "Y" jika AppArmor aktif di kernel
+	appArmorEnabledFile = "/sys/module/apparmor/parameters/enabled"
+	// appArmorProfilesFile mendaftar profil yang sudah dimuat, satu per
+	// baris dengan format "NAMA (MODE)"
+	appArmorProfilesFile = "/sys/kernel/security/apparmor/profiles"
+	// appArmorConfigDir adalah lokasi include bawaan apparmor_parser
+	appArmorConfigDir = "/etc/apparmor.d"
+)
+
+// AppArmorDir menyimpan profil AppArmor hasil render template sebelum
+// dimuat dengan apparmor_parser, diatur oleh Configure
+var AppArmorDir = filepath.Join(config.DefaultExecRoot, "apparmor")
+
+// appArmorTemplates adalah profil AppArmor yang dibuat minidocker sendiri.
+// Nilainya menandai profil restricted yang memakai aturan tambahan.
+var appArmorTemplates = map[string]bool{
+	"minidocker-default":    false,
+	"minidocker-restricted": true,
+}
+
+// appArmorTemplate diturunkan dari profil docker-default. Include hanya
+// ditulis jika file-nya ada, karena tidak semua distro memasangnya. Tanpa
+// tunables/global, variabel @{PROC} yang dipakai profil didefinisikan sendiri.
+var appArmorTemplate = template.Must(template.New("apparmor").Parse(`{{if .Tunables}}#include <tunables/global>
+{{else}}@{PROC}=/proc/
+{{end}}
+profile {{.Name}} flags=(attach_disconnected,mediate_deleted) {
+{{if .Abstractions}}  #include <abstractions/base>
+{{end}}
+  network,
+  capability,
+  file,
+  umount,
+
+  # Proses host boleh mengirim sinyal ke container, dan proses di dalam
+  # container boleh saling mengirim sinyal
+  signal (receive) peer=unconfined,
+  signal (send,receive) peer={{.Name}},
+
+  deny @{PROC}/* w,
+  deny @{PROC}/{[^1-9],[^1-9][^0-9],[^1-9s][^0-9y][^0-9s],[^1-9][^0-9][^0-9][^0-9/]*}/** w,
+  deny @{PROC}/sys/[^k]** w,
+  deny @{PROC}/sys/kernel/{?,??,[^s][^h][^m]**} w,
+  deny @{PROC}/sysrq-trigger rwklx,
+  deny @{PROC}/kcore rwklx,
+
+  deny mount,
+
+  deny /sys/[^f]*/** wklx,
+  deny /sys/f[^s]*/** wklx,
+  deny /sys/fs/[^c]*/** wklx,
+  deny /sys/fs/c[^g]*/** wklx,
+  deny /sys/fs/cg[^r]*/** wklx,
+  deny /sys/firmware/** rwklx,
+  deny /sys/devices/virtual/powercap/** rwklx,
+  deny /sys/kernel/security/** rwklx,
+{{if .Restricted}}
+  # Aturan tambahan profil restricted: tanpa socket raw dan packet, tanpa
+  # ptrace, dan /proc/sys serta /sys sepenuhnya read-only
+  deny network raw,
+  deny network packet,
+  deny ptrace (trace),
+  deny @{PROC}/sys/** w,
+  deny /sys/** w,
+{{end}}
+  ptrace (trace,read,tracedby,readby) peer={{.Name}},
+}
+`))
+
+// AppArmorEnabled memeriksa apakah AppArmor aktif di kernel host
+func AppArmorEnabled() bool {
+	data, err := os.ReadFile(appArmorEnabledFile)
+	if err != nil {
+		return false
+	}
+	if strings.TrimSpace(string(data)) != "Y" {
+		return false
+	}
+	_, err = os.Stat(appArmorProfilesFile)
+	return err == nil
+}
+
+// renderAppArmorProfile membuat isi profil AppArmor bawaan minidocker
+func renderAppArmorProfile(name string) ([]byte, error) {
+	restricted, ok := appArmorTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("profil AppArmor '%s' bukan profil bawaan minidocker", name)
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(appArmorConfigDir, path))
+		return err == nil
+	}
+
+	var buf bytes.Buffer
+	err := appArmorTemplate.Execute(&buf, struct {
+		Name         string
+		Restricted   bool
+		Tunables     bool
+		Abstractions bool
+	}{name, restricted, exists("tunables/global"), exists("abstractions/base")})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat profil AppArmor '%s': %v", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// appArmorProfileLoaded memeriksa apakah profil sudah dimuat ke kernel
+func appArmorProfileLoaded(name string) (bool, error) {
+	file, err := os.Open(appArmorProfilesFile)
+	if err != nil {
+		return false, fmt.Errorf("gagal membaca daftar profil AppArmor: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if loaded, _, _ := strings.Cut(scanner.Text(), " ("); loaded == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// ensureAppArmorProfile memastikan profil AppArmor container sudah dimuat
+// ke kernel dan mengembalikan nama profil yang harus dipakai child. Profil
+// bawaan minidocker dirender ulang dan dimuat dengan apparmor_parser jika
+// belum dimuat atau isinya berubah, sedangkan profil lain harus sudah
+// dimuat oleh user. Tanpa AppArmor di host hasilnya kosong sehingga
+// container berjalan tanpa AppArmor (ApplySecurityProfile memperingatkan).
+func ensureAppArmorProfile(name string) (string, error) {
+	if name == "" || name == "unconfined" || !AppArmorEnabled() {
+		return "", nil
+	}
+
+	loaded, err := appArmorProfileLoaded(name)
+	if err != nil {
+		return "", err
+	}
+	if _, generated := appArmorTemplates[name]; !generated {
+		if !loaded {
+			return "", fmt.Errorf("profil AppArmor '%s' belum dimuat ke kernel", name)
+		}
+		return name, nil
+	}
+
+	content, err := renderAppArmorProfile(name)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(AppArmorDir, name)
+	if current, err := os.ReadFile(path); loaded && err == nil && bytes.Equal(current, content) {
+		return name, nil
+	}
+
+	if err := os.MkdirAll(AppArmorDir, 0755); err != nil {
+		return "", fmt.Errorf("gagal membuat direktori profil AppArmor: %v", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("gagal menulis profil AppArmor '%s': %v", name, err)
+	}
+	// -K tidak menyimpan cache, -r mengganti profil yang sudah dimuat
+	if out, err := exec.Command("apparmor_parser", "-Kr", path).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("gagal memuat profil AppArmor '%s' dengan apparmor_parser: %v: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return name, nil
+}
+
+// applyAppArmorProfile meminta kernel memindahkan proses ke profil
+// AppArmor name pada exec berikutnya. Atribut hanya bisa ditulis oleh thread
+// itu sendiri, jadi /proc/thread-self dicoba lebih dulu. Kernel sebelum 5.8
+// belum memiliki direktori attr/apparmor.
+func applyAppArmorProfile(name string) error {
+	var lastErr error
+	for _, path := range []string{
+		"/proc/thread-self/attr/apparmor/exec",
+		"/proc/self/attr/apparmor/exec",
+		"/proc/thread-self/attr/exec",
+		"/proc/self/attr/exec",
+	} {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if os.IsNotExist(err) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("gagal membuka %s: %v", path, err)
+		}
+		_, err = file.Write([]byte("exec " + name))
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("gagal menulis %s: %v", path, err)
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// processAppArmorProfile membaca profil AppArmor yang sedang dipakai proses
+// pid, misalnya "minidocker-default (enforce)". String kosong berarti
+// AppArmor tidak aktif.
+func processAppArmorProfile(pid int) string {
+	// attr/current juga dipakai LSM lain seperti SELinux
+	if !AppArmorEnabled() {
+		return ""
+	}
+	for _, attr := range []string{"attr/apparmor/current", "attr/current"} {
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/%s", pid, attr))
+		if err == nil {
+			return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ExitCodeError dikembalikan ketika container yang dijalankan secara attached
+// berhenti dengan exit code bukan nol
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("container berhenti dengan exit code %d", e.Code)
+}
+
+// containerIO mengatur stdio container yang dijalankan secara attached.
+// File sisi container (stdin, stdout, stderr) diteruskan ke shim, sedangkan
+// CLI menyalin data antara terminal host dan sisi lainnya.
+type containerIO struct {
+	opts   RunOptions
+	log    *os.File
+	stdio  []*os.File
+	master *os.File
+	stdout *os.File
+	stderr *os.File
+
+	copies   sync.WaitGroup
+	cleanups []func()
+}
+
+// newContainerIO menyiapkan stdio untuk container attached
+func newContainerIO(opts RunOptions, logOutput *os.File) (*containerIO, error) {
+	cio := &containerIO{opts: opts, log: logOutput}
+
+	// Mode TTY: container memakai pty slave sebagai stdin, stdout, dan stderr
+	if opts.TTY {
+		master, slave, err := openPTY()
+		if err != nil {
+			return nil, err
+		}
+		cio.master = master
+		cio.stdio = []*os.File{slave, slave, slave}
+		return cio, nil
+	}
+
+	// Mode tanpa TTY: stdin langsung dari host jika interactive, output
+	// lewat pipe agar bisa ditulis ke terminal sekaligus ke file log
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka %s: %v", os.DevNull, err)
+	}
+	if opts.Interactive {
+		stdin.Close()
+		stdin = os.Stdin
+	}
+
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat pipe stdout: %v", err)
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdoutR.Close()
+		stdoutW.Close()
+		return nil, fmt.Errorf("gagal membuat pipe stderr: %v", err)
+	}
+
+	cio.stdout = stdoutR
+	cio.stderr = stderrR
+	cio.stdio = []*os.File{stdin, stdoutW, stderrW}
+	return cio, nil
+}
+
+// files mengembalikan stdin, stdout, dan stderr untuk proses container
+func (cio *containerIO) files() []*os.File {
+	return cio.stdio
+}
+
+// closeContainerSide menutup salinan file sisi container milik CLI setelah
+// diwariskan ke shim, sehingga EOF terdeteksi ketika container berhenti
+func (cio *containerIO) closeContainerSide() {
+	for _, f := range cio.stdio {
+		if f != os.Stdin {
+			f.Close()
+		}
+	}
+	cio.stdio = nil
+}
+
+// started dipanggil setelah proses container berjalan untuk mulai
+// meneruskan data antara terminal host dan container
+func (cio *containerIO) started(pid int) {
+	if process, err := os.FindProcess(pid); err == nil {
+		cio.cleanups = append(cio.cleanups, proxySignals(process, cio.opts.TTY))
+	}
+
+	if cio.master == nil {
+		cio.copyOutput(io.MultiWriter(os.Stdout, cio.log), cio.stdout)
+		cio.copyOutput(io.MultiWriter(os.Stderr, cio.log), cio.stderr)
+		return
+	}
+
+	if cio.opts.Interactive && isTerminal(os.Stdin) {
+		if restore, err := makeRawTerminal(os.Stdin); err == nil {
+			cio.cleanups = append(cio.cleanups, restore)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if isTerminal(os.Stdin) {
+		cio.cleanups = append(cio.cleanups, watchWindowSize(os.Stdin, cio.master))
+	}
+
+	// Read pada master berakhir dengan EIO ketika semua slave ditutup
+	cio.copyOutput(io.MultiWriter(os.Stdout, cio.log), cio.master)
+
+	if cio.opts.Interactive {
+		go io.Copy(cio.master, os.Stdin)
+	}
+}
+
+// copyOutput menyalin output container di background sampai EOF
+func (cio *containerIO) copyOutput(dst io.Writer, src io.Reader) {
+	cio.copies.Add(1)
+	go func() {
+		defer cio.copies.Done()
+		io.Copy(dst, src)
+	}()
+}
+
+// finish menunggu semua output container tersalin lalu memulihkan terminal
+func (cio *containerIO) finish() {
+	cio.copies.Wait()
+	cio.Close()
+}
+
+// Close memulihkan terminal host dan menutup file yang tersisa
+func (cio *containerIO) Close() {
+	for i := len(cio.cleanups) - 1; i >= 0; i-- {
+		cio.cleanups[i]()
+	}
+	cio.cleanups = nil
+
+	cio.closeContainerSide()
+	for _, f := range []*os.File{cio.master, cio.stdout, cio.stderr} {
+		if f != nil {
+			f.Close()
+		}
+	}
+	cio.master, cio.stdout, cio.stderr = nil, nil, nil
+}
+
+// proxySignals meneruskan sinyal yang diterima CLI ke proses container.
+// Pada mode TTY, Ctrl+C diteruskan oleh pty sebagai karakter sehingga
+// SIGINT tidak perlu diteruskan.
+func proxySignals(process *os.Process, tty bool) func() {
+	signals := []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
+	if !tty {
+		signals = append(signals, os.Interrupt)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, signals...)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
+
+// exitCodeFromError mengubah hasil cmd.Wait menjadi exit code ala shell.
+// Proses yang dihentikan sinyal mendapat exit code 128 + nomor sinyal.
+func exitCodeFromError(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return -1, fmt.Errorf("gagal menunggu container: %v", err)
+	}
+
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal()), nil
+	}
+	return exitErr.ExitCode(), nil
+}
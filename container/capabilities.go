@@ -0,0 +1,208 @@
+package container
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// capabilityNames adalah nama capability Linux tanpa prefix CAP_, diurutkan
+// sesuai nomornya di kernel (include/uapi/linux/capability.h)
+var capabilityNames = []string{
+	"CHOWN",              // 0
+	"DAC_OVERRIDE",       // 1
+	"DAC_READ_SEARCH",    // 2
+	"FOWNER",             // 3
+	"FSETID",             // 4
+	"KILL",               // 5
+	"SETGID",             // 6
+	"SETUID",             // 7
+	"SETPCAP",            // 8
+	"LINUX_IMMUTABLE",    // 9
+	"NET_BIND_SERVICE",   // 10
+	"NET_BROADCAST",      // 11
+	"NET_ADMIN",          // 12
+	"NET_RAW",            // 13
+	"IPC_LOCK",           // 14
+	"IPC_OWNER",          // 15
+	"SYS_MODULE",         // 16
+	"SYS_RAWIO",          // 17
+	"SYS_CHROOT",         // 18
+	"SYS_PTRACE",         // 19
+	"SYS_PACCT",          // 20
+	"SYS_ADMIN",          // 21
+	"SYS_BOOT",           // 22
+	"SYS_NICE",           // 23
+	"SYS_RESOURCE",       // 24
+	"SYS_TIME",           // 25
+	"SYS_TTY_CONFIG",     // 26
+	"MKNOD",              // 27
+	"LEASE",              // 28
+	"AUDIT_WRITE",        // 29
+	"AUDIT_CONTROL",      // 30
+	"SETFCAP",            // 31
+	"MAC_OVERRIDE",       // 32
+	"MAC_ADMIN",          // 33
+	"SYSLOG",             // 34
+	"WAKE_ALARM",         // 35
+	"BLOCK_SUSPEND",      // 36
+	"AUDIT_READ",         // 37
+	"PERFMON",            // 38
+	"BPF",                // 39
+	"CHECKPOINT_RESTORE", // 40
+}
+
+// allCapabilities adalah bitmask semua capability di capabilityNames
+var allCapabilities = uint64(1)<<len(capabilityNames) - 1
+
+// ParseCapability mengembalikan nomor capability. Nama boleh memakai prefix
+// CAP_ dan tidak membedakan huruf besar kecil, misalnya "CAP_NET_ADMIN" atau
+// "net_admin".
+func ParseCapability(name string) (int, error) {
+	normalized := strings.TrimPrefix(strings.ToUpper(name), "CAP_")
+	for i, capName := range capabilityNames {
+		if capName == normalized {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("capability tidak dikenal: %q", name)
+}
+
+// GetCapabilities menerjemahkan nama capability ke bitmask kernel (bit ke-n
+// adalah capability nomor n). "ALL" berarti semua capability, nama yang
+// tidak dikenal diabaikan.
+func GetCapabilities(caps []string) uint64 {
+	var result uint64
+	for _, name := range caps {
+		if strings.ToUpper(name) == "ALL" {
+			return allCapabilities
+		}
+		if n, err := ParseCapability(name); err == nil {
+			result |= 1 << uint(n)
+		}
+	}
+	return result
+}
+
+// CapabilityNames mengubah bitmask menjadi nama capability, diurutkan
+// sesuai nomornya. Bit yang tidak dikenal ditulis sebagai nomornya.
+func CapabilityNames(mask uint64) []string {
+	names := []string{}
+	for n := 0; n < 64; n++ {
+		if mask&(1<<uint(n)) == 0 {
+			continue
+		}
+		if n < len(capabilityNames) {
+			names = append(names, capabilityNames[n])
+		} else {
+			names = append(names, strconv.Itoa(n))
+		}
+	}
+	return names
+}
+
+// MergeCapabilities menerapkan --cap-add dan --cap-drop ke capability
+// profil seperti TweakCapabilities di Docker. "ALL" di cap-add memberi semua
+// capability kecuali yang ada di cap-drop, dan "ALL" di cap-drop hanya
+// menyisakan capability dari cap-add. Selain itu cap-drop diterapkan lebih
+// dulu sehingga capability yang ada di keduanya tetap ditambahkan.
+// Perubahannya dicatat di CapAdd dan CapDrop.
+func (p *SecurityProfile) MergeCapabilities(add, drop []string) error {
+	var addAll, dropAll bool
+	for _, name := range add {
+		if strings.ToUpper(name) == "ALL" {
+			addAll = true
+		} else if _, err := ParseCapability(name); err != nil {
+			return err
+		}
+	}
+	var dropNames []string
+	for _, name := range drop {
+		if strings.ToUpper(name) == "ALL" {
+			dropAll = true
+		} else if _, err := ParseCapability(name); err != nil {
+			return err
+		} else {
+			dropNames = append(dropNames, name)
+		}
+	}
+	if len(add) == 0 && len(drop) == 0 {
+		return nil
+	}
+
+	var mask uint64
+	switch {
+	case addAll:
+		mask = allCapabilities &^ GetCapabilities(dropNames)
+	case dropAll:
+		mask = GetCapabilities(add)
+	default:
+		mask = GetCapabilities(p.Capabilities)
+		mask &^= GetCapabilities(dropNames)
+		mask |= GetCapabilities(add)
+	}
+
+	p.Capabilities = CapabilityNames(mask)
+	p.CapAdd = add
+	p.CapDrop = drop
+	return nil
+}
+
+// CapabilitySets adalah capability yang benar-benar dimiliki proses
+type CapabilitySets struct {
+	Effective   []string `json:"effective"`
+	Permitted   []string `json:"permitted"`
+	Inheritable []string `json:"inheritable"`
+	Bounding    []string `json:"bounding"`
+	Ambient     []string `json:"ambient"`
+}
+
+// ContainerCapabilities membaca capability proses utama container yang
+// sedang berjalan dari /proc/<pid>/status
+func ContainerCapabilities(nameOrID string) (*CapabilitySets, error) {
+	container, err := getContainer(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if (container.Status != StateRunning && container.Status != StatePaused) || !isContainerActive(container) {
+		return nil, errorf(ErrConflict, "container %s tidak berjalan", nameOrID)
+	}
+	return processCapabilities(container.Pid)
+}
+
+// processCapabilities mem-parse field Cap* di /proc/<pid>/status
+func processCapabilities(pid int) (*CapabilitySets, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca status proses %d: %v", pid, err)
+	}
+	defer file.Close()
+
+	sets := &CapabilitySets{}
+	fields := map[string]*[]string{
+		"CapEff": &sets.Effective,
+		"CapPrm": &sets.Permitted,
+		"CapInh": &sets.Inheritable,
+		"CapBnd": &sets.Bounding,
+		"CapAmb": &sets.Ambient,
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		set, known := fields[key]
+		if !ok || !known {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca %s proses %d: %v", key, pid, err)
+		}
+		*set = CapabilityNames(mask)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gagal membaca status proses %d: %v", pid, err)
+	}
+	return sets, nil
+}
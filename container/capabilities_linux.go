@@ -0,0 +1,80 @@
+//go:build linux
+// +build linux
+
+package container
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	prCapbsetRead           = 23 // PR_CAPBSET_READ
+	prCapbsetDrop           = 24 // PR_CAPBSET_DROP
+	prCapAmbient            = 47 // PR_CAP_AMBIENT
+	prCapAmbientClearAll    = 4  // PR_CAP_AMBIENT_CLEAR_ALL
+	prSetNoNewPrivs         = 38 // PR_SET_NO_NEW_PRIVS
+	linuxCapabilityVersion3 = 0x20080522
+)
+
+// capHeader dan capData adalah struct __user_cap_header_struct dan
+// __user_cap_data_struct untuk capget/capset
+type capHeader struct {
+	version uint32
+	pid     int32
+}
+
+type capData struct {
+	effective   uint32
+	permitted   uint32
+	inheritable uint32
+}
+
+// applyCapabilities membatasi capability thread ini ke caps sebelum exec.
+// Seperti Docker, bounding, effective, dan permitted berisi caps, sedangkan
+// inheritable dan ambient dikosongkan agar proses non-root tidak mendapat
+// capability lewat file capability. Capability berlaku per thread, jadi
+// pemanggil harus mengunci goroutine ke thread yang melakukan exec.
+func applyCapabilities(caps []string) error {
+	mask := GetCapabilities(caps)
+
+	// Capability yang lebih baru dari kernel tidak bisa dipasang
+	var supported uint64
+	for n := 0; n < 64; n++ {
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prCapbsetRead, uintptr(n), 0); errno != 0 {
+			break
+		}
+		supported |= 1 << uint(n)
+	}
+
+	for n := 0; n < 64; n++ {
+		bit := uint64(1) << uint(n)
+		if supported&bit == 0 || mask&bit != 0 {
+			continue
+		}
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prCapbsetDrop, uintptr(n), 0); errno != 0 {
+			return fmt.Errorf("gagal menghapus %s dari bounding set: %v", CapabilityNames(bit)[0], errno)
+		}
+	}
+
+	// Kernel sebelum 4.3 belum mengenal ambient capability (EINVAL)
+	if _, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, prCapAmbient, prCapAmbientClearAll, 0, 0, 0, 0); errno != 0 && errno != syscall.EINVAL {
+		return fmt.Errorf("gagal mengosongkan ambient set: %v", errno)
+	}
+
+	header := capHeader{version: linuxCapabilityVersion3}
+	var data [2]capData
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CAPGET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return fmt.Errorf("capget: %v", errno)
+	}
+
+	// Capability yang tidak dimiliki proses ini tidak bisa diberikan
+	mask &= supported & (uint64(data[0].permitted) | uint64(data[1].permitted)<<32)
+	data[0] = capData{effective: uint32(mask), permitted: uint32(mask)}
+	data[1] = capData{effective: uint32(mask >> 32), permitted: uint32(mask >> 32)}
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return fmt.Errorf("capset: %v", errno)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package container
+
+import "fmt"
+
+// applyCapabilities tidak didukung di platform non-Linux
+func applyCapabilities(caps []string) error {
+	return fmt.Errorf("capabilities hanya didukung di Linux")
+}
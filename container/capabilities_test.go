@@ -0,0 +1,91 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeCapabilities(t *testing.T) {
+	base := []string{"CHOWN", "KILL", "NET_RAW"}
+	all := CapabilityNames(allCapabilities)
+	without := func(names []string, drop string) []string {
+		var result []string
+		for _, name := range names {
+			if name != drop {
+				result = append(result, name)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		add  []string
+		drop []string
+		want []string
+	}{
+		{
+			name: "tanpa perubahan",
+			want: base,
+		},
+		{
+			name: "drop lalu add",
+			add:  []string{"NET_ADMIN", "KILL"},
+			drop: []string{"KILL", "cap_net_raw"},
+			want: []string{"CHOWN", "KILL", "NET_ADMIN"},
+		},
+		{
+			name: "add ALL kecuali drop",
+			add:  []string{"ALL"},
+			drop: []string{"NET_RAW"},
+			want: without(all, "NET_RAW"),
+		},
+		{
+			name: "drop ALL hanya menyisakan add",
+			add:  []string{"NET_BIND_SERVICE"},
+			drop: []string{"ALL"},
+			want: []string{"NET_BIND_SERVICE"},
+		},
+		{
+			name: "drop ALL tanpa add",
+			drop: []string{"all"},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := SecurityProfile{Capabilities: base}
+			if err := profile.MergeCapabilities(tt.add, tt.drop); err != nil {
+				t.Fatalf("MergeCapabilities: %v", err)
+			}
+			if !reflect.DeepEqual(profile.Capabilities, tt.want) {
+				t.Errorf("capabilities = %v, ingin %v", profile.Capabilities, tt.want)
+			}
+		})
+	}
+
+	profile := SecurityProfile{Capabilities: base}
+	if err := profile.MergeCapabilities([]string{"NOT_A_CAP"}, nil); err == nil {
+		t.Error("MergeCapabilities dengan capability tidak dikenal berhasil, ingin error")
+	}
+}
+
+func TestSecurityProfileFileCapAddAll(t *testing.T) {
+	file := securityProfileFile{
+		CapAdd:  []string{"ALL"},
+		CapDrop: []string{"NET_RAW"},
+	}
+	profile, err := file.apply(SecurityProfile{Name: "default", Capabilities: []string{"CHOWN"}}, "custom", "/tmp/custom.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mask := GetCapabilities(profile.Capabilities)
+	raw, _ := ParseCapability("NET_RAW")
+	if mask != allCapabilities&^(1<<uint(raw)) {
+		t.Errorf("capabilities = %v, ingin semua kecuali NET_RAW", profile.Capabilities)
+	}
+	if profile.CapAdd != nil || profile.CapDrop != nil {
+		t.Errorf("cap_add/cap_drop file tercatat sebagai --cap-add/--cap-drop: %v %v", profile.CapAdd, profile.CapDrop)
+	}
+}
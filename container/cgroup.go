@@ -0,0 +1,349 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// cgroupRoot adalah lokasi mount hierarki cgroup di host
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupParent adalah cgroup induk untuk semua container minidocker
+const cgroupParent = "minidocker"
+
+// cgroupV1Controllers adalah hierarki cgroup v1 yang dipakai minidocker.
+// Di cgroup v2 semua controller berada dalam satu hierarki.
+var cgroupV1Controllers = []string{"memory", "cpu", "cpuacct", "pids", "cpuset", "blkio", "freezer"}
+
+// cgroup adalah cgroup milik satu container, misalnya /minidocker/<id>.
+// Path disimpan relatif terhadap root hierarki agar sama untuk v1 dan v2.
+type cgroup struct {
+	path string
+	v2   bool
+}
+
+// containerCgroupPath mengembalikan path cgroup untuk container
+func containerCgroupPath(containerID string) string {
+	return "/" + cgroupParent + "/" + containerID
+}
+
+// newCgroup membuat handle cgroup dan mendeteksi versi cgroup host
+func newCgroup(path string) *cgroup {
+	return &cgroup{path: path, v2: isCgroupV2()}
+}
+
+// isCgroupV2 mendeteksi apakah host memakai cgroup v2 (unified hierarchy).
+// Host hybrid yang masih memasang controller di v1 dianggap v1.
+func isCgroupV2() bool {
+	_, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	return err == nil
+}
+
+// dir mengembalikan direktori cgroup untuk controller tertentu
+func (cg *cgroup) dir(controller string) string {
+	if cg.v2 {
+		return filepath.Join(cgroupRoot, cg.path)
+	}
+	return filepath.Join(cgroupRoot, controller, cg.path)
+}
+
+// dirs mengembalikan semua direktori cgroup yang tersedia di host
+func (cg *cgroup) dirs() []string {
+	if cg.v2 {
+		return []string{cg.dir("")}
+	}
+
+	var dirs []string
+	for _, controller := range cgroupV1Controllers {
+		if _, err := os.Stat(filepath.Join(cgroupRoot, controller)); err == nil {
+			dirs = append(dirs, cg.dir(controller))
+		}
+	}
+	return dirs
+}
+
+// create membuat direktori cgroup beserta induknya
+func (cg *cgroup) create() error {
+	if cg.v2 {
+		return cg.createV2()
+	}
+
+	dirs := cg.dirs()
+	if len(dirs) == 0 {
+		return fmt.Errorf("hierarki cgroup tidak ditemukan di %s", cgroupRoot)
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("gagal membuat cgroup %s: %v", dir, err)
+		}
+	}
+
+	// cgroup v1 cpuset tidak bisa menerima proses sebelum cpus dan mems diisi
+	if _, err := os.Stat(cg.dir("cpuset")); err == nil {
+		if err := initCpuset(filepath.Join(cgroupRoot, "cpuset"), cg.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createV2 membuat cgroup v2 dan mengaktifkan controller yang dibutuhkan
+// di setiap induknya lewat cgroup.subtree_control
+func (cg *cgroup) createV2() error {
+	dir := cg.dir("")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("gagal membuat cgroup %s: %v", dir, err)
+	}
+
+	parent := cgroupRoot
+	for _, part := range strings.Split(strings.Trim(filepath.Dir(cg.path), "/"), "/") {
+		enableControllers(parent)
+		parent = filepath.Join(parent, part)
+	}
+	enableControllers(parent)
+	return nil
+}
+
+// enableControllers mengaktifkan controller untuk cgroup anak dari dir.
+// Controller yang tidak tersedia di kernel dilewati.
+func enableControllers(dir string) {
+	for _, controller := range []string{"cpu", "cpuset", "memory", "pids", "io"} {
+		os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("+"+controller), 0644)
+	}
+}
+
+// initCpuset menyalin cpuset.cpus dan cpuset.mems dari induk ke setiap
+// level cgroup yang masih kosong
+func initCpuset(root, path string) error {
+	dir := root
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		parent := dir
+		dir = filepath.Join(dir, part)
+		for _, file := range []string{"cpuset.cpus", "cpuset.mems"} {
+			current, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				return fmt.Errorf("gagal membaca %s: %v", file, err)
+			}
+			if strings.TrimSpace(string(current)) != "" {
+				continue
+			}
+			value, err := os.ReadFile(filepath.Join(parent, file))
+			if err != nil {
+				return fmt.Errorf("gagal membaca %s: %v", file, err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, file), value, 0644); err != nil {
+				return fmt.Errorf("gagal mengisi %s: %v", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addProcess memasukkan proses ke semua direktori cgroup container
+func (cg *cgroup) addProcess(pid int) error {
+	for _, dir := range cg.dirs() {
+		procsFile := filepath.Join(dir, "cgroup.procs")
+		if err := os.WriteFile(procsFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
+			return fmt.Errorf("gagal tambahkan pid ke cgroup %s: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+// applyResources menerapkan semua batas resource container ke cgroup
+func (cg *cgroup) applyResources(r Resources) error {
+	memBytes, err := parseMemoryLimit(r.Memory)
+	if err != nil {
+		return fmt.Errorf("format memory limit tidak valid: %v", err)
+	}
+	quota, err := r.cpuQuota()
+	if err != nil {
+		return fmt.Errorf("format CPU limit tidak valid: %v", err)
+	}
+
+	var writes []cgroupWrite
+	if cg.v2 {
+		writes = cg.resourceWritesV2(r, memBytes, quota)
+	} else {
+		writes = cg.resourceWritesV1(r, memBytes, quota)
+	}
+
+	for _, w := range writes {
+		if err := cg.write(w.controller, w.file, w.value); err != nil {
+			return fmt.Errorf("gagal menulis %s=%s: %v", w.file, w.value, err)
+		}
+	}
+
+	fmt.Fprintf(output, "Berhasil set resource limits: memory=%d bytes, cpu quota=%d/%d\n",
+		memBytes, quota, cpuPeriod)
+	return nil
+}
+
+// cgroupWrite adalah satu nilai yang ditulis ke file cgroup
+type cgroupWrite struct {
+	controller string
+	file       string
+	value      string
+}
+
+// resourceWritesV1 menyusun file cgroup v1 yang perlu ditulis. Urutan penting:
+// memory.limit_in_bytes harus ditulis sebelum memory.memsw.limit_in_bytes.
+func (cg *cgroup) resourceWritesV1(r Resources, memBytes, quota uint64) []cgroupWrite {
+	writes := []cgroupWrite{
+		{"memory", "memory.limit_in_bytes", strconv.FormatUint(memBytes, 10)},
+	}
+	if r.MemorySwap != 0 {
+		memsw := cgroupWrite{"memory", "memory.memsw.limit_in_bytes", strconv.FormatInt(r.MemorySwap, 10)}
+		// Saat update, memsw lama bisa lebih kecil dari memory baru sehingga
+		// harus dinaikkan lebih dulu
+		current, err := cg.read("memory", "memory.memsw.limit_in_bytes")
+		if value, perr := strconv.ParseUint(current, 10, 64); err == nil && perr == nil && value < memBytes {
+			writes = []cgroupWrite{memsw, writes[0]}
+		} else {
+			writes = append(writes, memsw)
+		}
+	}
+	if r.MemoryReservation > 0 {
+		writes = append(writes, cgroupWrite{"memory", "memory.soft_limit_in_bytes", strconv.FormatInt(r.MemoryReservation, 10)})
+	}
+
+	writes = append(writes,
+		cgroupWrite{"cpu", "cpu.cfs_period_us", strconv.Itoa(cpuPeriod)},
+		cgroupWrite{"cpu", "cpu.cfs_quota_us", strconv.FormatUint(quota, 10)},
+	)
+	if r.CPUShares > 0 {
+		writes = append(writes, cgroupWrite{"cpu", "cpu.shares", strconv.FormatUint(r.CPUShares, 10)})
+	}
+
+	writes = append(writes, cpusetWrites(r)...)
+	if r.PidsLimit != 0 {
+		writes = append(writes, cgroupWrite{"pids", "pids.max", pidsMax(r.PidsLimit)})
+	}
+
+	for _, device := range r.DeviceReadBps {
+		writes = append(writes, cgroupWrite{"blkio", "blkio.throttle.read_bps_device",
+			fmt.Sprintf("%d:%d %d", device.Major, device.Minor, device.Rate)})
+	}
+	for _, device := range r.DeviceWriteBps {
+		writes = append(writes, cgroupWrite{"blkio", "blkio.throttle.write_bps_device",
+			fmt.Sprintf("%d:%d %d", device.Major, device.Minor, device.Rate)})
+	}
+	return writes
+}
+
+// resourceWritesV2 menyusun file cgroup v2 yang perlu ditulis
+func (cg *cgroup) resourceWritesV2(r Resources, memBytes, quota uint64) []cgroupWrite {
+	writes := []cgroupWrite{
+		{"memory", "memory.max", strconv.FormatUint(memBytes, 10)},
+	}
+	// memory.swap.max hanya berisi swap, tanpa memory
+	if r.MemorySwap == -1 {
+		writes = append(writes, cgroupWrite{"memory", "memory.swap.max", "max"})
+	} else if r.MemorySwap > 0 {
+		writes = append(writes, cgroupWrite{"memory", "memory.swap.max", strconv.FormatUint(uint64(r.MemorySwap)-memBytes, 10)})
+	}
+	if r.MemoryReservation > 0 {
+		writes = append(writes, cgroupWrite{"memory", "memory.low", strconv.FormatInt(r.MemoryReservation, 10)})
+	}
+
+	writes = append(writes, cgroupWrite{"cpu", "cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriod)})
+	if r.CPUShares > 0 {
+		writes = append(writes, cgroupWrite{"cpu", "cpu.weight", strconv.FormatUint(cpuSharesToWeight(r.CPUShares), 10)})
+	}
+
+	writes = append(writes, cpusetWrites(r)...)
+	if r.PidsLimit != 0 {
+		writes = append(writes, cgroupWrite{"pids", "pids.max", pidsMax(r.PidsLimit)})
+	}
+
+	for _, device := range r.DeviceReadBps {
+		writes = append(writes, cgroupWrite{"io", "io.max",
+			fmt.Sprintf("%d:%d rbps=%d", device.Major, device.Minor, device.Rate)})
+	}
+	for _, device := range r.DeviceWriteBps {
+		writes = append(writes, cgroupWrite{"io", "io.max",
+			fmt.Sprintf("%d:%d wbps=%d", device.Major, device.Minor, device.Rate)})
+	}
+	return writes
+}
+
+// cpusetWrites menyusun nilai cpuset yang sama untuk cgroup v1 dan v2
+func cpusetWrites(r Resources) []cgroupWrite {
+	var writes []cgroupWrite
+	if r.CpusetCpus != "" {
+		writes = append(writes, cgroupWrite{"cpuset", "cpuset.cpus", r.CpusetCpus})
+	}
+	if r.CpusetMems != "" {
+		writes = append(writes, cgroupWrite{"cpuset", "cpuset.mems", r.CpusetMems})
+	}
+	return writes
+}
+
+// pidsMax mengubah --pids-limit menjadi nilai pids.max (-1 berarti tanpa batas)
+func pidsMax(limit int64) string {
+	if limit < 0 {
+		return "max"
+	}
+	return strconv.FormatInt(limit, 10)
+}
+
+// write menulis nilai ke file cgroup milik controller tertentu
+func (cg *cgroup) write(controller, file, value string) error {
+	return os.WriteFile(filepath.Join(cg.dir(controller), file), []byte(value), 0644)
+}
+
+// read membaca isi file cgroup milik controller tertentu
+func (cg *cgroup) read(controller, file string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(cg.dir(controller), file))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// oomKillCount membaca jumlah proses yang dihentikan OOM killer di cgroup.
+// cgroup v2 menyimpannya di memory.events, cgroup v1 di memory.oom_control.
+func (cg *cgroup) oomKillCount() int {
+	name := "memory.events"
+	if !cg.v2 {
+		name = "memory.oom_control"
+	}
+	file, err := os.Open(filepath.Join(cg.dir("memory"), name))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	return parseKeyedCount(file, "oom_kill")
+}
+
+// destroy menghapus semua direktori cgroup container. Proses yang baru
+// berhenti bisa membuat rmdir gagal dengan EBUSY sesaat, sehingga dicoba ulang.
+func (cg *cgroup) destroy() error {
+	for _, dir := range cg.dirs() {
+		var err error
+		for i := 0; i < 50; i++ {
+			err = os.Remove(dir)
+			if err == nil || os.IsNotExist(err) || !isBusy(err) {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("gagal menghapus cgroup %s: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+// isBusy mengecek apakah error berasal dari EBUSY
+func isBusy(err error) bool {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return pathErr.Err == syscall.EBUSY
+	}
+	return false
+}
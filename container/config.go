@@ -0,0 +1,70 @@
+package container
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/user/minidocker/image"
+	"github.com/user/minidocker/pkg/config"
+)
+
+var (
+	// RuntimeDir menyimpan file runtime per container (misalnya log shim)
+	// di bawah exec-root, diatur oleh Configure
+	RuntimeDir = filepath.Join(config.DefaultExecRoot, "containers")
+
+	// SeccompProfilesDir menyimpan profil seccomp, diatur oleh Configure
+	SeccompProfilesDir = config.DefaultSeccompDir
+
+	// SecurityProfilesDir menyimpan profil keamanan buatan user, diatur oleh
+	// Configure
+	SecurityProfilesDir = config.DefaultProfilesDir
+
+	// registryMirrors dicoba berurutan sebelum registry asal saat pull
+	registryMirrors []string
+
+	// output menerima pesan progres dan peringatan engine, diatur oleh SetOutput
+	output io.Writer = os.Stdout
+
+	// ShimBinary adalah binary minidocker yang dijalankan sebagai shim
+	// container. Program lain yang memakai paket ini sebagai library harus
+	// mengarahkannya ke binary minidocker.
+	ShimBinary = "/proc/self/exe"
+
+	// shimEnv meneruskan lokasi data ke proses shim, diatur oleh Configure.
+	// Direktori profil tidak perlu diteruskan karena profil seccomp
+	// container sudah di-resolve menjadi path saat container dibuat.
+	shimEnv []string
+)
+
+// Configure mengarahkan semua direktori data ke root dan exec-root dari
+// konfigurasi. Harus dipanggil sebelum operasi container apa pun.
+func Configure(cfg *config.Config) {
+	ContainerDir = filepath.Join(cfg.Root, "containers")
+	NamesDir = filepath.Join(cfg.Root, "names")
+	VolumeDir = filepath.Join(cfg.Root, "volumes")
+	RegistryDir = filepath.Join(cfg.Root, "registry")
+	EventsFile = filepath.Join(cfg.Root, "events.log")
+	image.SetRoot(cfg.Root)
+
+	RuntimeDir = filepath.Join(cfg.ExecRoot, "containers")
+	AppArmorDir = filepath.Join(cfg.ExecRoot, "apparmor")
+	SeccompProfilesDir = cfg.SeccompDir
+	SecurityProfilesDir = cfg.ProfilesDir
+	registryMirrors = cfg.RegistryMirrors
+	shimEnv = []string{config.EnvRoot + "=" + cfg.Root, config.EnvExecRoot + "=" + cfg.ExecRoot}
+}
+
+// SetOutput mengatur tujuan pesan progres dan peringatan engine (default
+// stdout). Program yang memakai paket ini sebagai library bisa membuangnya
+// dengan io.Discard.
+func SetOutput(w io.Writer) {
+	output = w
+	image.SetOutput(w)
+}
+
+// containerRuntimeDir mengembalikan direktori runtime container id
+func containerRuntimeDir(id string) string {
+	return filepath.Join(RuntimeDir, id)
+}
@@ -2,16 +2,20 @@ package container
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/user/minidocker/image"
+	"github.com/user/minidocker/pkg/config"
 	"github.com/user/minidocker/pkg/utils"
 )
 
@@ -20,20 +24,58 @@ type Container struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
 	Image     string    `json:"image"`
+	Command   []string  `json:"command"`
+	Env       []string  `json:"env"`
+	TTY       bool      `json:"tty"`
+	OpenStdin bool      `json:"open_stdin"`
 	Status    string    `json:"status"`
 	Pid       int       `json:"pid"`
+	ShimPid   int       `json:"shim_pid"`
 	CreatedAt time.Time `json:"created_at"`
 	Volumes   []string  `json:"volumes"`
 	Ports     []string  `json:"ports"`
-	Memory    string    `json:"memory"`
-	CPU       string    `json:"cpu"`
-	LogFile   string    `json:"log_file"`
+	Resources
+	LogFile   string `json:"log_file"`
+	LogDriver string `json:"log_driver,omitempty"`
+	// StopSignal adalah sinyal untuk stop, diambil dari konfigurasi image
+	StopSignal string          `json:"stop_signal,omitempty"`
+	Security   SecurityProfile `json:"security_profile"`
+	// Tmpfs memetakan path di container ke opsi mount tmpfs (--tmpfs)
+	Tmpfs map[string]string `json:"tmpfs,omitempty"`
+
+	// Labels dipakai untuk memilih container (misalnya --filter label=app=web).
+	// Annotations hanya metadata bebas dan tidak bisa difilter.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// AutoRemove menghapus container secara otomatis ketika berhenti (--rm)
+	AutoRemove       bool     `json:"auto_remove"`
+	AnonymousVolumes []string `json:"anonymous_volumes,omitempty"`
+	CgroupPath       string   `json:"cgroup_path,omitempty"`
+
+	// RestartPolicy dijalankan oleh shim. ManuallyStopped mencegah restart
+	// setelah container dihentikan dengan stop.
+	RestartPolicy   RestartPolicy `json:"restart_policy"`
+	RestartCount    int           `json:"restart_count"`
+	ManuallyStopped bool          `json:"manually_stopped"`
+
+	// State akhir proses, ditulis oleh shim ketika container berhenti
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	ExitCode   int       `json:"exit_code"`
+	OOMKilled  bool      `json:"oom_killed"`
 }
 
+// ContainerDir menyimpan metadata dan rootfs container, diatur oleh Configure
+var ContainerDir = filepath.Join(config.DefaultRoot, "containers")
+
 const (
-	ContainerDir = "/var/run/minidocker/containers"
+	StateCreated = "created"
 	StateStopped = "stopped"
 	StateRunning = "running"
+	StatePaused  = "paused"
+	// StateRestarting adalah container yang menunggu backoff sebelum restart
+	StateRestarting = "restarting"
 )
 
 // initContainerDir membuat direktori untuk menyimpan data container
@@ -44,327 +86,479 @@ func initContainerDir() error {
 	return nil
 }
 
-// RunContainer menjalankan container baru
+// RunOptions berisi parameter untuk menjalankan container baru
+type RunOptions struct {
+	Image   string
+	Name    string
+	Command []string
+	Env     []string
+	Volumes []string
+	Ports   []string
+	// Tmpfs memetakan path di container ke opsi mount tmpfs, misalnya
+	// "/run": "size=64m". Tetap writable walaupun rootfs read-only.
+	Tmpfs map[string]string
+	Resources
+
+	// Labels ditambahkan ke label yang diwarisi dari image
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// Detach menjalankan container di background dengan output hanya ke log
+	Detach bool
+	// Interactive menjaga stdin tetap terhubung ke container
+	Interactive bool
+	// TTY mengalokasikan pseudo-terminal di dalam container
+	TTY bool
+	// AutoRemove menghapus container beserta resource-nya ketika berhenti
+	AutoRemove bool
+	// RestartPolicy menentukan kapan container dijalankan ulang
+	RestartPolicy RestartPolicy
+	// LogDriver menentukan ke mana output container ditulis (file atau none)
+	LogDriver string
+}
+
+// RunContainer menjalankan container baru dengan profil keamanan default
 func RunContainer(imageName, containerName string, volumes []string, ports []string, memory string, cpu string) error {
-	if err := initContainerDir(); err != nil {
+	opts := RunOptions{
+		Image:   imageName,
+		Name:    containerName,
+		Volumes: volumes,
+		Ports:   ports,
+		Resources: Resources{
+			Memory: memory,
+			CPU:    cpu,
+		},
+	}
+	return RunContainerWithSecurity(opts, DefaultSecurityProfile())
+}
+
+// RunContainerWithSecurity menjalankan container dengan profil keamanan tertentu
+func RunContainerWithSecurity(opts RunOptions, secProfile SecurityProfile) error {
+	container, err := CreateContainer(opts, secProfile)
+	if err != nil {
 		return err
 	}
 
-	// Buat ID container unik jika nama tidak diberikan
-	containerID := containerName
-	if containerID == "" {
-		containerID = utils.GenerateID(8)
-	}
+	// Mode detached: shim menulis output container ke file log
+	if opts.Detach {
+		shim, err := startShim(container, nil)
+		if err != nil {
+			return err
+		}
+		shim.Close()
 
-	// Buat direktori root container
-	containerRootDir := filepath.Join(ContainerDir, containerID)
-	if err := os.MkdirAll(containerRootDir, 0755); err != nil {
-		return fmt.Errorf("gagal membuat direktori container: %v", err)
+		fmt.Fprintf(output, "Container %s berhasil dibuat dan dijalankan dengan PID %d\n", container.ID, shim.pid)
+		fmt.Fprintf(output, "Profil keamanan: %s\n", secProfile.Name)
+		return nil
 	}
 
-	// Buat file log
-	logFile := filepath.Join(containerRootDir, "container.log")
-	logFd, err := os.Create(logFile)
+	// Siapkan stdio sesuai mode interactive dan TTY
+	logOutput, err := openContainerLog(container)
 	if err != nil {
-		return fmt.Errorf("gagal membuat file log: %v", err)
-	}
-	logFd.Close()
-
-	// Ekstrak image
-	rootfs := filepath.Join(containerRootDir, "rootfs")
-	if err := os.MkdirAll(rootfs, 0755); err != nil {
-		return fmt.Errorf("gagal membuat rootfs: %v", err)
+		return err
 	}
+	defer logOutput.Close()
 
-	// Ekstrak image ke rootfs
-	if err := image.ExtractImage(imageName, rootfs); err != nil {
-		return fmt.Errorf("gagal ekstrak image: %v", err)
+	cio, err := newContainerIO(opts, logOutput)
+	if err != nil {
+		return err
 	}
+	defer cio.Close()
 
-	// Setup port mapping jika diberikan
-	if len(ports) > 0 {
-		if err := setupPortMapping(ports); err != nil {
-			fmt.Printf("Warning: gagal setup port mapping: %v\n", err)
-		}
+	shim, err := startShim(container, cio)
+	if err != nil {
+		return err
 	}
+	defer shim.Close()
 
-	// Fork child process dengan namespace baru
-	cmd := exec.Command("/proc/self/exe", "internal-start", rootfs)
+	fmt.Fprintf(output, "Container %s berhasil dibuat dan dijalankan dengan PID %d\n", container.ID, shim.pid)
+	fmt.Fprintf(output, "Profil keamanan: %s\n", secProfile.Name)
 
-	// Setup namespaces
-	// Catatan: Ini hanya bekerja di Linux
-	// Di Windows dan OS lain, kode ini diabaikan
-	if utils.IsLinux() {
-		cmd.SysProcAttr = createLinuxSysProcAttr()
-	}
-	
-	// Redirect output ke file log
-	logOutput, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0644)
+	// Mode attached: teruskan stdio lalu tunggu container selesai
+	cio.started(shim.pid)
+	exitCode, err := shim.wait()
+	cio.finish()
 	if err != nil {
-		return fmt.Errorf("gagal membuka file log: %v", err)
+		return err
 	}
-	defer logOutput.Close()
 
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = logOutput
-	cmd.Stderr = logOutput
-
-	// Set environment variables
-	cmd.Env = append(os.Environ(), 
-		fmt.Sprintf("MINIDOCKER_MEMORY=%s", memory),
-		fmt.Sprintf("MINIDOCKER_CPU=%s", cpu),
-	)
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("gagal menjalankan container: %v", err)
+	if exitCode != 0 {
+		return &ExitCodeError{Code: exitCode}
 	}
+	return nil
+}
 
-	// Tulis metadata container
-	container := Container{
-		ID:        containerID,
-		Name:      containerName,
-		Image:     imageName,
-		Status:    StateRunning,
-		Pid:       cmd.Process.Pid,
-		CreatedAt: time.Now(),
-		Volumes:   volumes,
-		Ports:     ports,
-		Memory:    memory,
-		CPU:       cpu,
-		LogFile:   logFile,
+// validateRunOptions memeriksa opsi container yang tidak bergantung pada
+// image maupun state container lain
+func validateRunOptions(opts RunOptions) error {
+	if err := validateResources(opts.Resources); err != nil {
+		return err
 	}
-
-	containerJSON, err := json.Marshal(container)
-	if err != nil {
-		return fmt.Errorf("gagal menyimpan metadata container: %v", err)
+	if err := config.ValidateLogDriver(opts.LogDriver); err != nil {
+		return err
 	}
-
-	if err := ioutil.WriteFile(
-		filepath.Join(containerRootDir, "config.json"),
-		containerJSON,
-		0644,
-	); err != nil {
-		return fmt.Errorf("gagal menulis config.json: %v", err)
+	if err := ValidateTmpfs(opts.Tmpfs); err != nil {
+		return err
+	}
+	if opts.Detach && (opts.Interactive || opts.TTY) {
+		return fmt.Errorf("mode detached tidak bisa digabung dengan --interactive atau --tty")
+	}
+	if opts.AutoRemove && opts.RestartPolicy.Name != "" && opts.RestartPolicy.Name != RestartNo {
+		return fmt.Errorf("AutoRemove (--rm) tidak bisa digabung dengan restart policy %s", opts.RestartPolicy)
 	}
-
-	fmt.Printf("Container %s berhasil dibuat dan dijalankan dengan PID %d\n", containerID, cmd.Process.Pid)
 	return nil
 }
 
-// RunContainerWithSecurity menjalankan container dengan profil keamanan tertentu
-func RunContainerWithSecurity(imageName, containerName string, volumes []string, ports []string, memory string, cpu string, secProfile SecurityProfile) error {
-	if err := initContainerDir(); err != nil {
-		return err
+// CreateContainer menyiapkan rootfs dan metadata container tanpa menjalankannya
+func CreateContainer(opts RunOptions, secProfile SecurityProfile) (_ *Container, err error) {
+	// Validasi opsi sebelum membuat apa pun
+	if opts.LogDriver == "" {
+		opts.LogDriver = config.LogDriverFile
+	}
+	if err := validateRunOptions(opts); err != nil {
+		return nil, errorf(ErrInvalidParameter, "%v", err)
 	}
 
-	// Buat ID container unik jika nama tidak diberikan
-	containerID := containerName
-	if containerID == "" {
-		containerID = utils.GenerateID(8)
+	// Nama profil seccomp di-resolve dengan seccomp_dir pemanggil, karena
+	// shim yang memasang filter bisa saja memuat config.json yang lain
+	if secProfile.SeccompProfile != "" {
+		seccompPath, err := GetSeccompProfile(secProfile.SeccompProfile)
+		if err != nil {
+			return nil, errorf(ErrInvalidParameter, "%v", err)
+		}
+		if seccompPath != "" {
+			if seccompPath, err = filepath.Abs(seccompPath); err != nil {
+				return nil, fmt.Errorf("gagal menentukan path profil seccomp: %v", err)
+			}
+		}
+		secProfile.SeccompPath = seccompPath
 	}
 
-	// Buat direktori root container
+	if err := initContainerDir(); err != nil {
+		return nil, err
+	}
+
+	// ID selalu dibuat acak; nama hanya alias yang didaftarkan di indeks nama
+	containerID := utils.GenerateID(64)
+
+	// Buat direktori root container sebelum nama didaftarkan, sehingga
+	// reservasi nama yang masih berjalan tidak pernah terlihat basi
 	containerRootDir := filepath.Join(ContainerDir, containerID)
-	if err := os.MkdirAll(containerRootDir, 0755); err != nil {
-		return fmt.Errorf("gagal membuat direktori container: %v", err)
+	if err := os.Mkdir(containerRootDir, 0755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori container: %v", err)
+	}
+	if opts.Name != "" {
+		if err := reserveName(opts.Name, containerID); err != nil {
+			os.RemoveAll(containerRootDir)
+			return nil, err
+		}
 	}
 
-	// Buat file log
-	logFile := filepath.Join(containerRootDir, "container.log")
-	logFd, err := os.Create(logFile)
-	if err != nil {
-		return fmt.Errorf("gagal membuat file log: %v", err)
+	// Container yang gagal dibuat tidak meninggalkan direktori maupun nama
+	defer func() {
+		if err != nil {
+			os.RemoveAll(containerRootDir)
+			releaseName(opts.Name, containerID)
+		}
+	}()
+
+	// Buat file log, kecuali output container dibuang
+	logFile := ""
+	if opts.LogDriver == config.LogDriverFile {
+		logFile = filepath.Join(containerRootDir, "container.log")
+		logFd, err := os.Create(logFile)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuat file log: %v", err)
+		}
+		logFd.Close()
 	}
-	logFd.Close()
 
 	// Ekstrak image
 	rootfs := filepath.Join(containerRootDir, "rootfs")
 	if err := os.MkdirAll(rootfs, 0755); err != nil {
-		return fmt.Errorf("gagal membuat rootfs: %v", err)
+		return nil, fmt.Errorf("gagal membuat rootfs: %v", err)
 	}
 
 	// Ekstrak image ke rootfs
-	if err := image.ExtractImage(imageName, rootfs); err != nil {
-		return fmt.Errorf("gagal ekstrak image: %v", err)
+	if err := image.ExtractImage(opts.Image, rootfs); err != nil {
+		if _, statErr := os.Stat(image.ArchivePath(opts.Image)); os.IsNotExist(statErr) {
+			return nil, errorf(ErrNotFound, "image '%s' tidak ditemukan: %v", opts.Image, err)
+		}
+		return nil, fmt.Errorf("gagal ekstrak image: %v", err)
 	}
 
-	// Setup port mapping jika diberikan
-	if len(ports) > 0 {
-		if err := setupPortMapping(ports); err != nil {
-			fmt.Printf("Warning: gagal setup port mapping: %v\n", err)
-		}
+	// Tentukan perintah dan environment dari argumen user atau konfigurasi image
+	imgConfig, err := image.ReadImageConfig(rootfs)
+	if err != nil {
+		fmt.Fprintf(output, "Warning: gagal membaca konfigurasi image: %v\n", err)
 	}
-	
-	// Terapkan profil keamanan
-	if err := ApplySecurityProfile(secProfile, containerID); err != nil {
-		fmt.Printf("Warning: gagal menerapkan profil keamanan: %v\n", err)
+	command := opts.Command
+	if len(command) == 0 {
+		command = imgConfig.Cmd
 	}
-
-	// Jika read-only filesystem, atur
-	if secProfile.ReadOnlyRootfs {
-		fmt.Printf("Setting up read-only rootfs untuk container %s\n", containerID)
-		// Di implementasi nyata, ini akan menggunakan mount dengan opsi ro
+	if len(command) == 0 {
+		return nil, fmt.Errorf("tidak ada perintah yang dijalankan: image %s tidak memiliki Cmd", opts.Image)
 	}
-
-	// Fork child process dengan namespace baru
-	cmd := exec.Command("/proc/self/exe", "internal-start", rootfs)
-
-	// Setup namespaces
-	// Catatan: Ini hanya bekerja di Linux
-	// Di Windows dan OS lain, kode ini diabaikan
-	if utils.IsLinux() {
-		cmd.SysProcAttr = createLinuxSysProcAttr()
+	env := mergeEnv(imgConfig.Env, opts.Env)
+	if imgConfig.StopSignal != "" {
+		if _, err := ParseSignal(imgConfig.StopSignal); err != nil {
+			fmt.Fprintf(output, "Warning: StopSignal image diabaikan: %v\n", err)
+			imgConfig.StopSignal = ""
+		}
 	}
-	
-	// Redirect output ke file log
-	logOutput, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("gagal membuka file log: %v", err)
+	if opts.TTY {
+		env = mergeEnv([]string{"TERM=xterm"}, env)
 	}
-	defer logOutput.Close()
 
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = logOutput
-	cmd.Stderr = logOutput
+	labels := mergeLabels(imageLabels(opts.Image), imgConfig.Labels, opts.Labels)
 
-	// Set environment variables
-	cmd.Env = append(os.Environ(), 
-		fmt.Sprintf("MINIDOCKER_MEMORY=%s", memory),
-		fmt.Sprintf("MINIDOCKER_CPU=%s", cpu),
-		fmt.Sprintf("MINIDOCKER_SECCOMP=%s", secProfile.SeccompProfile),
-		fmt.Sprintf("MINIDOCKER_APPARMOR=%s", secProfile.AppArmorProfile),
-		fmt.Sprintf("MINIDOCKER_NO_NEW_PRIVS=%t", secProfile.NoNewPrivs),
-		fmt.Sprintf("MINIDOCKER_READONLY=%t", secProfile.ReadOnlyRootfs),
-	)
+	// Volume tanpa sumber (misalnya -v /data) menjadi anonymous volume
+	volumes, anonymousVolumes, err := prepareVolumes(opts.Volumes)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("gagal menjalankan container: %v", err)
+	// Terapkan profil keamanan
+	if err := ApplySecurityProfile(secProfile, containerID); err != nil {
+		fmt.Fprintf(output, "Warning: gagal menerapkan profil keamanan: %v\n", err)
 	}
 
 	// Tulis metadata container
-	container := Container{
+	container := &Container{
 		ID:        containerID,
-		Name:      containerName,
-		Image:     imageName,
-		Status:    StateRunning,
-		Pid:       cmd.Process.Pid,
+		Name:      opts.Name,
+		Image:     opts.Image,
+		Command:   command,
+		Env:       env,
+		TTY:       opts.TTY,
+		OpenStdin: opts.Interactive,
+		Status:    StateCreated,
 		CreatedAt: time.Now(),
 		Volumes:   volumes,
-		Ports:     ports,
-		Memory:    memory,
-		CPU:       cpu,
+		Ports:     opts.Ports,
+		Resources: opts.Resources,
 		LogFile:   logFile,
+		LogDriver: opts.LogDriver,
+		Security:  secProfile,
+		Tmpfs:     opts.Tmpfs,
+
+		Labels:      labels,
+		Annotations: opts.Annotations,
+
+		StopSignal:    imgConfig.StopSignal,
+		RestartPolicy: opts.RestartPolicy,
+
+		AutoRemove:       opts.AutoRemove,
+		AnonymousVolumes: anonymousVolumes,
+	}
+
+	if err := saveContainer(container); err != nil {
+		return nil, err
 	}
+	recordContainerEvent(*container, "create", nil)
 
-	containerJSON, err := json.Marshal(container)
+	return container, nil
+}
+
+// StartContainer menjalankan container yang sudah dibuat di background
+func StartContainer(containerID string) error {
+	container, err := getContainer(containerID)
 	if err != nil {
-		return fmt.Errorf("gagal menyimpan metadata container: %v", err)
+		return err
 	}
 
-	if err := ioutil.WriteFile(
-		filepath.Join(containerRootDir, "config.json"),
-		containerJSON,
-		0644,
-	); err != nil {
-		return fmt.Errorf("gagal menulis config.json: %v", err)
+	if isContainerActive(container) || (container.Status == StateRestarting && processExists(container.ShimPid)) {
+		return errorf(ErrConflict, "container %s sudah berjalan", containerID)
 	}
 
-	fmt.Printf("Container %s berhasil dibuat dan dijalankan dengan PID %d\n", containerID, cmd.Process.Pid)
-	fmt.Printf("Profil keamanan: %s\n", secProfile.Name)
+	// Start manual membatalkan stop manual sebelumnya dan menghitung ulang restart
+	if err := updateContainer(container.ID, func(c *Container) error {
+		c.ManuallyStopped = false
+		c.RestartCount = 0
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	shim, err := startShim(&container, nil)
+	if err != nil {
+		return err
+	}
+	shim.Close()
 	return nil
 }
 
-// ListContainers menampilkan daftar container
-func ListContainers() error {
+// mergeEnv menggabungkan environment image dengan environment dari user.
+// Variabel dari user menimpa variabel image dengan nama yang sama.
+func mergeEnv(base, overrides []string) []string {
+	env := make([]string, 0, len(base)+len(overrides))
+	index := make(map[string]int)
+	for _, list := range [][]string{base, overrides} {
+		for _, kv := range list {
+			key := strings.SplitN(kv, "=", 2)[0]
+			if i, ok := index[key]; ok {
+				env[i] = kv
+				continue
+			}
+			index[key] = len(env)
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// ContainerSummary adalah ringkasan container untuk perintah ps
+type ContainerSummary struct {
+	// ID adalah ID lengkap (64 karakter hex); gunakan ShortID untuk tampilan
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Image   string   `json:"image"`
+	Command []string `json:"command"`
+	// State adalah status mentah (running, stopped, ...), sedangkan Status
+	// adalah teks untuk ditampilkan, misalnya "stopped (137)"
+	State        string            `json:"state"`
+	Status       string            `json:"status"`
+	ExitCode     int               `json:"exit_code"`
+	Pid          int               `json:"pid"`
+	RestartCount int               `json:"restart_count"`
+	Ports        []string          `json:"ports"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// ShortID mengembalikan ID container versi 12 karakter
+func (c ContainerSummary) ShortID() string {
+	return ShortID(c.ID)
+}
+
+// ListContainers mengembalikan daftar container yang cocok dengan filter
+// status, name, id, ancestor, dan label. Tanpa all, hanya container yang aktif
+// yang dikembalikan, kecuali filter status diisi.
+func ListContainers(all bool, filters Filters) ([]ContainerSummary, error) {
+	if err := filters.validate("status", "name", "id", "ancestor", "label", "label!"); err != nil {
+		return nil, err
+	}
 	if err := initContainerDir(); err != nil {
-		return err
+		return nil, err
 	}
 
 	containers, err := getContainers()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("%-12s %-15s %-15s %-10s %-10s %-10s %-10s\n", 
-		"ID", "NAME", "IMAGE", "STATUS", "PID", "PORTS", "CREATED")
-	
+	_, statusFilter := filters["status"]
+	summaries := []ContainerSummary{}
 	for _, c := range containers {
-		// Cek apakah container masih berjalan
-		pidRunning := false
-		if c.Pid > 0 {
-			pidPath := fmt.Sprintf("/proc/%d", c.Pid)
-			if _, err := os.Stat(pidPath); err == nil {
-				pidRunning = true
-			}
-		}
-
-		status := c.Status
-		if !pidRunning && status == StateRunning {
-			status = StateStopped
+		// Shim mencatat state akhir container. Jika shim tidak lagi berjalan
+		// (misalnya host reboot), tandai container berhenti di sini.
+		state := c.Status
+		if !processExists(c.Pid) && state != StateStopped && state != StateCreated && !processExists(c.ShimPid) {
+			state = StateStopped
 			updateContainerStatus(c.ID, StateStopped)
 		}
 
-		// Format port untuk display
-		portDisplay := "none"
-		if len(c.Ports) > 0 {
-			portDisplay = strings.Join(c.Ports, ", ")
+		if !all && !statusFilter && state != StateRunning && state != StatePaused && state != StateRestarting {
+			continue
+		}
+		if !filters.match("status", func(v string) bool { return v == state }) ||
+			!filters.match("name", func(v string) bool { return strings.Contains(c.Name, v) }) ||
+			!filters.match("id", func(v string) bool { return strings.HasPrefix(c.ID, v) }) ||
+			!filters.match("ancestor", func(v string) bool { return matchImageRef(c.Image, v) }) ||
+			!filters.matchLabels(c.Labels) {
+			continue
 		}
 
-		// Format created time
-		createdAgo := time.Since(c.CreatedAt).Round(time.Second)
-
-		fmt.Printf("%-12s %-15s %-15s %-10s %-10d %-10s %s ago\n", 
-			c.ID, c.Name, c.Image, status, c.Pid, portDisplay, createdAgo)
+		summary := ContainerSummary{
+			ID:           c.ID,
+			Name:         c.Name,
+			Image:        c.Image,
+			Command:      c.Command,
+			State:        state,
+			Status:       state,
+			ExitCode:     c.ExitCode,
+			RestartCount: c.RestartCount,
+			Ports:        c.Ports,
+			Labels:       c.Labels,
+			CreatedAt:    c.CreatedAt,
+		}
+		if state == StateRunning || state == StatePaused {
+			summary.Pid = c.Pid
+		}
+		if state == StateStopped && !c.FinishedAt.IsZero() {
+			summary.Status = fmt.Sprintf("%s (%d)", state, c.ExitCode)
+		}
+		summaries = append(summaries, summary)
 	}
 
-	return nil
+	return summaries, nil
 }
 
-// StopContainer menghentikan container yang sedang berjalan
-func StopContainer(containerID string) error {
+// StopContainer menghentikan container dengan StopSignal image (default
+// SIGTERM), lalu mengirim SIGKILL jika container belum berhenti setelah timeout
+func StopContainer(containerID string, timeout time.Duration) error {
 	if err := initContainerDir(); err != nil {
 		return err
 	}
 
-	containerDir := filepath.Join(ContainerDir, containerID)
-	configPath := filepath.Join(containerDir, "config.json")
-
-	data, err := ioutil.ReadFile(configPath)
+	container, err := getContainer(containerID)
 	if err != nil {
-		return fmt.Errorf("container dengan ID %s tidak ditemukan", containerID)
+		return err
 	}
 
-	var container Container
-	if err := json.Unmarshal(data, &container); err != nil {
-		return fmt.Errorf("gagal membaca konfigurasi container: %v", err)
+	// Container yang menunggu restart cukup ditandai berhenti, shim akan
+	// membatalkan restart-nya
+	if container.Status == StateRestarting {
+		if err := updateContainer(container.ID, func(c *Container) error {
+			c.ManuallyStopped = true
+			c.Status = StateStopped
+			return nil
+		}); err != nil {
+			return err
+		}
+		recordContainerEvent(container, "stop", nil)
+		return nil
 	}
 
-	if container.Status == StateStopped {
-		return fmt.Errorf("container %s sudah berhenti", containerID)
+	if container.Status != StateRunning && container.Status != StatePaused {
+		return errorf(ErrConflict, "container %s tidak berjalan", containerID)
 	}
 
-	// Kirim sinyal untuk menghentikan proses
-	pid := container.Pid
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("proses container tidak ditemukan: %v", err)
+	// Tandai stop manual agar shim tidak menjalankan restart policy
+	if err := updateContainer(container.ID, func(c *Container) error {
+		c.ManuallyStopped = true
+		return nil
+	}); err != nil {
+		return err
 	}
 
-	// Kirim sinyal
-	// Di Linux akan menggunakan SIGTERM dan SIGKILL
-	// Di Windows, hanya Kill yang tersedia
-	if err := killProcess(process); err != nil {
+	// Proses yang dibekukan tidak bisa menangani sinyal, jadi container
+	// yang di-pause dilanjutkan lebih dulu
+	if container.Status == StatePaused {
+		if err := thawContainer(container); err != nil {
+			return err
+		}
+		updateContainerStatus(container.ID, StateRunning)
+	}
+
+	if err := signalProcess(container.Pid, stopSignal(container)); err != nil {
 		return fmt.Errorf("gagal menghentikan proses: %v", err)
 	}
 
-	// Cleanup port mapping jika ada
-	if len(container.Ports) > 0 {
-		cleanupPortMapping(container.Ports)
+	if !waitForContainerExit(container, timeout) {
+		fmt.Fprintf(output, "Container %s tidak berhenti dalam %v, mengirim SIGKILL\n", containerID, timeout)
+		if err := signalProcess(container.Pid, syscall.SIGKILL); err != nil {
+			return fmt.Errorf("gagal menghentikan proses: %v", err)
+		}
+		waitForContainerExit(container, 5*time.Second)
 	}
 
-	// Update status container
-	if err := updateContainerStatus(containerID, StateStopped); err != nil {
-		return err
+	// Shim akan mencatat exit code dan membersihkan port mapping. Jika shim
+	// sudah tidak ada, update status secara langsung.
+	if current, err := getContainer(container.ID); err == nil && current.Status != StateStopped {
+		if err := updateContainerStatus(container.ID, StateStopped); err != nil {
+			return err
+		}
 	}
 
 	// Tambahkan log stop
@@ -377,47 +571,39 @@ func StopContainer(containerID string) error {
 		}
 	}
 
-	fmt.Printf("Container %s berhasil dihentikan\n", containerID)
+	recordContainerEvent(container, "stop", nil)
 	return nil
 }
 
 // ContainerLogs menampilkan logs dari container
 func ContainerLogs(containerID string, follow bool) error {
-	return LogsFromContainer(containerID, follow)
-}
-
-// ExecInContainer menjalankan perintah dalam container yang sedang berjalan
-func ExecInContainer(containerID string, command []string) error {
-	if err := initContainerDir(); err != nil {
-		return err
-	}
-
-	container, err := getContainer(containerID)
+	id, err := resolveContainerID(containerID)
 	if err != nil {
 		return err
 	}
+	return LogsFromContainer(id, follow)
+}
 
-	if container.Status != StateRunning {
-		return fmt.Errorf("container %s tidak berjalan", containerID)
-	}
-
-	// Pastikan container berjalan dengan melakukan nsenter
-	// nsenter memungkinkan untuk masuk ke namespace container
+// ExecInContainer menjalankan perintah dalam container yang sedang berjalan.
+// Exit code selain 0 dikembalikan sebagai *ExitCodeError.
+func ExecInContainer(containerID, workDir string, command []string) error {
 	if utils.IsLinux() {
-		// Karena nsenter perlu root privilege, kita perlu sudo
-		args := []string{
-			"-m", "-u", "-i", "-n", "-p",
-			"-t", strconv.Itoa(container.Pid),
-			"-r",
+		cmd, err := ExecCommand(containerID, ExecOptions{
+			Cmd:        command,
+			WorkingDir: workDir,
+			Stdin:      os.Stdin,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+		})
+		if err != nil {
+			return err
 		}
-		args = append(args, command...)
-
-		cmd := exec.Command("nsenter", args...)
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-
-		return cmd.Run()
+		err = cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &ExitCodeError{Code: exitErr.ExitCode()}
+		}
+		return err
 	} else {
 		// Di Windows, exec tidak bisa dilakukan dengan benar
 		// Kita akan simulasikan dengan pesan
@@ -427,6 +613,66 @@ func ExecInContainer(containerID string, command []string) error {
 	}
 }
 
+// ExecOptions berisi parameter perintah yang dijalankan di container
+type ExecOptions struct {
+	Cmd []string
+	// Env ditambahkan ke environment container
+	Env []string
+	// WorkingDir adalah direktori kerja absolut di container, default /
+	WorkingDir string
+	Stdin      io.Reader
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+// ExecCommand menyiapkan helper internal-exec yang menjalankan opts.Cmd di
+// cgroup dan namespace container dengan capability, no_new_privs, profil
+// AppArmor, dan filter seccomp container. Perintah belum dijalankan,
+// pemanggil yang memanggil Start atau Run.
+func ExecCommand(containerID string, opts ExecOptions) (*exec.Cmd, error) {
+	if err := initContainerDir(); err != nil {
+		return nil, err
+	}
+
+	container, err := getContainer(containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if container.Status == StatePaused {
+		return nil, errorf(ErrConflict, "container %s sedang di-pause, jalankan unpause terlebih dahulu", containerID)
+	}
+	if container.Status != StateRunning {
+		return nil, errorf(ErrConflict, "container %s tidak berjalan", containerID)
+	}
+	if len(opts.Cmd) == 0 {
+		return nil, fmt.Errorf("perintah exec kosong")
+	}
+	workDir := opts.WorkingDir
+	if workDir == "" {
+		workDir = "/"
+	}
+	if !path.IsAbs(workDir) {
+		return nil, errorf(ErrInvalidParameter, "working directory exec harus path absolut: %q", workDir)
+	}
+
+	// Environment dikirim sebagai JSON karena nilainya boleh berisi koma
+	env, err := json.Marshal(mergeEnv(container.Env, opts.Env))
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"internal-exec", "--workdir", workDir, "--env", string(env), container.ID}
+	args = append(args, opts.Cmd...)
+
+	// Seperti shim, helper membaca state container dari direktori data
+	cmd := exec.Command(ShimBinary, args...)
+	cmd.Env = append(os.Environ(), shimEnv...)
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+	return cmd, nil
+}
+
 // getContainers membaca semua metadata container
 func getContainers() ([]Container, error) {
 	files, err := ioutil.ReadDir(ContainerDir)
@@ -443,14 +689,8 @@ func getContainers() ([]Container, error) {
 			continue
 		}
 
-		configPath := filepath.Join(ContainerDir, file.Name(), "config.json")
-		data, err := ioutil.ReadFile(configPath)
-		if err != nil {
-			continue
-		}
-
 		var container Container
-		if err := json.Unmarshal(data, &container); err != nil {
+		if err := containerSchema.Read(containerConfigPath(file.Name()), &container); err != nil {
 			continue
 		}
 
@@ -460,49 +700,88 @@ func getContainers() ([]Container, error) {
 	return containers, nil
 }
 
-// getContainer mendapatkan info container by ID
+// getContainer mendapatkan info container berdasarkan ID lengkap, nama,
+// atau prefix ID yang unik
 func getContainer(containerID string) (Container, error) {
-	containerDir := filepath.Join(ContainerDir, containerID)
-	configPath := filepath.Join(containerDir, "config.json")
-
-	data, err := ioutil.ReadFile(configPath)
+	id, err := resolveContainerID(containerID)
 	if err != nil {
-		return Container{}, fmt.Errorf("container dengan ID %s tidak ditemukan", containerID)
+		return Container{}, err
 	}
 
 	var container Container
-	if err := json.Unmarshal(data, &container); err != nil {
+	if err := containerSchema.Read(containerConfigPath(id), &container); err != nil {
+		if os.IsNotExist(err) {
+			return Container{}, errorf(ErrNotFound, "container dengan ID %s tidak ditemukan", containerID)
+		}
 		return Container{}, fmt.Errorf("gagal membaca konfigurasi container: %v", err)
 	}
 
 	return container, nil
 }
 
-// updateContainerStatus mengupdate status container
-func updateContainerStatus(containerID, status string) error {
-	configPath := filepath.Join(ContainerDir, containerID, "config.json")
-	data, err := ioutil.ReadFile(configPath)
+// saveContainer menulis metadata container ke config.json secara atomik
+func saveContainer(container *Container) error {
+	if err := containerSchema.Write(containerConfigPath(container.ID), container); err != nil {
+		return fmt.Errorf("gagal menyimpan metadata container: %v", err)
+	}
+	return nil
+}
+
+// updateContainer membaca metadata container, menerapkan fn, lalu
+// menyimpannya kembali. Container dikunci selama fn berjalan sehingga
+// perubahan dari proses lain (misalnya shim dan stop) tidak saling menimpa.
+// fn tidak boleh memanggil updateContainer untuk container yang sama.
+func updateContainer(containerID string, fn func(*Container) error) error {
+	id, err := resolveContainerID(containerID)
 	if err != nil {
-		return fmt.Errorf("container dengan ID %s tidak ditemukan", containerID)
+		return err
 	}
 
 	var container Container
-	if err := json.Unmarshal(data, &container); err != nil {
-		return fmt.Errorf("gagal membaca konfigurasi container: %v", err)
+	if err := containerSchema.Update(containerConfigPath(id), &container, func() error {
+		return fn(&container)
+	}); err != nil {
+		if os.IsNotExist(err) {
+			return errorf(ErrNotFound, "container dengan ID %s tidak ditemukan", containerID)
+		}
+		return err
 	}
+	return nil
+}
 
-	container.Status = status
+// updateContainerStatus mengupdate status container
+func updateContainerStatus(containerID, status string) error {
+	return updateContainer(containerID, func(c *Container) error {
+		c.Status = status
+		return nil
+	})
+}
 
-	updatedData, err := json.Marshal(container)
-	if err != nil {
-		return fmt.Errorf("gagal mengupdate metadata container: %v", err)
+// processExists memeriksa apakah proses dengan pid tertentu masih ada
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
 	}
+	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+	return err == nil
+}
 
-	if err := ioutil.WriteFile(configPath, updatedData, 0644); err != nil {
-		return fmt.Errorf("gagal menulis config.json: %v", err)
+// waitForShimExit menunggu shim mencatat bahwa container sudah berhenti.
+// Mengembalikan false jika shim tidak berjalan atau batas waktu terlewati.
+func waitForShimExit(container Container, timeout time.Duration) bool {
+	if !processExists(container.ShimPid) {
+		return false
 	}
 
-	return nil
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		current, err := getContainer(container.ID)
+		if err == nil && current.Status == StateStopped {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
 }
 
 // isContainerRunning memeriksa apakah container masih berjalan
@@ -529,7 +808,7 @@ func isContainerRunning(containerID string) bool {
 func setupPortMapping(ports []string) error {
 	// Di non-Linux, kita hanya simulasikan
 	if !utils.IsLinux() {
-		fmt.Println("Simulasi port mapping:", ports)
+		fmt.Fprintln(output, "Simulasi port mapping:", ports)
 		return nil
 	}
 
@@ -545,7 +824,7 @@ func setupPortMapping(ports []string) error {
 		containerPort := parts[1]
 
 		// Contoh menambahkan iptables rule (perlu implementasi tambahan)
-		fmt.Printf("Setting up port mapping %s->%s\n", hostPort, containerPort)
+		fmt.Fprintf(output, "Setting up port mapping %s->%s\n", hostPort, containerPort)
 	}
 
 	return nil
@@ -555,7 +834,7 @@ func setupPortMapping(ports []string) error {
 func cleanupPortMapping(ports []string) {
 	// Di non-Linux, kita hanya simulasikan
 	if !utils.IsLinux() {
-		fmt.Println("Simulasi cleanup port mapping:", ports)
+		fmt.Fprintln(output, "Simulasi cleanup port mapping:", ports)
 		return
 	}
 
@@ -570,6 +849,6 @@ func cleanupPortMapping(ports []string) {
 		containerPort := parts[1]
 
 		// Contoh menghapus iptables rule (perlu implementasi tambahan)
-		fmt.Printf("Cleaning up port mapping %s->%s\n", hostPort, containerPort)
+		fmt.Fprintf(output, "Cleaning up port mapping %s->%s\n", hostPort, containerPort)
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,34 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Jenis error yang bisa diperiksa dengan errors.Is, misalnya oleh daemon
+// untuk memilih kode status HTTP
+var (
+	// ErrNotFound menandai container, image, atau volume yang tidak ada
+	ErrNotFound = errors.New("tidak ditemukan")
+	// ErrConflict menandai operasi yang bertentangan dengan state objek,
+	// misalnya nama yang sudah dipakai atau container yang masih berjalan
+	ErrConflict = errors.New("konflik")
+	// ErrInvalidParameter menandai opsi container yang tidak valid
+	ErrInvalidParameter = errors.New("parameter tidak valid")
+)
+
+// kindError adalah error dengan pesan biasa yang juga cocok dengan salah
+// satu jenis error di atas
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string { return e.msg }
+
+func (e *kindError) Unwrap() error { return e.kind }
+
+// errorf membuat error berjenis kind tanpa mengubah pesan untuk user
+func errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
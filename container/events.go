@@ -0,0 +1,346 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/minidocker/pkg/config"
+	"github.com/user/minidocker/pkg/store"
+)
+
+// Jenis objek yang mencatat event
+const (
+	EventTypeContainer = "container"
+	EventTypeImage     = "image"
+	EventTypeVolume    = "volume"
+	EventTypeNetwork   = "network"
+)
+
+// PortMappingNetwork adalah nama network untuk event network. Minidocker
+// belum memiliki objek network; container dengan port mapping dianggap
+// terhubung ke network ini selama port mapping-nya terpasang.
+const PortMappingNetwork = "portmap"
+
+// Event adalah satu perubahan state yang dicatat di jurnal event, misalnya
+// container start, die, atau oom. ID berisi ID container, referensi image
+// (NAMA:TAG), nama volume, atau nama network.
+type Event struct {
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ID         string            `json:"id"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Time       time.Time         `json:"time"`
+}
+
+// EventsFile adalah jurnal event (satu objek JSON per baris), diatur oleh
+// Configure. Jurnal dipakai bersama oleh CLI, shim, dan daemon.
+var EventsFile = filepath.Join(config.DefaultRoot, "events.log")
+
+const (
+	// eventsMaxSize adalah ukuran jurnal sebelum dipindah ke EventsFile.1.
+	// Hanya satu jurnal lama yang disimpan.
+	eventsMaxSize = 8 << 20
+
+	// eventsPollInterval adalah jeda pemeriksaan event baru saat follow
+	eventsPollInterval = 200 * time.Millisecond
+)
+
+// recordEvent menambahkan event ke jurnal. Kegagalan mencatat event tidak
+// menggagalkan operasi yang memicunya.
+func recordEvent(typ, action, id string, attributes map[string]string) {
+	event := Event{Type: typ, Action: action, ID: id, Attributes: attributes, Time: time.Now()}
+	if err := appendEvent(event); err != nil {
+		fmt.Fprintf(output, "Warning: gagal mencatat event %s %s: %v\n", typ, action, err)
+	}
+}
+
+// recordContainerEvent mencatat event container. Seperti Docker, atribut
+// berisi nama, image, dan label container ditambah extra.
+func recordContainerEvent(container Container, action string, extra map[string]string) {
+	attributes := map[string]string{}
+	for key, value := range container.Labels {
+		attributes[key] = value
+	}
+	attributes["name"] = container.Name
+	attributes["image"] = container.Image
+	for key, value := range extra {
+		attributes[key] = value
+	}
+	recordEvent(EventTypeContainer, action, container.ID, attributes)
+}
+
+// recordNetworkEvent mencatat event connect atau disconnect container ke
+// PortMappingNetwork. Seperti Docker, atribut container berisi ID container.
+func recordNetworkEvent(container Container, action string) {
+	recordEvent(EventTypeNetwork, action, PortMappingNetwork, map[string]string{
+		"container": container.ID,
+		"name":      PortMappingNetwork,
+		"type":      PortMappingNetwork,
+		"ports":     strings.Join(container.Ports, ","),
+	})
+}
+
+// appendEvent menulis event ke akhir jurnal dengan lock jurnal, lalu
+// merotasi jurnal yang sudah terlalu besar
+func appendEvent(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(EventsFile), 0755); err != nil {
+		return fmt.Errorf("gagal membuat direktori jurnal event: %v", err)
+	}
+
+	lock, err := store.Lock(EventsFile)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if info, err := os.Stat(EventsFile); err == nil && info.Size() >= eventsMaxSize {
+		if err := os.Rename(EventsFile, EventsFile+".1"); err != nil {
+			return fmt.Errorf("gagal merotasi jurnal event: %v", err)
+		}
+	}
+
+	file, err := os.OpenFile(EventsFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("gagal membuka jurnal event: %v", err)
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// EventOptions memilih event yang dibaca dari jurnal
+type EventOptions struct {
+	// Since dan Until membatasi waktu event (zero berarti tanpa batas)
+	Since time.Time
+	Until time.Time
+	// Filters mendukung type, event, container, image, volume, network, dan label
+	Filters Filters
+	// Follow terus menunggu event baru sampai Until atau ctx selesai
+	Follow bool
+}
+
+// ValidateEventFilters memastikan semua key filter event didukung
+func ValidateEventFilters(filters Filters) error {
+	return filters.validate("type", "event", "container", "image", "volume", "network", "label")
+}
+
+// Events memanggil fn untuk setiap event di jurnal yang cocok dengan opts,
+// berurutan dari yang paling lama. Dengan Follow, Events menunggu event
+// baru sampai Until terlewati atau ctx selesai. Error dari fn menghentikan
+// pembacaan dan dikembalikan.
+func Events(ctx context.Context, opts EventOptions, fn func(Event) error) error {
+	if err := ValidateEventFilters(opts.Filters); err != nil {
+		return err
+	}
+
+	emit := func(event Event) error {
+		if !opts.Since.IsZero() && event.Time.Before(opts.Since) {
+			return nil
+		}
+		if !opts.Until.IsZero() && event.Time.After(opts.Until) {
+			return nil
+		}
+		if !matchEvent(event, opts.Filters) {
+			return nil
+		}
+		return fn(event)
+	}
+
+	// Jurnal lama dibaca lebih dulu agar urutan tetap kronologis
+	if old, err := openJournal(EventsFile + ".1"); err != nil {
+		return err
+	} else if old != nil {
+		err := old.read(emit)
+		old.close()
+		if err != nil {
+			return err
+		}
+	}
+
+	journal, err := openJournal(EventsFile)
+	if err != nil {
+		return err
+	}
+	defer func() { journal.close() }()
+	if err := journal.read(emit); err != nil || !opts.Follow {
+		return err
+	}
+
+	ticker := time.NewTicker(eventsPollInterval)
+	defer ticker.Stop()
+	for {
+		if !opts.Until.IsZero() && time.Now().After(opts.Until) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := journal.read(emit); err != nil {
+			return err
+		}
+
+		// Jurnal dirotasi atau baru dibuat: sisa jurnal lama sudah dibaca
+		// di atas, lanjutkan dari awal jurnal baru
+		info, err := os.Stat(EventsFile)
+		if err != nil || journal.same(info) {
+			continue
+		}
+		next, err := openJournal(EventsFile)
+		if err != nil {
+			return err
+		}
+		journal.close()
+		journal = next
+		if err := journal.read(emit); err != nil {
+			return err
+		}
+	}
+}
+
+// journalReader membaca jurnal event baris demi baris. Baris terakhir yang
+// belum lengkap disimpan sampai sisanya ditulis.
+type journalReader struct {
+	file    *os.File
+	reader  *bufio.Reader
+	partial []byte
+}
+
+// openJournal membuka jurnal di path. Jurnal yang belum ada menghasilkan
+// nil tanpa error.
+func openJournal(path string) (*journalReader, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka jurnal event: %v", err)
+	}
+	return &journalReader{file: file, reader: bufio.NewReader(file)}, nil
+}
+
+// read memanggil fn untuk setiap event lengkap yang belum dibaca. Baris yang
+// tidak valid dilewati.
+func (j *journalReader) read(fn func(Event) error) error {
+	if j == nil {
+		return nil
+	}
+	for {
+		line, err := j.reader.ReadBytes('\n')
+		if err == io.EOF {
+			j.partial = append(j.partial, line...)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("gagal membaca jurnal event: %v", err)
+		}
+		if len(j.partial) > 0 {
+			line = append(j.partial, line...)
+			j.partial = nil
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
+
+// same memeriksa apakah info adalah file yang sedang dibaca
+func (j *journalReader) same(info os.FileInfo) bool {
+	if j == nil {
+		return false
+	}
+	current, err := j.file.Stat()
+	return err == nil && os.SameFile(current, info)
+}
+
+func (j *journalReader) close() {
+	if j != nil {
+		j.file.Close()
+	}
+}
+
+// matchEvent memeriksa filter event. Filter container cocok dengan prefix
+// ID atau nama container (termasuk container di event network), filter
+// image cocok dengan image container atau referensi image, dan filter label
+// memeriksa atribut event.
+func matchEvent(event Event, filters Filters) bool {
+	return filters.match("type", func(v string) bool { return event.Type == v }) &&
+		filters.match("event", func(v string) bool { return event.Action == v }) &&
+		filters.match("container", func(v string) bool {
+			switch event.Type {
+			case EventTypeContainer:
+				return strings.HasPrefix(event.ID, v) || event.Attributes["name"] == v
+			case EventTypeNetwork:
+				return strings.HasPrefix(event.Attributes["container"], v)
+			}
+			return false
+		}) &&
+		filters.match("image", func(v string) bool {
+			switch event.Type {
+			case EventTypeContainer:
+				return matchImageRef(event.Attributes["image"], v)
+			case EventTypeImage:
+				return matchImageRef(event.ID, v)
+			}
+			return false
+		}) &&
+		filters.match("volume", func(v string) bool {
+			return event.Type == EventTypeVolume && event.ID == v
+		}) &&
+		filters.match("network", func(v string) bool {
+			return event.Type == EventTypeNetwork && event.ID == v
+		}) &&
+		filters.matchLabels(event.Attributes)
+}
+
+// ParseEventTime mem-parse nilai --since dan --until: waktu RFC 3339
+// (misalnya 2024-01-02T15:04:05Z), Unix timestamp (1704207845 atau
+// 1704207845.5), atau durasi relatif terhadap now (misalnya 10m)
+func ParseEventTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if sec, frac, _ := strings.Cut(value, "."); sec != "" {
+		seconds, err := strconv.ParseInt(sec, 10, 64)
+		nanos, fracErr := int64(0), error(nil)
+		if frac != "" {
+			frac = (frac + "000000000")[:9]
+			nanos, fracErr = strconv.ParseInt(frac, 10, 64)
+		}
+		if err == nil && fracErr == nil {
+			return time.Unix(seconds, nanos), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("waktu tidak valid: %q (gunakan RFC 3339, Unix timestamp, atau durasi seperti 10m)", value)
+}
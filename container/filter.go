@@ -0,0 +1,196 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filters adalah kumpulan filter dari flag --filter KEY=VALUE. Beberapa
+// nilai untuk key yang sama digabung dengan OR, sedangkan key yang berbeda
+// digabung dengan AND, sama seperti Docker.
+type Filters map[string][]string
+
+// ParseFilters mem-parse nilai-nilai --filter seperti "status=running"
+func ParseFilters(values []string) (Filters, error) {
+	filters := Filters{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("format filter tidak valid: %q (gunakan KEY=VALUE)", value)
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		filters[key] = append(filters[key], parts[1])
+	}
+	return filters, nil
+}
+
+// validate memastikan semua key filter didukung
+func (f Filters) validate(allowed ...string) error {
+	for key := range f {
+		found := false
+		for _, a := range allowed {
+			if key == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sort.Strings(allowed)
+			return fmt.Errorf("filter %q tidak didukung (gunakan %s)", key, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// match mengembalikan true jika filter key tidak diisi, atau salah satu
+// nilainya cocok menurut fn
+func (f Filters) match(key string, fn func(value string) bool) bool {
+	values, ok := f[key]
+	if !ok {
+		return true
+	}
+	for _, value := range values {
+		if fn(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLabels memeriksa filter label=KEY[=VALUE] (semua harus cocok) dan
+// label!=KEY[=VALUE] (tidak boleh ada yang cocok)
+func (f Filters) matchLabels(labels map[string]string) bool {
+	for _, filter := range f["label"] {
+		if !matchLabel(labels, filter) {
+			return false
+		}
+	}
+	for _, filter := range f["label!"] {
+		if matchLabel(labels, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// danglingFilter membaca nilai filter dangling. Mengembalikan nil jika
+// filter tidak diisi.
+func (f Filters) danglingFilter() (*bool, error) {
+	values, ok := f["dangling"]
+	if !ok {
+		return nil, nil
+	}
+	var result *bool
+	for _, value := range values {
+		dangling, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("nilai filter dangling tidak valid: %q (gunakan true atau false)", value)
+		}
+		if result != nil && *result != dangling {
+			return nil, fmt.Errorf("filter dangling=true dan dangling=false tidak bisa dipakai bersamaan")
+		}
+		result = &dangling
+	}
+	return result, nil
+}
+
+// matchLabel memeriksa filter label berformat "KEY" atau "KEY=VALUE"
+func matchLabel(labels map[string]string, filter string) bool {
+	parts := strings.SplitN(filter, "=", 2)
+	value, ok := labels[parts[0]]
+	if !ok {
+		return false
+	}
+	return len(parts) == 1 || value == parts[1]
+}
+
+// matchImageRef membandingkan dua referensi image, dengan tag default latest
+func matchImageRef(image, ref string) bool {
+	imageName, imageTag := parseImageNameTag(image)
+	refName, refTag := parseImageNameTag(ref)
+	if refTag == "" {
+		return imageName == refName
+	}
+	if imageTag == "" {
+		imageTag = "latest"
+	}
+	return imageName == refName && imageTag == refTag
+}
+
+// FilterImages memilih image yang cocok dengan filter reference, label,
+// dan dangling. Seperti Docker, image dangling adalah image tanpa tag
+// (<none>), bukan image yang tidak dipakai container.
+func FilterImages(images []ImageInfo, filters Filters) ([]ImageInfo, error) {
+	if err := filters.validate("reference", "label", "label!", "dangling"); err != nil {
+		return nil, err
+	}
+	dangling, err := filters.danglingFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []ImageInfo{}
+	for _, img := range images {
+		ref := img.Name + ":" + img.Tag
+		if !filters.match("reference", func(v string) bool { return matchImageRef(ref, v) }) ||
+			!filters.matchLabels(img.Labels) {
+			continue
+		}
+		if dangling != nil && isDanglingImage(img) != *dangling {
+			continue
+		}
+		result = append(result, img)
+	}
+	return result, nil
+}
+
+// isDanglingImage melaporkan apakah image tidak memiliki tag
+func isDanglingImage(img ImageInfo) bool {
+	return img.Tag == "" || img.Tag == "<none>"
+}
+
+// FilterVolumes memilih volume yang cocok dengan filter name, driver,
+// label, dan dangling. Volume dangling adalah volume yang tidak dipakai
+// container mana pun.
+func FilterVolumes(volumes []Volume, filters Filters) ([]Volume, error) {
+	if err := filters.validate("name", "driver", "label", "label!", "dangling"); err != nil {
+		return nil, err
+	}
+	dangling, err := filters.danglingFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []Container
+	if dangling != nil {
+		if containers, err = getContainers(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := []Volume{}
+	for _, v := range volumes {
+		if !filters.match("name", func(f string) bool { return strings.Contains(v.Name, f) }) ||
+			!filters.match("driver", func(f string) bool { return v.Driver == f }) ||
+			!filters.matchLabels(v.Labels) {
+			continue
+		}
+		if dangling != nil {
+			used := false
+			for _, c := range containers {
+				for _, spec := range c.Volumes {
+					if volumeInUse(spec, v.Name) {
+						used = true
+					}
+				}
+			}
+			if used == *dangling {
+				continue
+			}
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
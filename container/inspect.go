@@ -0,0 +1,298 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/minidocker/image"
+)
+
+// ContainerInspect adalah state lengkap container untuk perintah inspect:
+// metadata dari config.json digabung dengan informasi runtime
+type ContainerInspect struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Image   string    `json:"image"`
+	Created time.Time `json:"created"`
+	Path    string    `json:"path"`
+	Args    []string  `json:"args"`
+
+	State  ContainerState  `json:"state"`
+	Config ContainerConfig `json:"config"`
+
+	HostConfig      HostConfig      `json:"host_config"`
+	Mounts          []MountPoint    `json:"mounts"`
+	NetworkSettings NetworkSettings `json:"network_settings"`
+
+	RestartCount int    `json:"restart_count"`
+	Rootfs       string `json:"rootfs"`
+	LogPath      string `json:"log_path"`
+	CgroupPath   string `json:"cgroup_path"`
+}
+
+// ContainerState adalah state proses container
+type ContainerState struct {
+	Status     string    `json:"status"`
+	Running    bool      `json:"running"`
+	Paused     bool      `json:"paused"`
+	Restarting bool      `json:"restarting"`
+	OOMKilled  bool      `json:"oom_killed"`
+	Pid        int       `json:"pid"`
+	ShimPid    int       `json:"shim_pid"`
+	ExitCode   int       `json:"exit_code"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Capabilities adalah capability proses container yang sedang
+	// berjalan, dibaca dari /proc/<pid>/status
+	Capabilities *CapabilitySets `json:"capabilities,omitempty"`
+	// AppArmorProfile adalah profil AppArmor proses container, misalnya
+	// "minidocker-default (enforce)", kosong jika AppArmor tidak aktif
+	AppArmorProfile string `json:"apparmor_profile,omitempty"`
+}
+
+// ContainerConfig adalah konfigurasi proses container
+type ContainerConfig struct {
+	Image      string   `json:"image"`
+	Cmd        []string `json:"cmd"`
+	Env        []string `json:"env"`
+	TTY        bool     `json:"tty"`
+	OpenStdin  bool     `json:"open_stdin"`
+	StopSignal string   `json:"stop_signal"`
+
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// HostConfig adalah pengaturan container di sisi host
+type HostConfig struct {
+	Resources
+	RestartPolicy   RestartPolicy     `json:"restart_policy"`
+	AutoRemove      bool              `json:"auto_remove"`
+	Binds           []string          `json:"binds"`
+	PortBindings    []string          `json:"port_bindings"`
+	Tmpfs           map[string]string `json:"tmpfs,omitempty"`
+	SecurityProfile SecurityProfile   `json:"security_profile"`
+}
+
+// MountPoint adalah volume yang dipasang di container
+type MountPoint struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Mode        string `json:"mode"`
+	RW          bool   `json:"rw"`
+	Anonymous   bool   `json:"anonymous,omitempty"`
+}
+
+// NetworkSettings adalah pengaturan jaringan container
+type NetworkSettings struct {
+	Ports []PortBinding `json:"ports"`
+}
+
+// PortBinding adalah pemetaan port host ke port container
+type PortBinding struct {
+	HostPort      string `json:"host_port"`
+	ContainerPort string `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+// ImageInspect menggabungkan metadata image di registry lokal (ImageInfo)
+// dengan konfigurasi image dari cache (ImageConfig)
+type ImageInspect struct {
+	ImageInfo
+	// Archive adalah lokasi file tar.gz image, kosong jika belum ada di cache
+	Archive string            `json:"archive,omitempty"`
+	Config  image.ImageConfig `json:"config"`
+}
+
+// InspectContainer mengembalikan state lengkap container berdasarkan ID atau nama
+func InspectContainer(nameOrID string) (*ContainerInspect, error) {
+	container, err := getContainer(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status di config.json bisa tertinggal jika shim mati mendadak
+	status := container.Status
+	if (status == StateRunning || status == StatePaused) && !isContainerActive(container) {
+		status = StateStopped
+	}
+	pid := container.Pid
+	if status != StateRunning && status != StatePaused {
+		pid = 0
+	}
+
+	inspect := &ContainerInspect{
+		ID:      container.ID,
+		Name:    container.Name,
+		Image:   container.Image,
+		Created: container.CreatedAt,
+		State: ContainerState{
+			Status:     status,
+			Running:    status == StateRunning || status == StatePaused,
+			Paused:     status == StatePaused,
+			Restarting: status == StateRestarting,
+			OOMKilled:  container.OOMKilled,
+			Pid:        pid,
+			ShimPid:    container.ShimPid,
+			ExitCode:   container.ExitCode,
+			StartedAt:  container.StartedAt,
+			FinishedAt: container.FinishedAt,
+		},
+		Config: ContainerConfig{
+			Image:      container.Image,
+			Cmd:        container.Command,
+			Env:        container.Env,
+			TTY:        container.TTY,
+			OpenStdin:  container.OpenStdin,
+			StopSignal: stopSignalName(container),
+
+			Labels:      container.Labels,
+			Annotations: container.Annotations,
+		},
+		HostConfig: HostConfig{
+			Resources:       container.Resources,
+			RestartPolicy:   container.RestartPolicy,
+			AutoRemove:      container.AutoRemove,
+			Binds:           container.Volumes,
+			PortBindings:    container.Ports,
+			Tmpfs:           container.Tmpfs,
+			SecurityProfile: container.Security,
+		},
+		Mounts:          containerMounts(container),
+		NetworkSettings: NetworkSettings{Ports: parsePortBindings(container.Ports)},
+		RestartCount:    container.RestartCount,
+		Rootfs:          filepath.Join(ContainerDir, container.ID, "rootfs"),
+		LogPath:         container.LogFile,
+		CgroupPath:      container.CgroupPath,
+	}
+	if pid != 0 {
+		if caps, err := processCapabilities(pid); err == nil {
+			inspect.State.Capabilities = caps
+		}
+		inspect.State.AppArmorProfile = processAppArmorProfile(pid)
+	}
+	if len(container.Command) > 0 {
+		inspect.Path = container.Command[0]
+		inspect.Args = container.Command[1:]
+	}
+	return inspect, nil
+}
+
+// InspectImage mengembalikan informasi image berdasarkan NAME[:TAG]. Image
+// ditemukan jika ada di registry lokal atau di cache image.
+func InspectImage(name string) (*ImageInspect, error) {
+	imageName, tag := parseImageNameTag(name)
+	if tag == "" {
+		tag = "latest"
+	}
+
+	inspect := &ImageInspect{ImageInfo: ImageInfo{Name: imageName, Tag: tag}}
+	found := false
+
+	images, err := ListImages()
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		if img.Name == imageName && img.Tag == tag {
+			inspect.ImageInfo = img
+			found = true
+			break
+		}
+	}
+
+	// Cache image disimpan dengan nama lengkap seperti yang dipakai saat run
+	for _, archiveName := range []string{name, imageName} {
+		archive := image.ArchivePath(archiveName)
+		info, err := os.Stat(archive)
+		if err != nil {
+			continue
+		}
+		config, err := image.ReadArchiveConfig(archiveName)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca image %s: %v", name, err)
+		}
+		inspect.Archive = archive
+		inspect.Config = config
+		if !found {
+			inspect.Size = info.Size()
+			inspect.CreatedAt = info.ModTime()
+		}
+		found = true
+		break
+	}
+
+	if !found {
+		return nil, errorf(ErrNotFound, "image '%s' tidak ditemukan", name)
+	}
+	return inspect, nil
+}
+
+// stopSignalName mengembalikan nama sinyal stop yang berlaku untuk container
+func stopSignalName(container Container) string {
+	if container.StopSignal != "" {
+		return container.StopSignal
+	}
+	return "SIGTERM"
+}
+
+// containerMounts mengubah spesifikasi volume (SOURCE:DEST[:MODE]) menjadi
+// MountPoint. Sumber berupa path absolut adalah bind mount, selain itu volume.
+func containerMounts(container Container) []MountPoint {
+	anonymous := make(map[string]bool)
+	for _, name := range container.AnonymousVolumes {
+		anonymous[name] = true
+	}
+
+	mounts := []MountPoint{}
+	for _, spec := range container.Volumes {
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 {
+			continue
+		}
+
+		mount := MountPoint{Source: parts[0], Destination: parts[1], Mode: "rw", RW: true}
+		if len(parts) > 2 && parts[2] != "" {
+			mount.Mode = parts[2]
+			mount.RW = parts[2] != "ro"
+		}
+
+		if filepath.IsAbs(parts[0]) {
+			mount.Type = "bind"
+		} else {
+			mount.Type = "volume"
+			mount.Name = parts[0]
+			mount.Anonymous = anonymous[parts[0]]
+			if volume, err := GetVolume(parts[0]); err == nil {
+				mount.Source = volume.Mountpoint
+			}
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts
+}
+
+// parsePortBindings mengubah spesifikasi port (HOST:CONTAINER[/PROTO])
+// menjadi PortBinding
+func parsePortBindings(ports []string) []PortBinding {
+	bindings := []PortBinding{}
+	for _, port := range ports {
+		parts := strings.SplitN(port, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		binding := PortBinding{HostPort: parts[0], ContainerPort: parts[1], Protocol: "tcp"}
+		if idx := strings.Index(parts[1], "/"); idx >= 0 {
+			binding.ContainerPort = parts[1][:idx]
+			binding.Protocol = parts[1][idx+1:]
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings
+}
@@ -1,19 +1,24 @@
 package container
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
+	"syscall"
+
+	"github.com/user/minidocker/pkg/seccomp"
 )
 
 // Variabel platform-agnostic untuk implementasi fungsi syscall
 var internalSyscallChroot func(path string) error
-var internalSetupMounts func(rootfs string) error
-var internalSetupCgroups func() error
+var internalSetupMounts func(config initConfig) error
 
 func init() {
 	// Default implementation untuk non-Linux platform
@@ -23,44 +28,104 @@ func init() {
 			return nil
 		}
 		
-		internalSetupMounts = func(rootfs string) error {
-			setupMountsDemo(rootfs)
-			return nil
-		}
-		
-		internalSetupCgroups = func() error {
-			fmt.Println("Demo: Setup cgroups (simulasi)")
+		internalSetupMounts = func(config initConfig) error {
+			setupMountsDemo(config.Rootfs)
 			return nil
 		}
 	}
 }
 
+// initPipeFd adalah nomor file descriptor init pipe di dalam child.
+// File descriptor 0-2 dipakai stdio, sehingga ExtraFiles[0] menjadi fd 3.
+const initPipeFd = 3
+
+// initConfig adalah spesifikasi proses yang dikirim parent ke child internal-start
+type initConfig struct {
+	Rootfs   string          `json:"rootfs"`
+	Args     []string        `json:"args"`
+	Env      []string        `json:"env"`
+	Security SecurityProfile `json:"security"`
+	// Seccomp adalah program BPF hasil kompilasi profil seccomp, kosong
+	// untuk unconfined
+	Seccomp []seccomp.Instruction `json:"seccomp,omitempty"`
+	// Tmpfs adalah mount tmpfs dari --tmpfs, dipasang setelah pivot_root
+	Tmpfs map[string]string `json:"tmpfs,omitempty"`
+	// AppArmorProfile adalah profil AppArmor yang sudah dimuat ke kernel,
+	// kosong jika container berjalan tanpa AppArmor
+	AppArmorProfile string `json:"apparmor_profile,omitempty"`
+	// Cwd adalah direktori kerja perintah exec di dalam container
+	Cwd string `json:"cwd,omitempty"`
+}
+
+// newInitPipe membuat pipe untuk mengirim initConfig dan memasang ujung
+// bacanya sebagai fd 3 di child. Ujung tulis dikembalikan ke pemanggil.
+func newInitPipe(cmd *exec.Cmd) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat init pipe: %v", err)
+	}
+	cmd.ExtraFiles = append(cmd.ExtraFiles, r)
+	return w, nil
+}
+
+// sendInitConfig menulis initConfig ke init pipe lalu menutupnya
+func sendInitConfig(pipe *os.File, config initConfig) error {
+	defer pipe.Close()
+	if err := json.NewEncoder(pipe).Encode(config); err != nil {
+		return fmt.Errorf("gagal mengirim konfigurasi ke container: %v", err)
+	}
+	return nil
+}
+
+// readInitConfig membaca initConfig yang dikirim parent lewat init pipe
+func readInitConfig() (initConfig, error) {
+	var config initConfig
+	pipe := os.NewFile(uintptr(initPipeFd), "init-pipe")
+	if pipe == nil {
+		return config, fmt.Errorf("init pipe tidak tersedia")
+	}
+	defer pipe.Close()
+
+	if err := json.NewDecoder(pipe).Decode(&config); err != nil {
+		return config, fmt.Errorf("gagal membaca konfigurasi container: %v", err)
+	}
+	if len(config.Args) == 0 {
+		return config, fmt.Errorf("perintah container kosong")
+	}
+	return config, nil
+}
+
 // InternalStartContainer memulai proses container dari dalam namespace yang terisolasi
-func InternalStartContainer(rootfs string) error {
+func InternalStartContainer() error {
 	// Cek apakah berjalan di Linux
 	if runtime.GOOS != "linux" {
 		return fmt.Errorf("kontainer hanya bisa berjalan di Linux, bukan di %s", runtime.GOOS)
 	}
 
-	fmt.Printf("Memulai container dengan rootfs: %s\n", rootfs)
-	
-	// Mendapatkan batasan resource
-	memLimit := os.Getenv("MINIDOCKER_MEMORY")
-	cpuLimit := os.Getenv("MINIDOCKER_CPU")
-
-	// Setup mounts untuk Linux
-	if err := internalSetupMounts(rootfs); err != nil {
-		return fmt.Errorf("gagal setup mounts: %v", err)
+	config, err := readInitConfig()
+	if err != nil {
+		reportStartError(err)
+		return err
 	}
-	
-	// Setup cgroups hanya di Linux dengan batas yang ditentukan user
-	if err := setupCgroupsWithLimits(memLimit, cpuLimit); err != nil {
-		fmt.Printf("Warning: gagal setup cgroups: %v\n", err)
+
+	if err := startContainerInit(config); err != nil {
+		reportStartError(err)
+		return err
 	}
-	
-	// Chroot ke rootfs (hanya di Linux)
-	if err := internalSyscallChroot(rootfs); err != nil {
-		return fmt.Errorf("gagal chroot: %v", err)
+	return nil
+}
+
+// reportStartError mengirim error setup ke shim lewat start pipe
+func reportStartError(err error) {
+	syscall.Write(startPipeFd, []byte(err.Error()))
+}
+
+// startContainerInit menyiapkan lingkungan container lalu meng-exec perintah user
+func startContainerInit(config initConfig) error {
+	// Setup mounts untuk Linux, termasuk pivot_root ke rootfs, masked path,
+	// tmpfs, dan rootfs read-only
+	if err := internalSetupMounts(config); err != nil {
+		return fmt.Errorf("gagal setup mounts: %v", err)
 	}
 	
 	// Change directory ke root
@@ -68,136 +133,323 @@ func InternalStartContainer(rootfs string) error {
 		return fmt.Errorf("gagal chdir ke /: %v", err)
 	}
 	
-	// Untuk demonstrasi, jalankan shell sederhana
-	fmt.Println("Menjalankan shell di dalam container...")
-	
-	// Di implementasi nyata dengan Linux, ini akan menggunakan chroot
-	cmdPath := "/bin/sh"
-	if runtime.GOOS != "linux" {
-		// Di platform lain gunakan perintah yang tersedia
-		cmdPath = "sh"
+	// Cari executable di dalam rootfs container berdasarkan PATH milik container
+	cmdPath, err := lookPathInEnv(config.Args[0], config.Env)
+	if err != nil {
+		return err
+	}
+
+	// Start pipe ditutup otomatis oleh kernel jika exec berhasil
+	syscall.CloseOnExec(startPipeFd)
+
+	// Capability dan filter seccomp berlaku per thread, jadi goroutine ini
+	// dikunci ke thread yang nantinya melakukan exec
+	runtime.LockOSThread()
+
+	// Transisi AppArmor terjadi saat exec. Seperti runc, profil diatur
+	// sebelum no_new_privs karena transisi dari proses unconfined tetap
+	// diizinkan.
+	if config.AppArmorProfile != "" {
+		if err := applyAppArmorProfile(config.AppArmorProfile); err != nil {
+			return fmt.Errorf("gagal mengatur profil AppArmor %s: %v", config.AppArmorProfile, err)
+		}
+	}
+
+	if err := applyProcessSecurity(config.Security, config.Seccomp); err != nil {
+		return err
 	}
-	
-	cmd := exec.Command(cmdPath, "-c", "echo 'MiniDocker Container Demo'; /bin/sh")
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("gagal menjalankan command di container: %v", err)
+	// Ganti proses ini dengan perintah user sehingga perintah tersebut
+	// menjadi PID 1 di dalam container
+	if err := syscall.Exec(cmdPath, config.Args, config.Env); err != nil {
+		return fmt.Errorf("gagal menjalankan %s di container: %v", config.Args[0], err)
 	}
 
 	return nil
 }
 
-// setupCgroupsWithLimits mengatur cgroups dengan batasan yang ditentukan user
-func setupCgroupsWithLimits(memLimit, cpuLimit string) error {
-	// Di non-Linux, kita hanya simulasikan
-	if runtime.GOOS != "linux" {
-		fmt.Printf("Simulasi setup cgroups dengan memory=%s, cpu=%s\n", memLimit, cpuLimit)
-		return nil
+// applyProcessSecurity menerapkan no_new_privs, capability, dan filter
+// seccomp profil ke thread ini. Dipakai oleh init container dan exec agar
+// urutannya selalu sama. Pemanggil harus mengunci goroutine ke thread yang
+// nantinya menjalankan perintah user.
+func applyProcessSecurity(security SecurityProfile, filter []seccomp.Instruction) error {
+	if security.NoNewPrivs {
+		if err := setNoNewPrivs(); err != nil {
+			return fmt.Errorf("gagal mengatur no_new_privs: %v", err)
+		}
 	}
 
-	// Tentukan pid saat ini
-	pid := os.Getpid()
+	// Pemasangan filter seccomp tanpa no_new_privs memerlukan CAP_SYS_ADMIN,
+	// jadi capability baru dibatasi setelah filter terpasang dan profil
+	// seccomp harus mengizinkan capget, capset, dan prctl. Dengan
+	// no_new_privs, filter dipasang paling akhir sehingga tidak membatasi
+	// pengaturan capability.
+	if security.NoNewPrivs {
+		if err := applyCapabilities(security.Capabilities); err != nil {
+			return fmt.Errorf("gagal mengatur capabilities: %v", err)
+		}
+	}
 
-	// Setup cgroup v2
-	cgroupRoot := "/sys/fs/cgroup"
-	containerCgroup := filepath.Join(cgroupRoot, fmt.Sprintf("minidocker_%d", pid))
+	// Filter seccomp dipasang setelah setup container agar setup di atas
+	// tidak ikut dibatasi
+	if len(filter) > 0 {
+		if err := seccomp.Install(filter); err != nil {
+			return fmt.Errorf("gagal memasang filter seccomp: %v", err)
+		}
+	}
 
-	// Membuat cgroup baru
-	if err := os.Mkdir(containerCgroup, 0755); err != nil {
-		return fmt.Errorf("gagal membuat cgroup: %v", err)
+	if !security.NoNewPrivs {
+		if err := applyCapabilities(security.Capabilities); err != nil {
+			return fmt.Errorf("gagal mengatur capabilities: %v", err)
+		}
+	}
+	return nil
+}
+
+// File descriptor tambahan di child internal-exec-init, setelah init pipe
+// (fd 3) dan start pipe (fd 4)
+const (
+	execMntNsFd = 5
+	execRootFd  = 6
+)
+
+// InternalExecContainer adalah titik masuk helper internal-exec yang
+// dijalankan ExecCommand. Helper masuk ke namespace container selain mount
+// namespace, lalu menjalankan child internal-exec-init di cgroup container.
+// Child tersebut masuk ke root container dan menerapkan profil keamanan
+// container sebelum exec, seperti init container. Helper menunggu perintah
+// selesai dan mengembalikan exit code-nya. env adalah environment lengkap
+// perintah exec.
+func InternalExecContainer(containerID, workDir string, env, args []string) (int, error) {
+	if runtime.GOOS != "linux" {
+		return 0, fmt.Errorf("exec hanya bisa berjalan di Linux, bukan di %s", runtime.GOOS)
 	}
 
-	// Set memory limit
-	memBytes, err := parseMemoryLimit(memLimit)
+	container, err := getContainer(containerID)
 	if err != nil {
-		fmt.Printf("Warning: format memory limit tidak valid, menggunakan default: %v\n", err)
-		memBytes = 67108864 // 64MB default
+		return 0, err
 	}
-	
-	memFile := filepath.Join(containerCgroup, "memory.max")
-	if err := os.WriteFile(memFile, []byte(strconv.FormatUint(memBytes, 10)), 0644); err != nil {
-		return fmt.Errorf("gagal set memory limit: %v", err)
+	if container.Status != StateRunning || !processExists(container.Pid) {
+		return 0, errorf(ErrConflict, "container %s tidak berjalan", containerID)
 	}
 
-	// Set CPU limit
-	cpuValue, err := parseCPULimit(cpuLimit)
+	filter, err := seccompFilter(container.Security)
 	if err != nil {
-		fmt.Printf("Warning: format CPU limit tidak valid, menggunakan default: %v\n", err)
-		cpuValue = 10000 // 10% dari 100000
+		return 0, err
 	}
-	
-	cpuFile := filepath.Join(containerCgroup, "cpu.max")
-	if err := os.WriteFile(cpuFile, []byte(fmt.Sprintf("%d 100000", cpuValue)), 0644); err != nil {
-		return fmt.Errorf("gagal set cpu limit: %v", err)
+	appArmorProfile, err := ensureAppArmorProfile(container.Security.AppArmorProfile)
+	if err != nil {
+		return 0, err
 	}
 
-	// Tambahkan pid ke cgroup
-	procsFile := filepath.Join(containerCgroup, "cgroup.procs")
-	if err := os.WriteFile(procsFile, []byte(fmt.Sprintf("%d", pid)), 0644); err != nil {
-		return fmt.Errorf("gagal tambahkan pid ke cgroup: %v", err)
+	// Mount namespace dan root dibuka dari /proc host lalu diteruskan ke
+	// child, karena child baru bisa masuk ke mount namespace setelah
+	// memisahkan state filesystem thread-nya
+	mntNs, root, err := openContainerRoot(container.Pid)
+	if err != nil {
+		return 0, err
 	}
 
-	fmt.Printf("Berhasil set resource limits: memory=%s (%d bytes), cpu=%s (%d)\n", 
-		memLimit, memBytes, cpuLimit, cpuValue)
+	// Seperti internal-start, child tidak mewarisi environment host dan
+	// konfigurasinya dikirim lewat init pipe
+	cmd := exec.Command("/proc/self/exe", "internal-exec-init")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = []string{}
 
-	return nil
-}
+	initPipe, err := newInitPipe(cmd)
+	if err != nil {
+		mntNs.Close()
+		root.Close()
+		return 0, err
+	}
+	startR, startW, err := os.Pipe()
+	if err != nil {
+		initPipe.Close()
+		mntNs.Close()
+		root.Close()
+		return 0, fmt.Errorf("gagal membuat start pipe: %v", err)
+	}
+	defer startR.Close()
+	cmd.ExtraFiles = append(cmd.ExtraFiles, startW, mntNs, root)
+
+	// Namespace diatur per thread dan namespace pid hanya berlaku untuk
+	// proses anak, jadi child dibuat dari thread yang sudah masuk ke
+	// namespace container. Goroutine ini tidak pernah membuka kunci thread,
+	// sehingga runtime menghentikan thread tersebut ketika goroutine selesai.
+	started := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := joinContainerNamespaces(container.Pid); err != nil {
+			started <- err
+			return
+		}
+		started <- cmd.Start()
+	}()
+	err = <-started
+	// Salinan ujung pipe dan namespace milik child tidak diperlukan lagi
+	for _, f := range cmd.ExtraFiles {
+		f.Close()
+	}
+	if err != nil {
+		initPipe.Close()
+		return 0, fmt.Errorf("gagal menjalankan exec: %v", err)
+	}
 
-// parseMemoryLimit mengubah string memori seperti "64m" menjadi bytes
-func parseMemoryLimit(limit string) (uint64, error) {
-	if limit == "" {
-		return 67108864, nil // 64MB default
+	// Child masih menunggu init config, sehingga perintah exec selalu
+	// berjalan di dalam cgroup container dan ikut dibatasi resource-nya
+	if container.CgroupPath != "" {
+		if err := newCgroup(container.CgroupPath).addProcess(cmd.Process.Pid); err != nil {
+			initPipe.Close()
+			cmd.Process.Kill()
+			cmd.Wait()
+			return 0, err
+		}
 	}
 
-	limit = strings.ToLower(limit)
-	var multiplier uint64 = 1
+	if err := sendInitConfig(initPipe, initConfig{
+		Args:     args,
+		Env:      env,
+		Cwd:      workDir,
+		Security: container.Security,
+		Seccomp:  filter,
+
+		AppArmorProfile: appArmorProfile,
+	}); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return 0, err
+	}
+
+	// Start pipe mencapai EOF ketika child berhasil exec. Jika ada isi,
+	// itu adalah pesan error dari child.
+	startErr, _ := ioutil.ReadAll(startR)
+	if len(startErr) > 0 {
+		cmd.Wait()
+		return 0, fmt.Errorf("%s", strings.TrimSpace(string(startErr)))
+	}
+
+	// SIGTERM dan SIGHUP untuk helper diteruskan ke perintah exec. SIGINT
+	// dan SIGQUIT dari terminal sudah diterima perintah exec karena berada
+	// di process group yang sama, jadi hanya ditangkap agar helper tetap
+	// menunggu perintah tersebut selesai.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			if sig == syscall.SIGTERM || sig == syscall.SIGHUP {
+				cmd.Process.Signal(sig)
+			}
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("gagal menunggu %s di container: %v", args[0], err)
+	}
+	return 0, nil
+}
 
-	if strings.HasSuffix(limit, "k") {
-		multiplier = 1024
-		limit = limit[:len(limit)-1]
-	} else if strings.HasSuffix(limit, "m") {
-		multiplier = 1024 * 1024
-		limit = limit[:len(limit)-1]
-	} else if strings.HasSuffix(limit, "g") {
-		multiplier = 1024 * 1024 * 1024
-		limit = limit[:len(limit)-1]
+// InternalExecInit adalah child internal-exec-init yang dijalankan
+// InternalExecContainer di namespace pid container
+func InternalExecInit() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("exec hanya bisa berjalan di Linux, bukan di %s", runtime.GOOS)
 	}
 
-	value, err := strconv.ParseUint(limit, 10, 64)
+	config, err := readInitConfig()
 	if err != nil {
-		return 0, err
+		reportStartError(err)
+		return err
 	}
 
-	return value * multiplier, nil
+	if err := startExecInit(config); err != nil {
+		reportStartError(err)
+		return err
+	}
+	return nil
 }
 
-// parseCPULimit mengubah persentase CPU menjadi nilai untuk cgroups
-func parseCPULimit(limit string) (uint64, error) {
-	if limit == "" {
-		return 10000, nil // 10% default
+// startExecInit masuk ke root container lalu meng-exec perintah exec dengan
+// urutan pengaturan keamanan yang sama seperti startContainerInit
+func startExecInit(config initConfig) error {
+	// Mount namespace, root, capability, dan filter seccomp berlaku per
+	// thread, jadi goroutine ini dikunci ke thread yang nantinya melakukan
+	// exec
+	runtime.LockOSThread()
+
+	// Atribut AppArmor ditulis lewat /proc host, sebelum thread pindah ke
+	// mount namespace container. Transisinya terjadi saat exec.
+	if config.AppArmorProfile != "" {
+		if err := applyAppArmorProfile(config.AppArmorProfile); err != nil {
+			return fmt.Errorf("gagal mengatur profil AppArmor %s: %v", config.AppArmorProfile, err)
+		}
+	}
+
+	mntNs := os.NewFile(uintptr(execMntNsFd), "mnt-ns")
+	root := os.NewFile(uintptr(execRootFd), "root")
+	if mntNs == nil || root == nil {
+		return fmt.Errorf("namespace container tidak tersedia")
+	}
+	if err := enterContainerRoot(mntNs, root, config.Cwd); err != nil {
+		return err
 	}
 
-	// Hapus "%" jika ada
-	limit = strings.TrimSuffix(limit, "%")
-	
-	value, err := strconv.ParseUint(limit, 10, 64)
+	cmdPath, err := lookPathInEnv(config.Args[0], config.Env)
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	// Start pipe ditutup otomatis oleh kernel jika exec berhasil
+	syscall.CloseOnExec(startPipeFd)
+
+	if err := applyProcessSecurity(config.Security, config.Seccomp); err != nil {
+		return err
 	}
 
-	// Batasi nilai antara 0-100%
-	if value > 100 {
-		value = 100
+	if err := syscall.Exec(cmdPath, config.Args, config.Env); err != nil {
+		return fmt.Errorf("gagal menjalankan %s di container: %v", config.Args[0], err)
 	}
+	return nil
+}
 
-	// Konversi persentase ke jumlah dari 100000
-	// 100% = 100000
-	cpuValue := (value * 100000) / 100
+// defaultPath dipakai jika environment container tidak memiliki PATH
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// lookPathInEnv mencari executable menggunakan PATH dari environment container
+func lookPathInEnv(file string, env []string) (string, error) {
+	if strings.Contains(file, "/") {
+		return file, nil
+	}
+
+	path := defaultPath
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "PATH=") {
+			path = strings.TrimPrefix(kv, "PATH=")
+		}
+	}
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
+		}
+		candidate := filepath.Join(dir, file)
+		info, err := os.Stat(candidate)
+		if err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return candidate, nil
+		}
+	}
 
-	return cpuValue, nil
+	return "", fmt.Errorf("executable %q tidak ditemukan di PATH container", file)
 }
 
 // syscallChroot adalah fungsi pembungkus untuk chroot agar kode tetap berjalan di Windows
@@ -0,0 +1,83 @@
+package container
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseLabels mengubah daftar "KEY=VALUE" menjadi map label. Label tanpa
+// "=" mendapat nilai kosong, sama seperti Docker.
+func ParseLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			return nil, fmt.Errorf("label tidak valid: %q (gunakan KEY=VALUE)", value)
+		}
+		labels[key] = ""
+		if len(parts) == 2 {
+			labels[key] = parts[1]
+		}
+	}
+	return labels, nil
+}
+
+// ReadLabelFile membaca file label berisi satu KEY=VALUE per baris. Baris
+// kosong dan baris yang diawali # diabaikan.
+func ReadLabelFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka file label: %v", err)
+	}
+	defer file.Close()
+
+	var labels []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		labels = append(labels, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gagal membaca file label %s: %v", path, err)
+	}
+	return labels, nil
+}
+
+// mergeLabels menggabungkan beberapa map label. Label di map yang lebih
+// belakang menimpa label dengan key yang sama.
+func mergeLabels(sets ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, set := range sets {
+		for key, value := range set {
+			merged[key] = value
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// imageLabels mengembalikan label image dari metadata registry lokal
+func imageLabels(imageName string) map[string]string {
+	images, err := ListImages()
+	if err != nil {
+		return nil
+	}
+	name, tag := parseImageNameTag(imageName)
+	if tag == "" {
+		tag = "latest"
+	}
+	for _, img := range images {
+		if img.Name == name && img.Tag == tag {
+			return img.Labels
+		}
+	}
+	return nil
+}
@@ -7,7 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+
+	"github.com/user/minidocker/pkg/seccomp"
 )
 
 func init() {
@@ -17,11 +22,18 @@ func init() {
 	// Inisialisasi fungsi-fungsi syscall Linux
 	internalSyscallChroot = syscall.Chroot
 	internalSetupMounts = setupMountsLinux
-	internalSetupCgroups = setupCgroupsLinux
 }
 
 // Implementasi khusus Linux dari setupMounts
-func setupMountsLinux(rootfs string) error {
+func setupMountsLinux(config initConfig) error {
+	rootfs := config.Rootfs
+
+	// Jadikan semua mount private agar mount container tidak bocor ke host
+	// dan pivot_root tidak ditolak karena shared mount
+	if err := syscall.Mount("", "/", "", syscall.MS_PRIVATE|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("gagal membuat mount private: %v", err)
+	}
+
 	// Mount procfs
 	procPath := filepath.Join(rootfs, "proc")
 	if err := os.MkdirAll(procPath, 0755); err != nil {
@@ -52,11 +64,164 @@ func setupMountsLinux(rootfs string) error {
 		return fmt.Errorf("gagal mount /tmp: %v", err)
 	}
 
+	// Masked dan read-only path dipasang sebelum pivot_root karena masking
+	// file memakai /dev/null milik host. Bind mount rekursif di pivotRoot
+	// ikut membawa mount-mount ini.
+	if err := maskPaths(rootfs, config.Security.MaskedPaths); err != nil {
+		return err
+	}
+	if err := readonlyPaths(rootfs, config.Security.ReadonlyPaths); err != nil {
+		return err
+	}
+
 	// Pivot root
 	if err := pivotRoot(rootfs); err != nil {
 		return fmt.Errorf("gagal pivot root: %v", err)
 	}
 
+	// Tmpfs --tmpfs dipasang sebelum root dibuat read-only agar direktori
+	// tujuannya masih bisa dibuat
+	if err := mountTmpfs(config.Tmpfs); err != nil {
+		return err
+	}
+
+	if config.Security.ReadOnlyRootfs {
+		if err := syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+			return fmt.Errorf("gagal membuat rootfs read-only: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// containerPath mengembalikan lokasi path container di bawah rootfs setelah
+// symlink di-resolve. Path yang tidak ada mengembalikan "" dan path yang
+// keluar dari rootfs lewat symlink ditolak.
+func containerPath(rootfs, path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(rootfs, path))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("gagal memeriksa %s: %v", path, err)
+	}
+	if rootfs, err = filepath.EvalSymlinks(rootfs); err != nil {
+		return "", fmt.Errorf("gagal memeriksa rootfs: %v", err)
+	}
+	if resolved != rootfs && !strings.HasPrefix(resolved, rootfs+"/") {
+		return "", fmt.Errorf("path %s keluar dari rootfs container", path)
+	}
+	return resolved, nil
+}
+
+// maskPaths menyembunyikan path dari container: direktori ditutup tmpfs
+// read-only kosong dan file ditutup bind mount /dev/null
+func maskPaths(rootfs string, paths []string) error {
+	for _, path := range paths {
+		target, err := containerPath(rootfs, path)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+		info, err := os.Stat(target)
+		if err != nil {
+			return fmt.Errorf("gagal memeriksa %s: %v", path, err)
+		}
+		if info.IsDir() {
+			err = syscall.Mount("tmpfs", target, "tmpfs", syscall.MS_RDONLY, "")
+		} else {
+			err = syscall.Mount("/dev/null", target, "", syscall.MS_BIND, "")
+		}
+		if err != nil {
+			return fmt.Errorf("gagal menyembunyikan %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// readonlyPaths membuat path container read-only dengan bind mount ke
+// dirinya sendiri lalu remount dengan MS_RDONLY
+func readonlyPaths(rootfs string, paths []string) error {
+	for _, path := range paths {
+		target, err := containerPath(rootfs, path)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+		if err := syscall.Mount(target, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+			return fmt.Errorf("gagal bind mount %s: %v", path, err)
+		}
+		if err := syscall.Mount("", target, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+			return fmt.Errorf("gagal membuat %s read-only: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// mountTmpfs memasang tmpfs --tmpfs di root container yang sudah di-pivot
+func mountTmpfs(tmpfs map[string]string) error {
+	targets := make([]string, 0, len(tmpfs))
+	for target := range tmpfs {
+		targets = append(targets, target)
+	}
+	// Urutkan agar path induk dipasang sebelum path di dalamnya
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return fmt.Errorf("gagal membuat direktori tmpfs %s: %v", target, err)
+		}
+		flags, data := tmpfsMountOptions(tmpfs[target])
+		if err := syscall.Mount("tmpfs", target, "tmpfs", flags, data); err != nil {
+			return fmt.Errorf("gagal mount tmpfs %s: %v", target, err)
+		}
+	}
+	return nil
+}
+
+// tmpfsMountOptions mengubah opsi --tmpfs menjadi flag mount dan data tmpfs.
+// Seperti Docker, defaultnya noexec, nosuid, dan nodev.
+func tmpfsMountOptions(options string) (uintptr, string) {
+	flags := uintptr(syscall.MS_NOEXEC | syscall.MS_NOSUID | syscall.MS_NODEV)
+	var data []string
+	if options == "" {
+		return flags, ""
+	}
+	for _, opt := range strings.Split(options, ",") {
+		switch opt {
+		case "ro":
+			flags |= syscall.MS_RDONLY
+		case "rw":
+			flags &^= syscall.MS_RDONLY
+		case "exec":
+			flags &^= syscall.MS_NOEXEC
+		case "noexec":
+			flags |= syscall.MS_NOEXEC
+		case "suid":
+			flags &^= syscall.MS_NOSUID
+		case "nosuid":
+			flags |= syscall.MS_NOSUID
+		case "dev":
+			flags &^= syscall.MS_NODEV
+		case "nodev":
+			flags |= syscall.MS_NODEV
+		default:
+			data = append(data, opt)
+		}
+	}
+	return flags, strings.Join(data, ",")
+}
+
+// setNoNewPrivs mengaktifkan PR_SET_NO_NEW_PRIVS untuk thread ini sehingga
+// execve tidak bisa menambah privilege lewat setuid atau file capability
+func setNoNewPrivs() error {
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0); errno != 0 {
+		return errno
+	}
 	return nil
 }
 
@@ -97,50 +262,168 @@ func pivotRoot(rootfs string) error {
 	return nil
 }
 
-// setupCgroupsLinux mengatur cgroups untuk batasi resource container
-func setupCgroupsLinux() error {
-	// Tentukan pid saat ini
-	pid := os.Getpid()
+// Implementasi khusus Linux untuk createLinuxSysProcAttr
+func createLinuxSysProcAttrImpl() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUTS | // Hostname & domain
+			syscall.CLONE_NEWPID | // Process ID
+			syscall.CLONE_NEWNS | // Mount
+			syscall.CLONE_NEWNET | // Network
+			syscall.CLONE_NEWIPC, // Inter-process communication
+	}
+}
 
-	// Setup cgroup v2
-	cgroupRoot := "/sys/fs/cgroup"
-	containerCgroup := filepath.Join(cgroupRoot, fmt.Sprintf("minidocker_%d", pid))
+// createShimSysProcAttr menjalankan shim di session baru agar tidak ikut
+// berhenti ketika terminal atau CLI minidocker ditutup
+func createShimSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
 
-	// Membuat cgroup baru
-	if err := os.Mkdir(containerCgroup, 0755); err != nil {
-		return fmt.Errorf("gagal membuat cgroup: %v", err)
+// unmountAll melepas semua mount point di bawah dir, mulai dari yang terdalam
+func unmountAll(dir string) error {
+	data, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return err
 	}
 
-	// Batasi memory (64 MB)
-	memLimit := "67108864" // 64MB
-	memFile := filepath.Join(containerCgroup, "memory.max")
-	if err := os.WriteFile(memFile, []byte(memLimit), 0644); err != nil {
-		return fmt.Errorf("gagal set memory limit: %v", err)
+	var mounts []string
+	for _, line := range strings.Split(string(data), "\n") {
+		// Field ke-5 mountinfo adalah mount point
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		mountPoint := unescapeMountPath(fields[4])
+		if mountPoint == dir || strings.HasPrefix(mountPoint, dir+"/") {
+			mounts = append(mounts, mountPoint)
+		}
 	}
 
-	// Batasi CPU (10%)
-	cpuLimit := "10000" // 10% dari 100000
-	cpuFile := filepath.Join(containerCgroup, "cpu.max")
-	if err := os.WriteFile(cpuFile, []byte(cpuLimit+" 100000"), 0644); err != nil {
-		return fmt.Errorf("gagal set cpu limit: %v", err)
+	sort.Slice(mounts, func(i, j int) bool {
+		return len(mounts[i]) > len(mounts[j])
+	})
+
+	for _, mountPoint := range mounts {
+		if err := syscall.Unmount(mountPoint, syscall.MNT_DETACH); err != nil && err != syscall.EINVAL {
+			return fmt.Errorf("unmount %s: %v", mountPoint, err)
+		}
 	}
+	return nil
+}
 
-	// Tambahkan pid ke cgroup
-	procsFile := filepath.Join(containerCgroup, "cgroup.procs")
-	if err := os.WriteFile(procsFile, []byte(fmt.Sprintf("%d", pid)), 0644); err != nil {
-		return fmt.Errorf("gagal tambahkan pid ke cgroup: %v", err)
+// unescapeMountPath mengubah escape oktal di mountinfo (misalnya \040 untuk
+// spasi) kembali menjadi karakter aslinya
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
 	}
 
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if value, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// blockDeviceNumber membaca nomor major:minor dari block device di host
+func blockDeviceNumber(path string) (uint32, uint32, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return 0, 0, fmt.Errorf("device %s tidak ditemukan: %v", path, err)
+	}
+	if stat.Mode&syscall.S_IFMT != syscall.S_IFBLK {
+		return 0, 0, fmt.Errorf("%s bukan block device", path)
+	}
+
+	// Format dev_t Linux, sama seperti makedev di glibc
+	rdev := uint64(stat.Rdev)
+	major := uint32((rdev>>8)&0xfff) | uint32((rdev>>32)&^0xfff)
+	minor := uint32(rdev&0xff) | uint32((rdev>>12)&^0xff)
+	return major, minor, nil
+}
+
+// execNamespaces adalah namespace container yang dimasuki helper exec
+// sebelum membuat child, dengan urutan yang sama seperti nsenter. Mount
+// namespace dimasuki oleh child itu sendiri lewat enterContainerRoot.
+var execNamespaces = []string{"ipc", "uts", "net", "pid"}
+
+// joinContainerNamespaces memindahkan thread ini ke namespace container
+// dengan proses pid. Namespace pid hanya berlaku untuk proses anak yang
+// dibuat thread ini. Pemanggil harus mengunci goroutine ke thread ini dan
+// tidak membukanya lagi.
+func joinContainerNamespaces(pid int) error {
+	for _, ns := range execNamespaces {
+		f, err := os.Open(fmt.Sprintf("/proc/%d/ns/%s", pid, ns))
+		if err != nil {
+			return fmt.Errorf("gagal membuka namespace %s container: %v", ns, err)
+		}
+		err = setns(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("gagal masuk ke namespace %s container: %v", ns, err)
+		}
+	}
 	return nil
 }
 
-// Implementasi khusus Linux untuk createLinuxSysProcAttr
-func createLinuxSysProcAttrImpl() *syscall.SysProcAttr {
-	return &syscall.SysProcAttr{
-		Cloneflags: syscall.CLONE_NEWUTS | // Hostname & domain
-			syscall.CLONE_NEWPID | // Process ID
-			syscall.CLONE_NEWNS | // Mount
-			syscall.CLONE_NEWNET | // Network
-			syscall.CLONE_NEWIPC, // Inter-process communication
+// openContainerRoot membuka mount namespace dan root container dengan proses pid
+func openContainerRoot(pid int) (*os.File, *os.File, error) {
+	mntNs, err := os.Open(fmt.Sprintf("/proc/%d/ns/mnt", pid))
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membuka namespace mnt container: %v", err)
+	}
+	root, err := os.Open(fmt.Sprintf("/proc/%d/root", pid))
+	if err != nil {
+		mntNs.Close()
+		return nil, nil, fmt.Errorf("gagal membuka root container: %v", err)
+	}
+	return mntNs, root, nil
+}
+
+// enterContainerRoot memindahkan thread ini ke mount namespace mntNs, lalu
+// ke root dan workDir container seperti nsenter -r -w. Kedua file ditutup
+// agar tidak terbawa ke perintah exec. Pemanggil harus mengunci goroutine
+// ke thread ini dan tidak membukanya lagi.
+func enterContainerRoot(mntNs, root *os.File, workDir string) error {
+	defer mntNs.Close()
+	defer root.Close()
+
+	// Kernel menolak setns ke mount namespace jika root dan direktori kerja
+	// dipakai bersama thread lain
+	if err := syscall.Unshare(syscall.CLONE_FS); err != nil {
+		return fmt.Errorf("gagal memisahkan state filesystem thread: %v", err)
 	}
-} 
\ No newline at end of file
+	if err := setns(mntNs); err != nil {
+		return fmt.Errorf("gagal masuk ke namespace mnt container: %v", err)
+	}
+	if err := syscall.Fchdir(int(root.Fd())); err != nil {
+		return fmt.Errorf("gagal pindah ke root container: %v", err)
+	}
+	if err := syscall.Chroot("."); err != nil {
+		return fmt.Errorf("gagal chroot ke root container: %v", err)
+	}
+	if err := syscall.Chdir(workDir); err != nil {
+		return fmt.Errorf("gagal chdir ke %s di container: %v", workDir, err)
+	}
+	return nil
+}
+
+// setns memindahkan thread ini ke namespace yang dirujuk f. Paket syscall
+// tidak menyediakan setns, jadi nomornya diambil dari tabel seccomp.
+func setns(f *os.File) error {
+	nr, ok := seccomp.NativeSyscall("setns")
+	if !ok {
+		return fmt.Errorf("nomor syscall setns tidak diketahui untuk arsitektur ini")
+	}
+	if _, _, errno := syscall.RawSyscall(uintptr(nr), f.Fd(), 0, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
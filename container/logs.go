@@ -1,33 +1,45 @@
 package container
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/user/minidocker/pkg/config"
 )
 
 // LogsFromContainer membaca dan menampilkan log dari container dengan ID tertentu
 // Parameter follow menentukan apakah logs akan di-follow secara real-time
 // Fungsi ini berbeda dengan ContainerLogs di container.go untuk menghindari redeclaration
 func LogsFromContainer(id string, follow bool) error {
-	// Verifikasi container ada
-	containerPath := filepath.Join(ContainerDir, id)
-	if _, err := os.Stat(containerPath); os.IsNotExist(err) {
-		return fmt.Errorf("container dengan ID '%s' tidak ditemukan", id)
+	if follow {
+		fmt.Fprintf(output, "Menampilkan logs untuk container %s (CTRL+C untuk keluar):\n", id)
 	}
+	return WriteContainerLogs(context.Background(), id, follow, os.Stdout)
+}
 
-	// Baca config untuk memastikan container valid
-	configPath := filepath.Join(containerPath, "config.json")
-	_, err := os.ReadFile(configPath)
+// WriteContainerLogs menulis log container ke w. Dengan follow, log baru
+// terus ditulis sampai container berhenti, dihapus, atau ctx selesai.
+func WriteContainerLogs(ctx context.Context, containerID string, follow bool, w io.Writer) error {
+	id, err := resolveContainerID(containerID)
 	if err != nil {
+		return err
+	}
+
+	// Baca config untuk memastikan container valid
+	var container Container
+	if err := containerSchema.Read(containerConfigPath(id), &container); err != nil {
 		return fmt.Errorf("gagal membaca config container: %v", err)
 	}
+	if container.LogDriver == config.LogDriverNone {
+		return fmt.Errorf("container '%s' memakai log driver none, log tidak tersedia", id)
+	}
 
 	// Path ke file log container
-	logPath := filepath.Join(containerPath, "container.log")
+	logPath := filepath.Join(ContainerDir, id, "container.log")
 	if _, err := os.Stat(logPath); os.IsNotExist(err) {
 		return fmt.Errorf("file log untuk container '%s' tidak ditemukan", id)
 	}
@@ -39,60 +51,45 @@ func LogsFromContainer(id string, follow bool) error {
 	}
 	defer file.Close()
 
-	// Jika tidak follow, cukup tampilkan log yang ada
-	if !follow {
-		_, err = io.Copy(os.Stdout, file)
+	// Tampilkan log yang sudah ada
+	if _, err := io.Copy(w, file); err != nil || !follow {
 		return err
 	}
 
-	// Jika follow, gunakan scanner untuk membaca log line-by-line
-	// dan tetap pantau file untuk perubahan baru
-	fmt.Printf("Menampilkan logs untuk container %s (CTRL+C untuk keluar):\n", id)
-	
-	scanner := bufio.NewScanner(file)
-	
-	// Pertama, tampilkan log yang sudah ada
-	for scanner.Scan() {
-		fmt.Println(scanner.Text())
-	}
-
-	// Kemudian, pantau file untuk perubahan baru
+	// Pantau file untuk output baru. Read berikutnya melanjutkan dari posisi
+	// terakhir, jadi cukup salin ulang setiap interval.
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
 	for {
-		// Dapatkan posisi saat ini di file
-		currentPos, err := file.Seek(0, io.SeekCurrent)
-		if err != nil {
-			return fmt.Errorf("error saat membaca posisi file: %v", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
 		}
 
-		// Tunggu sebentar untuk perubahan file
-		time.Sleep(100 * time.Millisecond)
-
-		// Dapatkan ukuran file terbaru
-		fileInfo, err := os.Stat(logPath)
-		if err != nil {
-			return fmt.Errorf("error saat membaca info file: %v", err)
+		if _, err := io.Copy(w, file); err != nil {
+			return err
 		}
 
-		// Jika file bertambah ukurannya
-		if fileInfo.Size() > currentPos {
-			// Reset scanner dengan file yang sama
-			scanner = bufio.NewScanner(file)
-			
-			// Lanjutkan membaca dari posisi terakhir
-			_, err = file.Seek(currentPos, io.SeekStart)
-			if err != nil {
-				return fmt.Errorf("error saat menyetel posisi file: %v", err)
-			}
-
-			// Baca dan tampilkan baris baru
-			for scanner.Scan() {
-				fmt.Println(scanner.Text())
-			}
+		// Berhenti setelah container berhenti atau dihapus; container yang
+		// menunggu restart tetap diikuti
+		current, err := getContainer(id)
+		if err != nil || (!isContainerActive(current) && current.Status != StateRestarting) {
+			_, err = io.Copy(w, file)
+			return err
 		}
+	}
+}
 
-		// Verifikasi container masih ada
-		if _, err := os.Stat(containerPath); os.IsNotExist(err) {
-			return fmt.Errorf("container '%s' dihentikan", id)
-		}
+// openContainerLog membuka tujuan output container sesuai log driver.
+// Dengan driver none, output dibuang ke /dev/null.
+func openContainerLog(container *Container) (*os.File, error) {
+	if container.LogDriver == config.LogDriverNone || container.LogFile == "" {
+		return os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	}
+	logOutput, err := os.OpenFile(container.LogFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka file log: %v", err)
 	}
-} 
\ No newline at end of file
+	return logOutput, nil
+}
@@ -0,0 +1,170 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/user/minidocker/pkg/config"
+	"github.com/user/minidocker/pkg/store"
+)
+
+// NamesDir adalah indeks nama container: satu file per nama yang berisi ID
+// container pemiliknya. Indeks diubah sambil memegang lock NamesDir dan
+// direktori container selalu dibuat sebelum namanya didaftarkan, sehingga
+// dua container tidak pernah mendapat nama yang sama, bahkan jika run
+// berjalan bersamaan.
+var NamesDir = filepath.Join(config.DefaultRoot, "names")
+
+// ShortIDLength adalah panjang ID container versi pendek yang ditampilkan di ps
+const ShortIDLength = 12
+
+// ErrAmbiguousID dikembalikan jika prefix ID cocok dengan lebih dari satu container
+var ErrAmbiguousID = errors.New("prefix ID ambigu")
+
+// validContainerName sama dengan aturan nama container Docker. Nama juga
+// dipakai sebagai nama file di NamesDir, sehingga "/" tidak diperbolehkan.
+var validContainerName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ShortID mengembalikan 12 karakter pertama ID container
+func ShortID(id string) string {
+	if len(id) > ShortIDLength {
+		return id[:ShortIDLength]
+	}
+	return id
+}
+
+// lockNames mengunci indeks nama. Lock dipasang pada NamesDir, bukan per
+// nama, karena file lock seperti "web.lock" juga nama container yang valid.
+func lockNames() (*store.FileLock, error) {
+	if err := os.MkdirAll(NamesDir, 0755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori indeks nama: %v", err)
+	}
+	return store.Lock(NamesDir)
+}
+
+// reserveName mendaftarkan nama untuk container id di indeks nama.
+// Direktori container id harus sudah ada, karena nama yang pemiliknya tidak
+// memiliki direktori dianggap basi dan boleh diambil container lain.
+func reserveName(name, id string) error {
+	if !validContainerName.MatchString(name) {
+		return fmt.Errorf("nama container %q tidak valid: hanya boleh berisi [a-zA-Z0-9_.-] dan diawali huruf atau angka", name)
+	}
+	lock, err := lockNames()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// Container lama (sebelum ada indeks nama) memakai nama sebagai ID
+	if _, err := os.Stat(filepath.Join(ContainerDir, name)); err == nil {
+		return errorf(ErrConflict, "nama container %q sudah dipakai oleh container %s", name, name)
+	}
+
+	path := filepath.Join(NamesDir, name)
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			defer file.Close()
+			if _, err := file.WriteString(id); err != nil {
+				os.Remove(path)
+				return fmt.Errorf("gagal menulis indeks nama: %v", err)
+			}
+			return nil
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("gagal mendaftarkan nama container: %v", err)
+		}
+
+		// Nama yang ditinggalkan container yang sudah tidak ada boleh dipakai
+		// ulang. Pemeriksaan dan penghapusan terjadi di bawah lock sehingga
+		// reservasi lain tidak bisa menyela.
+		owner, ok := lookupName(name)
+		if ok {
+			if _, err := os.Stat(filepath.Join(ContainerDir, owner)); err == nil {
+				return errorf(ErrConflict, "nama container %q sudah dipakai oleh container %s", name, ShortID(owner))
+			}
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("gagal menghapus indeks nama lama: %v", err)
+		}
+	}
+}
+
+// releaseName menghapus nama dari indeks jika masih dimiliki container id
+func releaseName(name, id string) {
+	if name == "" {
+		return
+	}
+	lock, err := lockNames()
+	if err != nil {
+		return
+	}
+	defer lock.Unlock()
+	if owner, ok := lookupName(name); ok && owner == id {
+		os.Remove(filepath.Join(NamesDir, name))
+	}
+}
+
+// lookupName mencari ID container pemilik nama
+func lookupName(name string) (string, bool) {
+	if !validContainerName.MatchString(name) {
+		return "", false
+	}
+	data, err := os.ReadFile(filepath.Join(NamesDir, name))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// resolveContainerID mengubah referensi container menjadi ID lengkap.
+// Urutan pencarian sama seperti Docker: ID lengkap, nama, lalu prefix ID
+// yang unik.
+func resolveContainerID(ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("ID atau nama container kosong")
+	}
+	if strings.ContainsAny(ref, `/\`) || ref == "." || ref == ".." {
+		return "", errorf(ErrNotFound, "container '%s' tidak ditemukan", ref)
+	}
+
+	if _, err := os.Stat(filepath.Join(ContainerDir, ref, "config.json")); err == nil {
+		return ref, nil
+	}
+
+	if id, ok := lookupName(ref); ok {
+		if _, err := os.Stat(filepath.Join(ContainerDir, id)); err == nil {
+			return id, nil
+		}
+	}
+
+	entries, err := os.ReadDir(ContainerDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("gagal membaca direktori container: %v", err)
+	}
+	var matches []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ref) {
+			matches = append(matches, entry.Name())
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", errorf(ErrNotFound, "container '%s' tidak ditemukan", ref)
+	case 1:
+		return matches[0], nil
+	}
+
+	sort.Strings(matches)
+	for i, id := range matches {
+		matches[i] = ShortID(id)
+	}
+	return "", fmt.Errorf("%w: '%s' cocok dengan %d container: %s (gunakan prefix yang lebih panjang)",
+		ErrAmbiguousID, ref, len(matches), strings.Join(matches, ", "))
+}
@@ -0,0 +1,192 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// useTempDirs mengarahkan ContainerDir dan NamesDir ke direktori sementara
+func useTempDirs(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	oldContainers, oldNames := ContainerDir, NamesDir
+	ContainerDir = filepath.Join(root, "containers")
+	NamesDir = filepath.Join(root, "names")
+	t.Cleanup(func() {
+		ContainerDir, NamesDir = oldContainers, oldNames
+	})
+	if err := os.MkdirAll(ContainerDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeContainer membuat direktori container dengan config.json kosong
+func fakeContainer(t *testing.T, id string) {
+	t.Helper()
+	dir := filepath.Join(ContainerDir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveContainerID(t *testing.T) {
+	useTempDirs(t)
+	fakeContainer(t, "abc123")
+	fakeContainer(t, "abd456")
+	fakeContainer(t, "fff789")
+	if err := reserveName("web", "fff789"); err != nil {
+		t.Fatal(err)
+	}
+	// Nama yang juga prefix ID container lain tetap dianggap nama
+	if err := reserveName("abc", "abd456"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+		err  error
+	}{
+		{ref: "abc123", want: "abc123"},
+		{ref: "web", want: "fff789"},
+		{ref: "abc", want: "abd456"},
+		{ref: "abd", want: "abd456"},
+		{ref: "ff", want: "fff789"},
+		{ref: "ab", err: ErrAmbiguousID},
+		{ref: "zzz", err: ErrNotFound},
+		{ref: "../abc123", err: ErrNotFound},
+		{ref: "..", err: ErrNotFound},
+	}
+	for _, tt := range tests {
+		got, err := resolveContainerID(tt.ref)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("resolveContainerID(%q) error = %v, ingin %v", tt.ref, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("resolveContainerID(%q) = %q, %v, ingin %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	if _, err := resolveContainerID(""); err == nil {
+		t.Error("resolveContainerID(\"\") berhasil, ingin error")
+	}
+}
+
+func TestResolveContainerIDStaleName(t *testing.T) {
+	useTempDirs(t)
+	fakeContainer(t, "abc123")
+	if err := reserveName("web", "dead00"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nama milik container yang sudah tidak ada tidak ditemukan
+	if _, err := resolveContainerID("web"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("resolveContainerID(web) error = %v, ingin %v", err, ErrNotFound)
+	}
+}
+
+func TestReserveName(t *testing.T) {
+	useTempDirs(t)
+	fakeContainer(t, "abc123")
+
+	if err := reserveName("web", "abc123"); err != nil {
+		t.Fatalf("reserveName: %v", err)
+	}
+	if err := reserveName("web", "def456"); !errors.Is(err, ErrConflict) {
+		t.Errorf("reserveName nama yang dipakai container aktif = %v, ingin %v", err, ErrConflict)
+	}
+
+	// Nama yang ditinggalkan container yang sudah dihapus boleh dipakai ulang
+	if err := reserveName("old", "dead00"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reserveName("old", "def456"); err != nil {
+		t.Fatalf("reserveName nama basi: %v", err)
+	}
+	if owner, _ := lookupName("old"); owner != "def456" {
+		t.Errorf("pemilik nama old = %q, ingin def456", owner)
+	}
+
+	// Container lama memakai nama sebagai ID
+	fakeContainer(t, "legacy")
+	if err := reserveName("legacy", "def456"); !errors.Is(err, ErrConflict) {
+		t.Errorf("reserveName nama container lama = %v, ingin %v", err, ErrConflict)
+	}
+
+	for _, name := range []string{"-web", "a/b", "..", "web app"} {
+		if err := reserveName(name, "def456"); err == nil {
+			t.Errorf("reserveName(%q) berhasil, ingin error nama tidak valid", name)
+		}
+	}
+}
+
+func TestReserveNameConcurrent(t *testing.T) {
+	useTempDirs(t)
+	// Nama basi ikut diperebutkan, karena penghapusannya tidak boleh
+	// menghapus reservasi container lain
+	if err := reserveName("old", "dead00"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"web", "old"} {
+		const n = 200
+		var wg sync.WaitGroup
+		errs := make([]error, n)
+		for i := 0; i < n; i++ {
+			// Seperti CreateContainer, direktori container dibuat lebih dulu
+			id := fmt.Sprintf("%s%02d", name, i)
+			fakeContainer(t, id)
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				errs[i] = reserveName(name, id)
+			}(i, id)
+		}
+		wg.Wait()
+
+		winner := ""
+		for i, err := range errs {
+			if err == nil {
+				if winner != "" {
+					t.Fatalf("nama %s didaftarkan untuk %s dan %s%02d", name, winner, name, i)
+				}
+				winner = fmt.Sprintf("%s%02d", name, i)
+			} else if !errors.Is(err, ErrConflict) {
+				t.Errorf("reserveName(%s) error = %v, ingin %v", name, err, ErrConflict)
+			}
+		}
+		if winner == "" {
+			t.Fatalf("tidak ada reservasi nama %s yang berhasil", name)
+		}
+		if owner, _ := lookupName(name); owner != winner {
+			t.Errorf("pemilik nama %s = %q, ingin %q", name, owner, winner)
+		}
+	}
+}
+
+func TestReleaseName(t *testing.T) {
+	useTempDirs(t)
+	if err := reserveName("web", "abc123"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hanya pemilik yang bisa melepas nama
+	releaseName("web", "def456")
+	if _, ok := lookupName("web"); !ok {
+		t.Fatal("nama dilepas oleh container yang bukan pemiliknya")
+	}
+	releaseName("web", "abc123")
+	if _, ok := lookupName("web"); ok {
+		t.Error("nama masih terdaftar setelah dilepas pemiliknya")
+	}
+}
@@ -0,0 +1,126 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PauseContainer membekukan semua proses container lewat cgroup freezer.
+// Pemeriksaan status, freeze, dan pencatatan status dilakukan selama
+// container dikunci agar tidak bertabrakan dengan pause/unpause lain.
+func PauseContainer(containerID string) error {
+	var paused Container
+	if err := updateContainer(containerID, func(container *Container) error {
+		if container.Status == StatePaused {
+			return errorf(ErrConflict, "container %s sudah di-pause", containerID)
+		}
+		if !isContainerActive(*container) {
+			return errorf(ErrConflict, "container %s tidak berjalan", containerID)
+		}
+		if container.CgroupPath == "" {
+			return fmt.Errorf("container %s tidak memiliki cgroup, pause tidak didukung", containerID)
+		}
+
+		if err := newCgroup(container.CgroupPath).freeze(true); err != nil {
+			return fmt.Errorf("gagal pause container %s: %v", containerID, err)
+		}
+		container.Status = StatePaused
+		paused = *container
+		return nil
+	}); err != nil {
+		return err
+	}
+	recordContainerEvent(paused, "pause", nil)
+	return nil
+}
+
+// UnpauseContainer melanjutkan container yang di-pause
+func UnpauseContainer(containerID string) error {
+	var unpaused Container
+	if err := updateContainer(containerID, func(container *Container) error {
+		if container.Status != StatePaused {
+			return errorf(ErrConflict, "container %s tidak sedang di-pause", containerID)
+		}
+
+		if err := thawContainer(*container); err != nil {
+			return err
+		}
+		container.Status = StateRunning
+		unpaused = *container
+		return nil
+	}); err != nil {
+		return err
+	}
+	recordContainerEvent(unpaused, "unpause", nil)
+	return nil
+}
+
+// thawContainer mencairkan cgroup container tanpa mengubah status
+func thawContainer(container Container) error {
+	if container.CgroupPath == "" {
+		return nil
+	}
+	if err := newCgroup(container.CgroupPath).freeze(false); err != nil {
+		return fmt.Errorf("gagal unpause container %s: %v", container.ID, err)
+	}
+	return nil
+}
+
+// isContainerActive memeriksa apakah proses container masih ada, baik
+// berjalan maupun di-pause
+func isContainerActive(container Container) bool {
+	if container.Status != StateRunning && container.Status != StatePaused {
+		return false
+	}
+	return processExists(container.Pid)
+}
+
+// freeze membekukan (frozen=true) atau mencairkan semua proses di cgroup.
+// cgroup v2 memakai cgroup.freeze, cgroup v1 memakai controller freezer.
+// Fungsi ini menunggu sampai kernel selesai mengubah state.
+func (cg *cgroup) freeze(frozen bool) error {
+	var controller, file, value, stateFile, want string
+	if cg.v2 {
+		controller, file, stateFile = "", "cgroup.freeze", "cgroup.events"
+		value, want = "0", "frozen 0"
+		if frozen {
+			value, want = "1", "frozen 1"
+		}
+	} else {
+		controller, file, stateFile = "freezer", "freezer.state", "freezer.state"
+		value = "THAWED"
+		if frozen {
+			value = "FROZEN"
+		}
+		want = value
+	}
+
+	if err := cg.write(controller, file, value); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(filepath.Join(cg.dir(controller), stateFile))
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == want {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("cgroup tidak mencapai state %q", want)
+		}
+		// cgroup v1 bisa tertahan di FREEZING, tulis ulang agar dicoba lagi
+		if !cg.v2 && frozen {
+			cg.write(controller, file, value)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,10 +1,8 @@
 package container
 
 import (
-	"os"
 	"runtime"
 	"syscall"
-	"time"
 )
 
 // Deklarasi fungsi yang diimplementasikan di linux.go dan non_linux.go
@@ -22,24 +20,3 @@ func createLinuxSysProcAttr() *syscall.SysProcAttr {
 	// Panggil implementasi platform spesifik
 	return createLinuxSysProcAttrInternal()
 }
-
-// killProcess membunuh proses dengan cara yang sesuai platform
-func killProcess(process *os.Process) error {
-	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
-		// Di Unix-like OS, kita kirim SIGTERM lalu SIGKILL
-		if err := process.Signal(syscall.SIGTERM); err != nil {
-			return err
-		}
-
-		// Tunggu beberapa saat lalu kirim SIGKILL jika masih berjalan
-		time.Sleep(2 * time.Second)
-		if err := process.Signal(syscall.SIGKILL); err != nil {
-			// Abaikan error ini karena proses mungkin sudah berhenti
-			return nil
-		}
-		return nil
-	} else {
-		// Di Windows, kita hanya bisa menggunakan Kill()
-		return process.Kill()
-	}
-} 
\ No newline at end of file
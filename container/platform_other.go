@@ -0,0 +1,49 @@
+//go:build !linux
+// +build !linux
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Catatan: non_linux.go berakhiran _linux sehingga hanya dikompilasi di
+// Linux, padahal build tag-nya !linux. Implementasi untuk platform
+// non-Linux karena itu ditempatkan di file ini.
+
+// createShimSysProcAttr tidak memerlukan atribut khusus di platform non-Linux
+func createShimSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{}
+}
+
+// unmountAll tidak diperlukan di platform non-Linux karena tidak ada mount
+func unmountAll(dir string) error {
+	return nil
+}
+
+// setNoNewPrivs tidak didukung di platform non-Linux
+func setNoNewPrivs() error {
+	return fmt.Errorf("no_new_privs hanya didukung di Linux")
+}
+
+// joinContainerNamespaces tidak didukung di platform non-Linux
+func joinContainerNamespaces(pid int) error {
+	return fmt.Errorf("exec hanya didukung di Linux")
+}
+
+// openContainerRoot tidak didukung di platform non-Linux
+func openContainerRoot(pid int) (*os.File, *os.File, error) {
+	return nil, nil, fmt.Errorf("exec hanya didukung di Linux")
+}
+
+// enterContainerRoot tidak didukung di platform non-Linux
+func enterContainerRoot(mntNs, root *os.File, workDir string) error {
+	return fmt.Errorf("exec hanya didukung di Linux")
+}
+
+// blockDeviceNumber tidak tersedia di platform non-Linux
+func blockDeviceNumber(path string) (uint32, uint32, error) {
+	return 0, 0, fmt.Errorf("batas IO device hanya didukung di Linux")
+}
@@ -0,0 +1,267 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// securityProfileExtensions adalah ekstensi file profil keamanan yang
+// dikenali. Jika ada beberapa file dengan nama yang sama, yang pertama dipakai.
+var securityProfileExtensions = []string{".json", ".yaml", ".yml"}
+
+// validSecurityProfileName membatasi nama profil buatan user agar selalu
+// menunjuk ke file di dalam SecurityProfilesDir
+var validSecurityProfileName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// builtinSecurityProfiles adalah profil bawaan, diurutkan untuk ditampilkan
+var builtinSecurityProfiles = []func() SecurityProfile{
+	DefaultSecurityProfile,
+	RestrictedSecurityProfile,
+	PrivilegedSecurityProfile,
+}
+
+// securityProfileFile adalah isi file profil keamanan di SecurityProfilesDir
+// (JSON atau YAML). Nama profil diambil dari nama file. Field yang tidak
+// diisi diwarisi dari profil Inherits, atau dari profil default jika
+// Inherits kosong.
+type securityProfileFile struct {
+	Inherits        string    `json:"inherits"`
+	Description     string    `json:"description"`
+	SeccompProfile  *string   `json:"seccomp_profile"`
+	Capabilities    *[]string `json:"capabilities"`
+	CapAdd          []string  `json:"cap_add"`
+	CapDrop         []string  `json:"cap_drop"`
+	NoNewPrivs      *bool     `json:"no_new_privs"`
+	ReadOnlyRootfs  *bool     `json:"read_only_rootfs"`
+	AppArmorProfile *string   `json:"apparmor_profile"`
+	Label           *[]string `json:"label"`
+	MaskedPaths     *[]string `json:"masked_paths"`
+	ReadonlyPaths   *[]string `json:"readonly_paths"`
+}
+
+// SecurityProfileInfo adalah ringkasan profil keamanan untuk daftar profil
+type SecurityProfileInfo struct {
+	Name        string `json:"name"`
+	Inherits    string `json:"inherits,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Source adalah "builtin" atau path file profil
+	Source string `json:"source"`
+	// Error berisi alasan jika file profil tidak bisa dipakai
+	Error string `json:"error,omitempty"`
+}
+
+// builtinSecurityProfile mengembalikan profil bawaan bernama name
+func builtinSecurityProfile(name string) (SecurityProfile, bool) {
+	for _, builtin := range builtinSecurityProfiles {
+		if profile := builtin(); profile.Name == strings.ToLower(name) {
+			return profile, true
+		}
+	}
+	return SecurityProfile{}, false
+}
+
+// ListSecurityProfiles mengembalikan profil bawaan diikuti profil di
+// SecurityProfilesDir, diurutkan berdasarkan nama. Profil yang tidak valid
+// tetap dikembalikan dengan Error terisi.
+func ListSecurityProfiles() ([]SecurityProfileInfo, error) {
+	var infos []SecurityProfileInfo
+	for _, builtin := range builtinSecurityProfiles {
+		profile := builtin()
+		infos = append(infos, SecurityProfileInfo{
+			Name:        profile.Name,
+			Description: profile.Description,
+			Source:      "builtin",
+		})
+	}
+
+	entries, err := os.ReadDir(SecurityProfilesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return infos, nil
+		}
+		return nil, fmt.Errorf("gagal membaca direktori profil keamanan: %v", err)
+	}
+
+	// os.ReadDir sudah mengurutkan berdasarkan nama file
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name, ok := securityProfileName(entry.Name())
+		if !ok || entry.IsDir() || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		info := SecurityProfileInfo{Name: name, Source: findSecurityProfileFile(name)}
+		if _, builtin := builtinSecurityProfile(name); builtin {
+			info.Error = "nama profil bawaan tidak bisa ditimpa"
+		} else if profile, err := GetSecurityProfile(name); err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Inherits = profile.Inherits
+			info.Description = profile.Description
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// securityProfileName mengembalikan nama profil dari nama file profil
+func securityProfileName(file string) (string, bool) {
+	ext := filepath.Ext(file)
+	for _, known := range securityProfileExtensions {
+		if ext == known {
+			name := strings.TrimSuffix(file, ext)
+			return name, validSecurityProfileName.MatchString(name)
+		}
+	}
+	return "", false
+}
+
+// findSecurityProfileFile mencari file profil bernama name di
+// SecurityProfilesDir. String kosong berarti profil tidak ada.
+func findSecurityProfileFile(name string) string {
+	if !validSecurityProfileName.MatchString(name) {
+		return ""
+	}
+	for _, ext := range securityProfileExtensions {
+		file := filepath.Join(SecurityProfilesDir, name+ext)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+	return ""
+}
+
+// loadSecurityProfile memuat profil buatan user beserta profil yang
+// diwarisinya. chain berisi profil yang sedang dimuat untuk mendeteksi
+// pewarisan melingkar.
+func loadSecurityProfile(name string, chain []string) (SecurityProfile, error) {
+	if profile, ok := builtinSecurityProfile(name); ok {
+		return profile, nil
+	}
+	for _, loading := range chain {
+		if loading == name {
+			return SecurityProfile{}, fmt.Errorf("pewarisan profil keamanan melingkar: %s -> %s", strings.Join(chain, " -> "), name)
+		}
+	}
+
+	file := findSecurityProfileFile(name)
+	if file == "" {
+		return SecurityProfile{}, fmt.Errorf("profil keamanan '%s' tidak dikenal", name)
+	}
+	spec, err := readSecurityProfileFile(file)
+	if err != nil {
+		return SecurityProfile{}, err
+	}
+
+	parentName := spec.Inherits
+	if parentName == "" {
+		parentName = "default"
+	}
+	parent, err := loadSecurityProfile(parentName, append(chain, name))
+	if err != nil {
+		return SecurityProfile{}, err
+	}
+	profile, err := spec.apply(parent, name, file)
+	if err != nil {
+		return SecurityProfile{}, fmt.Errorf("profil keamanan %s tidak valid: %v", file, err)
+	}
+	return profile, nil
+}
+
+// readSecurityProfileFile membaca file profil JSON atau YAML. Field yang
+// tidak dikenal dianggap error agar salah ketik tidak diam-diam diabaikan.
+func readSecurityProfileFile(file string) (*securityProfileFile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca profil keamanan: %v", err)
+	}
+	if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("profil keamanan %s: YAML tidak valid: %v", file, err)
+		}
+	}
+
+	var spec securityProfileFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("profil keamanan %s tidak valid: %v", file, err)
+	}
+	return &spec, nil
+}
+
+// apply menimpa field parent dengan field yang diisi di file profil
+func (f *securityProfileFile) apply(parent SecurityProfile, name, file string) (SecurityProfile, error) {
+	profile := parent
+	profile.Name = name
+	profile.Inherits = parent.Name
+	profile.Description = f.Description
+
+	if f.SeccompProfile != nil {
+		seccompProfile := *f.SeccompProfile
+		// Path relatif dihitung dari direktori file profil
+		if strings.Contains(seccompProfile, "/") && !filepath.IsAbs(seccompProfile) {
+			seccompProfile = filepath.Join(filepath.Dir(file), seccompProfile)
+		}
+		profile.SeccompProfile = seccompProfile
+	}
+	if f.Capabilities != nil {
+		for _, capName := range *f.Capabilities {
+			if strings.ToUpper(capName) == "ALL" {
+				continue
+			}
+			if _, err := ParseCapability(capName); err != nil {
+				return profile, err
+			}
+		}
+		profile.Capabilities = *f.Capabilities
+	}
+	// cap_add dan cap_drop di file menjadi bagian capability profil, bukan
+	// catatan --cap-add/--cap-drop
+	if err := profile.MergeCapabilities(f.CapAdd, f.CapDrop); err != nil {
+		return profile, err
+	}
+	profile.CapAdd, profile.CapDrop = nil, nil
+
+	if f.NoNewPrivs != nil {
+		profile.NoNewPrivs = *f.NoNewPrivs
+	}
+	if f.ReadOnlyRootfs != nil {
+		profile.ReadOnlyRootfs = *f.ReadOnlyRootfs
+	}
+	if f.AppArmorProfile != nil {
+		profile.AppArmorProfile = *f.AppArmorProfile
+	}
+	if f.Label != nil {
+		for _, label := range *f.Label {
+			if err := validateLabelOpt(label); err != nil {
+				return profile, err
+			}
+		}
+		profile.Label = *f.Label
+	}
+	for field, paths := range map[string]*[]string{"masked_paths": f.MaskedPaths, "readonly_paths": f.ReadonlyPaths} {
+		if paths == nil {
+			continue
+		}
+		for _, p := range *paths {
+			if !path.IsAbs(p) {
+				return profile, fmt.Errorf("%s harus berisi path absolut: %q", field, p)
+			}
+		}
+	}
+	if f.MaskedPaths != nil {
+		profile.MaskedPaths = *f.MaskedPaths
+	}
+	if f.ReadonlyPaths != nil {
+		profile.ReadonlyPaths = *f.ReadonlyPaths
+	}
+	return profile, nil
+}
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/user/minidocker/pkg/config"
 )
 
 // ImageInfo merepresentasikan informasi image di registry
@@ -20,10 +22,11 @@ type ImageInfo struct {
 	Labels    map[string]string `json:"labels"`
 }
 
-const (
-	// RegistryDir direktori untuk menyimpan data registry
-	RegistryDir = "/var/run/minidocker/registry"
-)
+// defaultRegistry adalah registry asal image jika tidak ada mirror
+const defaultRegistry = "https://example.com"
+
+// RegistryDir direktori untuk menyimpan data registry, diatur oleh Configure
+var RegistryDir = filepath.Join(config.DefaultRoot, "registry")
 
 // InitRegistryDir membuat direktori untuk menyimpan data registry
 func InitRegistryDir() error {
@@ -46,12 +49,13 @@ func PullImage(imageName string) error {
 		tag = "latest"
 	}
 
-	fmt.Printf("Mengunduh image %s:%s...\n", name, tag)
+	fmt.Fprintf(output, "Mengunduh image %s:%s...\n", name, tag)
 
-	// Simulasi penunduhan dari registry
-	// Pada implementasi nyata, ini akan mengunduh dari Docker Hub atau registry lain
-	imageURL := fmt.Sprintf("https://example.com/v2/%s/manifests/%s", name, tag)
-	fmt.Printf("Simulasi GET %s\n", imageURL)
+	// Simulasi penunduhan dari registry. Mirror dari config.json dipakai
+	// lebih dulu; pada implementasi nyata, endpoint berikutnya dicoba jika
+	// mirror gagal.
+	imageURL := fmt.Sprintf("%s/v2/%s/manifests/%s", pullEndpoints()[0], name, tag)
+	fmt.Fprintf(output, "Simulasi GET %s\n", imageURL)
 
 	// Buat direktori untuk image
 	imageDir := filepath.Join(RegistryDir, name)
@@ -70,32 +74,33 @@ func PullImage(imageName string) error {
 	}
 
 	// Simpan metadata
-	imageJSON, err := json.MarshalIndent(imageInfo, "", "  ")
-	if err != nil {
+	if err := imageInfoSchema.Write(filepath.Join(imageDir, fmt.Sprintf("%s.json", tag)), imageInfo); err != nil {
 		return fmt.Errorf("gagal menyimpan metadata image: %v", err)
 	}
 
-	if err := os.WriteFile(
-		filepath.Join(imageDir, fmt.Sprintf("%s.json", tag)),
-		imageJSON,
-		0644,
-	); err != nil {
-		return fmt.Errorf("gagal menulis metadata: %v", err)
-	}
-
 	// Simulasi unduhan layer
-	fmt.Println("Menyiapkan layer...")
+	fmt.Fprintln(output, "Menyiapkan layer...")
 	time.Sleep(1 * time.Second)
-	fmt.Println("Layer 1/3: [====================] 100%")
+	fmt.Fprintln(output, "Layer 1/3: [====================] 100%")
 	time.Sleep(500 * time.Millisecond)
-	fmt.Println("Layer 2/3: [====================] 100%")
+	fmt.Fprintln(output, "Layer 2/3: [====================] 100%")
 	time.Sleep(500 * time.Millisecond)
-	fmt.Println("Layer 3/3: [====================] 100%")
+	fmt.Fprintln(output, "Layer 3/3: [====================] 100%")
 
-	fmt.Printf("Image %s:%s berhasil diunduh\n", name, tag)
+	recordEvent(EventTypeImage, "pull", name+":"+tag, map[string]string{"name": name})
+	fmt.Fprintf(output, "Image %s:%s berhasil diunduh\n", name, tag)
 	return nil
 }
 
+// pullEndpoints mengembalikan mirror registry diikuti registry asal
+func pullEndpoints() []string {
+	endpoints := make([]string, 0, len(registryMirrors)+1)
+	for _, mirror := range registryMirrors {
+		endpoints = append(endpoints, strings.TrimSuffix(mirror, "/"))
+	}
+	return append(endpoints, defaultRegistry)
+}
+
 // PushImage mengunggah image ke registry (simulasi)
 func PushImage(imageName string) error {
 	// Parsing nama image dan tag
@@ -104,18 +109,19 @@ func PushImage(imageName string) error {
 		tag = "latest"
 	}
 
-	fmt.Printf("Mengunggah image %s:%s...\n", name, tag)
+	fmt.Fprintf(output, "Mengunggah image %s:%s...\n", name, tag)
 
 	// Simulasi pengunggahan ke registry
-	fmt.Println("Menyiapkan layer...")
+	fmt.Fprintln(output, "Menyiapkan layer...")
 	time.Sleep(1 * time.Second)
-	fmt.Println("Layer 1/3: [====================] 100%")
+	fmt.Fprintln(output, "Layer 1/3: [====================] 100%")
 	time.Sleep(500 * time.Millisecond)
-	fmt.Println("Layer 2/3: [====================] 100%")
+	fmt.Fprintln(output, "Layer 2/3: [====================] 100%")
 	time.Sleep(500 * time.Millisecond)
-	fmt.Println("Layer 3/3: [====================] 100%")
+	fmt.Fprintln(output, "Layer 3/3: [====================] 100%")
 
-	fmt.Printf("Image %s:%s berhasil diunggah\n", name, tag)
+	recordEvent(EventTypeImage, "push", name+":"+tag, map[string]string{"name": name})
+	fmt.Fprintf(output, "Image %s:%s berhasil diunggah\n", name, tag)
 	return nil
 }
 
@@ -150,13 +156,8 @@ func ListImages() ([]ImageInfo, error) {
 		for _, file := range files {
 			if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
 				// Baca file metadata
-				data, err := os.ReadFile(filepath.Join(imageDir, file.Name()))
-				if err != nil {
-					continue
-				}
-
 				var imageInfo ImageInfo
-				if err := json.Unmarshal(data, &imageInfo); err != nil {
+				if err := imageInfoSchema.Read(filepath.Join(imageDir, file.Name()), &imageInfo); err != nil {
 					continue
 				}
 
@@ -274,22 +275,17 @@ func TagImage(sourceImage, targetImage string) error {
 		targetTag = "latest"
 	}
 
-	fmt.Printf("Membuat tag %s:%s dari %s:%s\n", targetName, targetTag, sourceName, sourceTag)
+	fmt.Fprintf(output, "Membuat tag %s:%s dari %s:%s\n", targetName, targetTag, sourceName, sourceTag)
 
 	// Periksa apakah source image ada
 	sourceMetadataPath := filepath.Join(RegistryDir, sourceName, fmt.Sprintf("%s.json", sourceTag))
 	if _, err := os.Stat(sourceMetadataPath); os.IsNotExist(err) {
-		return fmt.Errorf("source image tidak ditemukan: %s:%s", sourceName, sourceTag)
+		return errorf(ErrNotFound, "source image tidak ditemukan: %s:%s", sourceName, sourceTag)
 	}
 
 	// Baca metadata source
-	data, err := os.ReadFile(sourceMetadataPath)
-	if err != nil {
-		return fmt.Errorf("gagal membaca metadata: %v", err)
-	}
-
 	var imageInfo ImageInfo
-	if err := json.Unmarshal(data, &imageInfo); err != nil {
+	if err := imageInfoSchema.Read(sourceMetadataPath, &imageInfo); err != nil {
 		return fmt.Errorf("gagal membaca metadata: %v", err)
 	}
 
@@ -304,26 +300,19 @@ func TagImage(sourceImage, targetImage string) error {
 	}
 
 	// Simpan metadata baru
-	targetMetadata, err := json.MarshalIndent(imageInfo, "", "  ")
-	if err != nil {
+	if err := imageInfoSchema.Write(filepath.Join(targetDir, fmt.Sprintf("%s.json", targetTag)), imageInfo); err != nil {
 		return fmt.Errorf("gagal menyimpan metadata: %v", err)
 	}
 
-	if err := os.WriteFile(
-		filepath.Join(targetDir, fmt.Sprintf("%s.json", targetTag)),
-		targetMetadata,
-		0644,
-	); err != nil {
-		return fmt.Errorf("gagal menulis metadata: %v", err)
-	}
-
-	fmt.Printf("Tag %s:%s berhasil dibuat\n", targetName, targetTag)
+	recordEvent(EventTypeImage, "tag", targetName+":"+targetTag,
+		map[string]string{"name": targetName, "source": sourceName + ":" + sourceTag})
+	fmt.Fprintf(output, "Tag %s:%s berhasil dibuat\n", targetName, targetTag)
 	return nil
 }
 
 // DownloadImageFromURL mengunduh image dari URL (simulasi)
 func DownloadImageFromURL(url, savePath string) error {
-	fmt.Printf("Mengunduh dari %s ke %s...\n", url, savePath)
+	fmt.Fprintf(output, "Mengunduh dari %s ke %s...\n", url, savePath)
 
 	// Simulasi unduhan
 	time.Sleep(2 * time.Second)
@@ -347,7 +336,7 @@ func DownloadImageFromURL(url, savePath string) error {
 		return fmt.Errorf("gagal menulis file: %v", err)
 	}
 
-	fmt.Println("Unduhan selesai")
+	fmt.Fprintln(output, "Unduhan selesai")
 	return nil
 }
 
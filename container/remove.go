@@ -0,0 +1,160 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// anonymousVolumeLabel menandai volume yang dibuat otomatis untuk container
+const anonymousVolumeLabel = "minidocker.anonymous"
+
+// prepareVolumes mengubah spesifikasi volume tanpa sumber (misalnya -v /data)
+// menjadi anonymous volume yang akan dihapus bersama container
+func prepareVolumes(specs []string) ([]string, []string, error) {
+	var volumes, anonymous []string
+	for _, spec := range specs {
+		if strings.Contains(spec, ":") {
+			volumes = append(volumes, spec)
+			continue
+		}
+
+		volume, err := CreateVolume(utils.GenerateID(64), map[string]string{anonymousVolumeLabel: "true"})
+		if err != nil {
+			return nil, anonymous, fmt.Errorf("gagal membuat anonymous volume untuk %s: %v", spec, err)
+		}
+		anonymous = append(anonymous, volume.Name)
+		volumes = append(volumes, volume.Name+":"+spec)
+	}
+	return volumes, anonymous, nil
+}
+
+// RemoveContainer menghapus container beserta semua resource miliknya.
+// Container yang sedang berjalan hanya bisa dihapus dengan force.
+func RemoveContainer(containerID string, force bool) error {
+	container, err := getContainer(containerID)
+	if err != nil {
+		return err
+	}
+
+	if isContainerActive(container) {
+		if !force {
+			return errorf(ErrConflict, "container %s sedang berjalan, hentikan terlebih dahulu atau gunakan --force", containerID)
+		}
+		if err := forceStopContainer(container); err != nil {
+			return err
+		}
+		// Container dengan --rm sudah dihapus oleh shim-nya sendiri
+		if _, err := os.Stat(filepath.Join(ContainerDir, container.ID)); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	return removeContainerResources(container.ID)
+}
+
+// PruneContainers menghapus semua container yang tidak sedang berjalan dan
+// cocok dengan filter label, lalu mengembalikan ID container yang dihapus
+func PruneContainers(filters Filters) ([]string, error) {
+	if err := filters.validate("label", "label!"); err != nil {
+		return nil, errorf(ErrInvalidParameter, "%v", err)
+	}
+	containers, err := getContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, c := range containers {
+		if isContainerActive(c) || !filters.matchLabels(c.Labels) {
+			continue
+		}
+		if err := removeContainerResources(c.ID); err != nil {
+			fmt.Fprintf(output, "Warning: gagal menghapus container %s: %v\n", c.ID, err)
+			continue
+		}
+		removed = append(removed, c.ID)
+	}
+	return removed, nil
+}
+
+// forceStopContainer menghentikan container dengan SIGKILL dan menunggu shim
+// mencatat bahwa container sudah berhenti
+func forceStopContainer(container Container) error {
+	// Cegah shim menjalankan restart policy
+	updateContainer(container.ID, func(c *Container) error {
+		c.ManuallyStopped = true
+		return nil
+	})
+
+	process, err := os.FindProcess(container.Pid)
+	if err != nil {
+		return fmt.Errorf("proses container tidak ditemukan: %v", err)
+	}
+	if err := process.Signal(syscall.SIGKILL); err != nil && processExists(container.Pid) {
+		return fmt.Errorf("gagal menghentikan proses: %v", err)
+	}
+	// Di cgroup v1, SIGKILL baru diproses setelah cgroup dicairkan
+	if container.Status == StatePaused {
+		thawContainer(container)
+	}
+
+	if !waitForShimExit(container, 5*time.Second) {
+		// Shim tidak ada, bersihkan resource yang biasanya dibersihkan shim
+		if len(container.Ports) > 0 {
+			cleanupPortMapping(container.Ports)
+		}
+	}
+	return nil
+}
+
+// removeContainerResources melepas mount, cgroup, anonymous volume, lalu
+// menghapus direktori container (rootfs, log, dan metadata)
+func removeContainerResources(containerID string) error {
+	container, err := getContainer(containerID)
+	if err != nil {
+		return err
+	}
+
+	containerRootDir := filepath.Join(ContainerDir, container.ID)
+	releaseName(container.Name, container.ID)
+
+	// Lepas semua mount yang masih tersisa di bawah direktori container
+	if err := unmountAll(containerRootDir); err != nil {
+		return fmt.Errorf("gagal melepas mount container: %v", err)
+	}
+
+	// Hapus cgroup milik container
+	if err := removeContainerCgroup(container); err != nil {
+		fmt.Fprintf(output, "Warning: gagal menghapus cgroup container %s: %v\n", container.ID, err)
+	}
+
+	// Hapus anonymous volume
+	for _, volumeName := range container.AnonymousVolumes {
+		if err := RemoveVolume(volumeName, true); err != nil {
+			fmt.Fprintf(output, "Warning: gagal menghapus volume %s: %v\n", volumeName, err)
+		}
+	}
+
+	if err := os.RemoveAll(containerRootDir); err != nil {
+		return fmt.Errorf("gagal menghapus direktori container: %v", err)
+	}
+	if err := os.RemoveAll(containerRuntimeDir(container.ID)); err != nil {
+		return fmt.Errorf("gagal menghapus direktori runtime container: %v", err)
+	}
+	recordContainerEvent(container, "destroy", nil)
+	return nil
+}
+
+// removeContainerCgroup menghapus direktori cgroup yang dicatat untuk container
+func removeContainerCgroup(container Container) error {
+	if container.CgroupPath == "" {
+		return nil
+	}
+	return newCgroup(container.CgroupPath).destroy()
+}
@@ -0,0 +1,292 @@
+package container
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// cpuPeriod adalah periode CFS (mikrodetik) yang dipakai untuk kuota CPU
+const cpuPeriod = 100000
+
+// maxMemNodes adalah batas jumlah node NUMA untuk --cpuset-mems, sama dengan
+// MAX_NUMNODES kernel dengan CONFIG_NODES_SHIFT maksimum
+const maxMemNodes = 1024
+
+// Resources berisi batas resource container. Memory dan CPU adalah nilai
+// asli dari flag --memory dan --cpu, sedangkan batas lainnya disimpan
+// dalam bentuk yang sudah diparse.
+type Resources struct {
+	Memory string `json:"memory"`
+	CPU    string `json:"cpu"`
+
+	// NanoCPUs adalah jumlah CPU dalam satuan 1e-9 (--cpus 1.5 = 1500000000).
+	// Jika diisi, nilai ini menggantikan CPU.
+	NanoCPUs          int64            `json:"nano_cpus,omitempty"`
+	CPUShares         uint64           `json:"cpu_shares,omitempty"`
+	CpusetCpus        string           `json:"cpuset_cpus,omitempty"`
+	CpusetMems        string           `json:"cpuset_mems,omitempty"`
+	PidsLimit         int64            `json:"pids_limit,omitempty"`
+	MemorySwap        int64            `json:"memory_swap,omitempty"`
+	MemoryReservation int64            `json:"memory_reservation,omitempty"`
+	DeviceReadBps     []ThrottleDevice `json:"device_read_bps,omitempty"`
+	DeviceWriteBps    []ThrottleDevice `json:"device_write_bps,omitempty"`
+}
+
+// ThrottleDevice adalah batas bandwidth IO untuk satu block device
+type ThrottleDevice struct {
+	Path  string `json:"path"`
+	Major uint32 `json:"major"`
+	Minor uint32 `json:"minor"`
+	Rate  uint64 `json:"rate"`
+}
+
+// ParseByteSize mengubah ukuran seperti "512k", "64m", "1gb" menjadi bytes
+func ParseByteSize(size string) (int64, error) {
+	value := strings.ToLower(strings.TrimSpace(size))
+	value = strings.TrimSuffix(value, "b")
+
+	var multiplier int64 = 1
+	if n := len(value); n > 0 {
+		switch value[n-1] {
+		case 'k':
+			multiplier = 1024
+		case 'm':
+			multiplier = 1024 * 1024
+		case 'g':
+			multiplier = 1024 * 1024 * 1024
+		case 't':
+			multiplier = 1024 * 1024 * 1024 * 1024
+		}
+		if multiplier != 1 {
+			value = value[:n-1]
+		}
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 || number > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("ukuran tidak valid: %q", size)
+	}
+	return number * multiplier, nil
+}
+
+// ParseMemorySwap mengubah nilai --memory-swap menjadi bytes. Nilai -1
+// berarti swap tidak dibatasi.
+func ParseMemorySwap(value string) (int64, error) {
+	if strings.TrimSpace(value) == "-1" {
+		return -1, nil
+	}
+	return ParseByteSize(value)
+}
+
+// ParseCPUs mengubah jumlah CPU pecahan seperti "1.5" menjadi NanoCPUs
+func ParseCPUs(value string) (int64, error) {
+	cpus, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	// Perbandingan ditulis positif agar NaN dan nilai di luar int64 ditolak
+	if err != nil || !(cpus > 0 && cpus < math.MaxInt64/1e9) {
+		return 0, fmt.Errorf("jumlah CPU tidak valid: %q", value)
+	}
+	return int64(cpus * 1e9), nil
+}
+
+// ParseThrottleDevice mengubah nilai seperti "/dev/sda:10mb" menjadi
+// ThrottleDevice. Nomor major:minor dibaca dari device di host.
+func ParseThrottleDevice(value string) (ThrottleDevice, error) {
+	idx := strings.LastIndex(value, ":")
+	if idx <= 0 {
+		return ThrottleDevice{}, fmt.Errorf("format device tidak valid: %q (gunakan PATH:RATE)", value)
+	}
+
+	rate, err := ParseByteSize(value[idx+1:])
+	if err != nil {
+		return ThrottleDevice{}, fmt.Errorf("rate device tidak valid: %q", value)
+	}
+
+	path := value[:idx]
+	major, minor, err := blockDeviceNumber(path)
+	if err != nil {
+		return ThrottleDevice{}, err
+	}
+	return ThrottleDevice{Path: path, Major: major, Minor: minor, Rate: uint64(rate)}, nil
+}
+
+// SetThrottleDevice menambahkan batas device ke daftar, atau mengganti batas
+// untuk device yang sama jika sudah ada
+func SetThrottleDevice(devices []ThrottleDevice, device ThrottleDevice) []ThrottleDevice {
+	for i, existing := range devices {
+		if existing.Major == device.Major && existing.Minor == device.Minor {
+			devices[i] = device
+			return devices
+		}
+	}
+	return append(devices, device)
+}
+
+// UpdateContainerResources mengubah resource limits container. Jika container
+// sedang berjalan, file cgroup-nya langsung ditulis ulang tanpa restart.
+// Nilai baru divalidasi dengan aturan yang sama seperti saat run.
+func UpdateContainerResources(containerID string, fn func(*Resources) error) error {
+	return updateContainer(containerID, func(c *Container) error {
+		resources := c.Resources
+		// Salin slice agar nilai lama tidak ikut berubah jika update gagal
+		resources.DeviceReadBps = append([]ThrottleDevice(nil), c.DeviceReadBps...)
+		resources.DeviceWriteBps = append([]ThrottleDevice(nil), c.DeviceWriteBps...)
+
+		if err := fn(&resources); err != nil {
+			return err
+		}
+		if err := validateResources(resources); err != nil {
+			return errorf(ErrInvalidParameter, "%v", err)
+		}
+
+		if isContainerActive(*c) && c.CgroupPath != "" {
+			if err := newCgroup(c.CgroupPath).applyResources(resources); err != nil {
+				return fmt.Errorf("gagal mengubah resource limits container %s: %v", c.ID, err)
+			}
+		}
+
+		c.Resources = resources
+		return nil
+	})
+}
+
+// parseMemoryLimit mengubah string memori seperti "64m" menjadi bytes
+func parseMemoryLimit(limit string) (uint64, error) {
+	if limit == "" {
+		return 67108864, nil // 64MB default
+	}
+
+	value, err := ParseByteSize(limit)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(value), nil
+}
+
+// parseCPULimit mengubah persentase CPU menjadi kuota CFS per cpuPeriod.
+// Persentase di atas 100 berarti lebih dari satu core (250 = 2,5 core).
+func parseCPULimit(limit string) (uint64, error) {
+	if limit == "" {
+		return 10000, nil // 10% default
+	}
+
+	// Hapus "%" jika ada
+	limit = strings.TrimSuffix(limit, "%")
+
+	value, err := strconv.ParseUint(limit, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("persentase CPU tidak valid: %q", limit)
+	}
+
+	// Konversi persentase ke jumlah dari 100000
+	// 100% = 100000
+	return (value * cpuPeriod) / 100, nil
+}
+
+// cpuQuota mengembalikan kuota CFS untuk container, dari --cpus jika ada,
+// atau dari persentase --cpu
+func (r Resources) cpuQuota() (uint64, error) {
+	if r.NanoCPUs > 0 {
+		return uint64(r.NanoCPUs) * cpuPeriod / 1e9, nil
+	}
+	return parseCPULimit(r.CPU)
+}
+
+// validateResources memeriksa semua batas resource sebelum container dibuat
+func validateResources(r Resources) error {
+	memory, err := parseMemoryLimit(r.Memory)
+	if err != nil {
+		return fmt.Errorf("--memory tidak valid: %v", err)
+	}
+	if memory < 6*1024*1024 {
+		return fmt.Errorf("--memory minimal 6m")
+	}
+
+	if r.NanoCPUs > 0 && r.CPU != "" {
+		return fmt.Errorf("--cpu dan --cpus tidak bisa dipakai bersamaan")
+	}
+	quota, err := r.cpuQuota()
+	if err != nil {
+		return fmt.Errorf("--cpu tidak valid: %v", err)
+	}
+	if quota < 1000 {
+		return fmt.Errorf("batas CPU terlalu kecil (minimal 1%% atau --cpus 0.01)")
+	}
+	if maxQuota := uint64(runtime.NumCPU()) * cpuPeriod; quota > maxQuota {
+		return fmt.Errorf("batas CPU melebihi jumlah CPU host (%d)", runtime.NumCPU())
+	}
+
+	if r.CPUShares != 0 && (r.CPUShares < 2 || r.CPUShares > 262144) {
+		return fmt.Errorf("--cpu-shares harus antara 2 dan 262144")
+	}
+
+	if r.CpusetCpus != "" {
+		last, err := parseCPUList(r.CpusetCpus)
+		if err != nil {
+			return fmt.Errorf("--cpuset-cpus tidak valid: %v", err)
+		}
+		if last >= runtime.NumCPU() {
+			return fmt.Errorf("--cpuset-cpus: CPU %d tidak tersedia (host memiliki %d CPU)", last, runtime.NumCPU())
+		}
+	}
+	if r.CpusetMems != "" {
+		last, err := parseCPUList(r.CpusetMems)
+		if err != nil {
+			return fmt.Errorf("--cpuset-mems tidak valid: %v", err)
+		}
+		if last >= maxMemNodes {
+			return fmt.Errorf("--cpuset-mems: node %d melebihi batas %d node", last, maxMemNodes)
+		}
+	}
+
+	if r.PidsLimit < -1 {
+		return fmt.Errorf("--pids-limit harus bilangan positif, atau -1 untuk tanpa batas")
+	}
+
+	if r.MemorySwap > 0 && uint64(r.MemorySwap) < memory {
+		return fmt.Errorf("--memory-swap harus lebih besar atau sama dengan --memory")
+	}
+	if r.MemoryReservation > 0 && uint64(r.MemoryReservation) > memory {
+		return fmt.Errorf("--memory-reservation harus lebih kecil atau sama dengan --memory")
+	}
+
+	for _, device := range append(append([]ThrottleDevice{}, r.DeviceReadBps...), r.DeviceWriteBps...) {
+		if device.Rate == 0 {
+			return fmt.Errorf("rate untuk device %s harus lebih dari 0", device.Path)
+		}
+	}
+	return nil
+}
+
+// parseCPUList memeriksa daftar seperti "0-3,5" dan mengembalikan nomor
+// terbesar di dalamnya. Rentang tidak dijabarkan, sehingga rentang yang
+// sangat besar tetap cepat ditolak pemanggil.
+func parseCPUList(list string) (int, error) {
+	last := 0
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil || start < 0 {
+			return 0, fmt.Errorf("format %q tidak valid", list)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil || end < start {
+				return 0, fmt.Errorf("format %q tidak valid", list)
+			}
+		}
+		if end > last {
+			last = end
+		}
+	}
+	return last, nil
+}
+
+// cpuSharesToWeight mengubah cpu.shares (cgroup v1, 2-262144) menjadi
+// cpu.weight (cgroup v2, 1-10000) dengan rumus yang sama seperti runc
+func cpuSharesToWeight(shares uint64) uint64 {
+	return 1 + ((shares-2)*9999)/262142
+}
@@ -0,0 +1,151 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nama-nama restart policy, sama seperti Docker
+const (
+	RestartNo            = "no"
+	RestartOnFailure     = "on-failure"
+	RestartAlways        = "always"
+	RestartUnlessStopped = "unless-stopped"
+)
+
+// Batas backoff antar restart. Jeda dimulai dari restartBackoffMin dan
+// berlipat dua setiap restart, lalu kembali ke awal jika container sempat
+// berjalan lebih lama dari restartResetAfter.
+const (
+	restartBackoffMin = 100 * time.Millisecond
+	restartBackoffMax = time.Minute
+	restartResetAfter = 10 * time.Second
+)
+
+// RestartPolicy menentukan kapan shim menjalankan ulang container
+type RestartPolicy struct {
+	Name string `json:"name"`
+	// MaximumRetryCount hanya berlaku untuk on-failure (0 = tanpa batas)
+	MaximumRetryCount int `json:"maximum_retry_count,omitempty"`
+}
+
+// ParseRestartPolicy mem-parse nilai --restart seperti "on-failure:3"
+func ParseRestartPolicy(value string) (RestartPolicy, error) {
+	if value == "" {
+		return RestartPolicy{Name: RestartNo}, nil
+	}
+
+	parts := strings.SplitN(value, ":", 2)
+	policy := RestartPolicy{Name: parts[0]}
+
+	switch policy.Name {
+	case RestartNo, RestartAlways, RestartUnlessStopped:
+		if len(parts) == 2 {
+			return policy, fmt.Errorf("restart policy %s tidak menerima jumlah percobaan", policy.Name)
+		}
+	case RestartOnFailure:
+		if len(parts) == 2 {
+			count, err := strconv.Atoi(parts[1])
+			if err != nil || count < 0 {
+				return policy, fmt.Errorf("jumlah percobaan restart tidak valid: %q", parts[1])
+			}
+			policy.MaximumRetryCount = count
+		}
+	default:
+		return policy, fmt.Errorf("restart policy tidak dikenal: %q (gunakan no, on-failure[:N], always, atau unless-stopped)", value)
+	}
+	return policy, nil
+}
+
+// String mengembalikan policy dalam format flag --restart
+func (p RestartPolicy) String() string {
+	if p.Name == "" {
+		return RestartNo
+	}
+	if p.Name == RestartOnFailure && p.MaximumRetryCount > 0 {
+		return fmt.Sprintf("%s:%d", p.Name, p.MaximumRetryCount)
+	}
+	return p.Name
+}
+
+// shouldRestart memutuskan apakah container yang baru berhenti perlu
+// dijalankan ulang. Container yang dihentikan manual tidak pernah di-restart.
+func shouldRestart(container Container, exitCode int) bool {
+	if container.ManuallyStopped {
+		return false
+	}
+
+	switch container.RestartPolicy.Name {
+	case RestartAlways, RestartUnlessStopped:
+		return true
+	case RestartOnFailure:
+		if exitCode == 0 {
+			return false
+		}
+		max := container.RestartPolicy.MaximumRetryCount
+		return max == 0 || container.RestartCount < max
+	}
+	return false
+}
+
+// restartBackoff menghitung jeda eksponensial antar restart
+type restartBackoff struct {
+	delay time.Duration
+}
+
+// next mengembalikan jeda sebelum restart berikutnya. uptime adalah lama
+// container berjalan sebelum berhenti.
+func (b *restartBackoff) next(uptime time.Duration) time.Duration {
+	if b.delay == 0 || uptime >= restartResetAfter {
+		b.delay = restartBackoffMin
+	} else {
+		b.delay *= 2
+		if b.delay > restartBackoffMax {
+			b.delay = restartBackoffMax
+		}
+	}
+	return b.delay
+}
+
+// ResumeContainers menjalankan kembali container dengan restart policy
+// always dan unless-stopped, misalnya setelah host reboot. Container
+// unless-stopped yang dihentikan manual tidak dijalankan. Mengembalikan ID
+// container yang berhasil dijalankan.
+func ResumeContainers() ([]string, error) {
+	containers, err := getContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	var resumed []string
+	for _, c := range containers {
+		switch c.RestartPolicy.Name {
+		case RestartAlways:
+		case RestartUnlessStopped:
+			if c.ManuallyStopped {
+				continue
+			}
+		default:
+			continue
+		}
+
+		// Lewati container yang masih diawasi shim
+		if processExists(c.ShimPid) && c.Status != StateStopped {
+			continue
+		}
+
+		// Cgroup lama bisa tertinggal jika host mati mendadak
+		if err := removeContainerCgroup(c); err != nil {
+			fmt.Fprintf(output, "Warning: gagal menghapus cgroup lama container %s: %v\n", c.ID, err)
+		}
+
+		if err := StartContainer(c.ID); err != nil {
+			fmt.Fprintf(output, "Warning: gagal menjalankan container %s: %v\n", c.ID, err)
+			continue
+		}
+		resumed = append(resumed, c.ID)
+	}
+	return resumed, nil
+}
@@ -4,92 +4,230 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/user/minidocker/pkg/seccomp"
 )
 
 // SecurityProfile mendefinisikan profil keamanan untuk container
 type SecurityProfile struct {
-	Name          string   `json:"name"`
-	SeccompProfile string  `json:"seccomp_profile"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Inherits adalah profil induk untuk profil buatan user
+	Inherits       string   `json:"inherits,omitempty"`
+	SeccompProfile string   `json:"seccomp_profile"`
 	Capabilities   []string `json:"capabilities"`
-	NoNewPrivs     bool     `json:"no_new_privs"`
-	ReadOnlyRootfs bool     `json:"read_only_rootfs"`
-	AppArmorProfile string `json:"apparmor_profile"`
+	// SeccompPath adalah file profil seccomp container yang ditentukan saat
+	// container dibuat. Shim dan helper exec memuat konfigurasi sendiri,
+	// sehingga nama profil tidak di-resolve ulang di seccomp_dir mereka.
+	SeccompPath string `json:"seccomp_path,omitempty"`
+	// CapAdd dan CapDrop mencatat --cap-add dan --cap-drop yang sudah
+	// digabung ke Capabilities oleh MergeCapabilities
+	CapAdd  []string `json:"cap_add,omitempty"`
+	CapDrop []string `json:"cap_drop,omitempty"`
+	// SecurityOpt mencatat --security-opt yang sudah diterapkan oleh
+	// ApplySecurityOpts
+	SecurityOpt     []string `json:"security_opt,omitempty"`
+	NoNewPrivs      bool     `json:"no_new_privs"`
+	ReadOnlyRootfs  bool     `json:"read_only_rootfs"`
+	AppArmorProfile string   `json:"apparmor_profile"`
+	// Label adalah opsi label SELinux (label=...), hanya dicatat
+	Label []string `json:"label,omitempty"`
+	// MaskedPaths disembunyikan dari container dan ReadonlyPaths dibuat
+	// read-only, sama seperti default OCI runtime
+	MaskedPaths   []string `json:"masked_paths,omitempty"`
+	ReadonlyPaths []string `json:"readonly_paths,omitempty"`
 }
 
+// defaultMaskedPaths dan defaultReadonlyPaths adalah path /proc dan /sys
+// yang dibatasi untuk profil non-privileged
+var (
+	defaultMaskedPaths = []string{
+		"/proc/acpi", "/proc/asound", "/proc/kcore", "/proc/keys", "/proc/latency_stats",
+		"/proc/timer_list", "/proc/timer_stats", "/proc/sched_debug", "/proc/scsi",
+		"/sys/firmware", "/sys/devices/virtual/powercap",
+	}
+	defaultReadonlyPaths = []string{
+		"/proc/bus", "/proc/fs", "/proc/irq", "/proc/sys", "/proc/sysrq-trigger",
+	}
+)
+
 // DefaultSecurityProfile memberikan profil keamanan default
 func DefaultSecurityProfile() SecurityProfile {
 	return SecurityProfile{
-		Name:          "default",
-		SeccompProfile: "default",
-		Capabilities:   []string{"CHOWN", "DAC_OVERRIDE", "FSETID", "FOWNER", "MKNOD", "NET_RAW", "SETGID", "SETUID", "SETFCAP", "SETPCAP", "NET_BIND_SERVICE", "SYS_CHROOT", "KILL", "AUDIT_WRITE"},
-		NoNewPrivs:     true,
-		ReadOnlyRootfs: false,
+		Name:            "default",
+		Description:     "Seccomp default, capability dasar Docker, dan no_new_privs",
+		SeccompProfile:  "default",
+		Capabilities:    []string{"CHOWN", "DAC_OVERRIDE", "FSETID", "FOWNER", "MKNOD", "NET_RAW", "SETGID", "SETUID", "SETFCAP", "SETPCAP", "NET_BIND_SERVICE", "SYS_CHROOT", "KILL", "AUDIT_WRITE"},
+		NoNewPrivs:      true,
+		ReadOnlyRootfs:  false,
 		AppArmorProfile: "minidocker-default",
+		MaskedPaths:     append([]string{}, defaultMaskedPaths...),
+		ReadonlyPaths:   append([]string{}, defaultReadonlyPaths...),
 	}
 }
 
 // RestrictedSecurityProfile memberikan profil keamanan yang lebih ketat
 func RestrictedSecurityProfile() SecurityProfile {
 	return SecurityProfile{
-		Name:          "restricted",
-		SeccompProfile: "restricted",
-		Capabilities:   []string{"CHOWN", "DAC_OVERRIDE", "FSETID", "FOWNER", "NET_BIND_SERVICE", "SETGID", "SETUID"},
-		NoNewPrivs:     true,
-		ReadOnlyRootfs: true,
+		Name:            "restricted",
+		Description:     "Seccomp ketat, capability minimal, dan rootfs read-only",
+		SeccompProfile:  "restricted",
+		Capabilities:    []string{"CHOWN", "DAC_OVERRIDE", "FSETID", "FOWNER", "NET_BIND_SERVICE", "SETGID", "SETUID"},
+		NoNewPrivs:      true,
+		ReadOnlyRootfs:  true,
 		AppArmorProfile: "minidocker-restricted",
+		MaskedPaths:     append([]string{}, defaultMaskedPaths...),
+		ReadonlyPaths:   append([]string{}, defaultReadonlyPaths...),
 	}
 }
 
 // PrivilegedSecurityProfile memberikan profil dengan semua capabilities
 func PrivilegedSecurityProfile() SecurityProfile {
 	return SecurityProfile{
-		Name:          "privileged",
-		SeccompProfile: "unconfined",
-		Capabilities:   []string{"ALL"},
-		NoNewPrivs:     false,
-		ReadOnlyRootfs: false,
+		Name:            "privileged",
+		Description:     "Semua capability tanpa seccomp, AppArmor, dan masked path",
+		SeccompProfile:  "unconfined",
+		Capabilities:    []string{"ALL"},
+		NoNewPrivs:      false,
+		ReadOnlyRootfs:  false,
 		AppArmorProfile: "unconfined",
 	}
 }
 
-// GetSecurityProfile mendapatkan profil berdasarkan nama
+// GetSecurityProfile mendapatkan profil berdasarkan nama: profil bawaan
+// (default, restricted, privileged) atau file NAMA.json, NAMA.yaml, atau
+// NAMA.yml di SecurityProfilesDir
 func GetSecurityProfile(name string) (SecurityProfile, error) {
-	switch strings.ToLower(name) {
-	case "default":
-		return DefaultSecurityProfile(), nil
-	case "restricted":
-		return RestrictedSecurityProfile(), nil
-	case "privileged":
-		return PrivilegedSecurityProfile(), nil
-	default:
-		return SecurityProfile{}, fmt.Errorf("profil keamanan '%s' tidak dikenal", name)
+	return loadSecurityProfile(name, nil)
+}
+
+// BuildSecurityProfile menyusun profil keamanan container dari nama profil
+// beserta --read-only, --security-opt, --cap-add, dan --cap-drop, dengan
+// urutan yang sama untuk CLI, daemon, dan SDK
+func BuildSecurityProfile(name string, readOnly bool, securityOpts, capAdd, capDrop []string) (SecurityProfile, error) {
+	profile, err := GetSecurityProfile(name)
+	if err != nil {
+		return profile, err
 	}
+	if readOnly {
+		profile.ReadOnlyRootfs = true
+	}
+	if err := profile.ApplySecurityOpts(securityOpts); err != nil {
+		return profile, err
+	}
+	if err := profile.MergeCapabilities(capAdd, capDrop); err != nil {
+		return profile, err
+	}
+	return profile, nil
 }
 
-// GetSeccompProfile mendapatkan path ke file profil seccomp
-func GetSeccompProfile(name string) (string, error) {
-	// Lokasi default untuk profil seccomp
-	profilesDir := "/etc/minidocker/seccomp"
-
-	// Cek apakah direktori ada
-	if _, err := os.Stat(profilesDir); os.IsNotExist(err) {
-		// Buat direktori jika belum ada
-		if err := os.MkdirAll(profilesDir, 0755); err != nil {
-			return "", fmt.Errorf("gagal membuat direktori profil seccomp: %v", err)
+// ApplySecurityOpts menerapkan --security-opt ke profil dengan format Docker:
+//   - seccomp=PATH atau seccomp=unconfined
+//   - apparmor=NAMA
+//   - no-new-privileges atau no-new-privileges=true|false
+//   - label=user:USER, role:ROLE, type:TYPE, level:LEVEL, atau disable
+//
+// Format lama dengan ":" (misalnya seccomp:unconfined) juga diterima. Opsi
+// yang diterapkan dicatat di SecurityOpt dengan path seccomp absolut.
+func (p *SecurityProfile) ApplySecurityOpts(opts []string) error {
+	for _, opt := range opts {
+		key, value, hasValue := strings.Cut(opt, "=")
+		if !hasValue {
+			key, value, hasValue = strings.Cut(opt, ":")
+		}
+		if hasValue && value == "" {
+			return fmt.Errorf("security option %q tidak memiliki nilai", opt)
+		}
+
+		switch key {
+		case "seccomp":
+			if !hasValue {
+				return fmt.Errorf("security option seccomp memerlukan path profil atau unconfined")
+			}
+			if value != "unconfined" {
+				abs, err := filepath.Abs(value)
+				if err != nil {
+					return fmt.Errorf("path profil seccomp tidak valid: %v", err)
+				}
+				if _, err := seccomp.LoadProfile(abs); err != nil {
+					return err
+				}
+				value = abs
+			}
+			p.SeccompProfile = value
+		case "apparmor":
+			if !hasValue {
+				return fmt.Errorf("security option apparmor memerlukan nama profil")
+			}
+			p.AppArmorProfile = value
+		case "no-new-privileges":
+			enabled := true
+			if hasValue {
+				var err error
+				if enabled, err = strconv.ParseBool(value); err != nil {
+					return fmt.Errorf("nilai no-new-privileges tidak valid: %q", value)
+				}
+			}
+			p.NoNewPrivs = enabled
+		case "label":
+			if !hasValue {
+				return fmt.Errorf("security option label memerlukan nilai")
+			}
+			if err := validateLabelOpt(value); err != nil {
+				return err
+			}
+			p.Label = append(p.Label, value)
+		default:
+			return fmt.Errorf("security option tidak didukung: %q", opt)
+		}
+
+		if hasValue {
+			p.SecurityOpt = append(p.SecurityOpt, key+"="+value)
+		} else {
+			p.SecurityOpt = append(p.SecurityOpt, key)
 		}
-		
-		// Untuk implementasi nyata, kita akan mengisi dengan profil default
-		// Ini hanya simulasi
-		createDefaultSeccompProfiles(profilesDir)
 	}
+	return nil
+}
 
+// validateLabelOpt memeriksa opsi label SELinux dengan format Docker
+func validateLabelOpt(label string) error {
+	if label == "disable" || label == "nested" {
+		return nil
+	}
+	key, value, ok := strings.Cut(label, ":")
+	switch key {
+	case "user", "role", "type", "level", "filetype":
+		if ok && value != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("label %q tidak valid (gunakan user:, role:, type:, level:, filetype:, atau disable)", label)
+}
+
+// GetSeccompProfile mendapatkan path ke file profil seccomp. name adalah
+// nama profil di SeccompProfilesDir atau path file jika berisi "/".
+func GetSeccompProfile(name string) (string, error) {
 	// Cek profil seccomp
 	if name == "unconfined" {
 		return "", nil // Tidak perlu profil untuk unconfined
 	}
 
-	profilePath := filepath.Join(profilesDir, fmt.Sprintf("%s.json", name))
+	if strings.Contains(name, "/") {
+		if _, err := os.Stat(name); err != nil {
+			return "", fmt.Errorf("profil seccomp '%s' tidak ditemukan", name)
+		}
+		return name, nil
+	}
+
+	// Profil bawaan dibuat jika belum ada
+	if err := createDefaultSeccompProfiles(SeccompProfilesDir); err != nil {
+		return "", err
+	}
+
+	profilePath := filepath.Join(SeccompProfilesDir, fmt.Sprintf("%s.json", name))
 	if _, err := os.Stat(profilePath); os.IsNotExist(err) {
 		return "", fmt.Errorf("profil seccomp '%s' tidak ditemukan", name)
 	}
@@ -97,118 +235,209 @@ func GetSeccompProfile(name string) (string, error) {
 	return profilePath, nil
 }
 
+// seccompFilter mengompilasi profil seccomp container menjadi program BPF
+// untuk arsitektur native. Profil unconfined menghasilkan nil.
+func seccompFilter(profile SecurityProfile) ([]seccomp.Instruction, error) {
+	if profile.SeccompProfile == "" {
+		return nil, nil
+	}
+	// Container lama tidak memiliki SeccompPath
+	path := profile.SeccompPath
+	if path == "" {
+		var err error
+		path, err = GetSeccompProfile(profile.SeccompProfile)
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+
+	p, err := seccomp.LoadProfile(path)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := seccomp.Compile(p, "")
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengompilasi profil seccomp '%s': %v", profile.SeccompProfile, err)
+	}
+	return prog, nil
+}
+
 // ApplySecurityProfile menerapkan profil keamanan ke container
 func ApplySecurityProfile(profile SecurityProfile, containerID string) error {
-	fmt.Printf("Menerapkan profil keamanan '%s' untuk container %s\n", profile.Name, containerID)
-	fmt.Printf("  - Seccomp: %s\n", profile.SeccompProfile)
-	fmt.Printf("  - AppArmor: %s\n", profile.AppArmorProfile)
-	fmt.Printf("  - Capabilities: %s\n", strings.Join(profile.Capabilities, ", "))
-	fmt.Printf("  - NoNewPrivs: %t\n", profile.NoNewPrivs)
-	fmt.Printf("  - ReadOnlyRootfs: %t\n", profile.ReadOnlyRootfs)
+	fmt.Fprintf(output, "Menerapkan profil keamanan '%s' untuk container %s\n", profile.Name, containerID)
+	fmt.Fprintf(output, "  - Seccomp: %s\n", profile.SeccompProfile)
+	fmt.Fprintf(output, "  - AppArmor: %s\n", profile.AppArmorProfile)
+	fmt.Fprintf(output, "  - Capabilities: %s\n", strings.Join(profile.Capabilities, ", "))
+	fmt.Fprintf(output, "  - NoNewPrivs: %t\n", profile.NoNewPrivs)
+	fmt.Fprintf(output, "  - ReadOnlyRootfs: %t\n", profile.ReadOnlyRootfs)
+	if len(profile.Label) > 0 {
+		fmt.Fprintf(output, "  - Label: %s (SELinux belum didukung, hanya dicatat)\n", strings.Join(profile.Label, ", "))
+	}
 
-	// Ini hanya simulasi, pada implementasi sebenarnya
-	// kita akan menggunakan syscall dan library seperti libseccomp, libcap, dll
+	// Seccomp, AppArmor, capabilities, no_new_privs, dan read-only rootfs
+	// diterapkan oleh shim dan child internal-start sebelum exec. Tanpa
+	// AppArmor di host, container tetap berjalan tanpa profil AppArmor.
+	if profile.AppArmorProfile != "" && profile.AppArmorProfile != "unconfined" && !AppArmorEnabled() {
+		fmt.Fprintf(output, "Peringatan: AppArmor tidak aktif di host ini, profil AppArmor '%s' tidak diterapkan\n", profile.AppArmorProfile)
+	}
 
 	return nil
 }
 
-// createDefaultSeccompProfiles membuat profil default seccomp (simulasi)
-func createDefaultSeccompProfiles(dir string) {
-	// Default profile (permisif tapi masih aman)
+// createDefaultSeccompProfiles menulis profil seccomp bawaan (format JSON
+// Docker) ke dir. Profil yang sudah ada tidak ditimpa sehingga bisa diubah.
+func createDefaultSeccompProfiles(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("gagal membuat direktori profil seccomp: %v", err)
+	}
+
+	// Default profile (permisif tapi masih aman). clone tidak boleh membuat
+	// namespace baru, dan clone3 ditolak dengan ENOSYS agar libc kembali
+	// memakai clone yang argumennya bisa diperiksa.
 	defaultProfile := `{
 	"defaultAction": "SCMP_ACT_ERRNO",
-	"architectures": ["SCMP_ARCH_X86_64", "SCMP_ARCH_X86", "SCMP_ARCH_X32"],
+	"defaultErrnoRet": 1,
+	"archMap": [
+		{"architecture": "SCMP_ARCH_X86_64", "subArchitectures": ["SCMP_ARCH_X86", "SCMP_ARCH_X32"]},
+		{"architecture": "SCMP_ARCH_AARCH64", "subArchitectures": ["SCMP_ARCH_ARM"]}
+	],
 	"syscalls": [
 		{
 			"names": [
-				"accept", "access", "arch_prctl", "bind", "brk", "capget", "capset", "chdir", "chmod",
-				"chown", "clone", "close", "connect", "dup", "dup2", "dup3", "epoll_create", "epoll_ctl",
-				"epoll_wait", "execve", "exit", "exit_group", "faccessat", "fadvise64", "fchdir", "fchmod",
-				"fcntl", "fdatasync", "flock", "fork", "fstat", "fstatfs", "fsync", "futex", "getcwd",
-				"getdents", "getdents64", "getegid", "geteuid", "getgid", "getpeername", "getpgrp", 
-				"getpid", "getppid", "getpriority", "getrandom", "getresgid", "getresuid", 
-				"getrlimit", "getrusage", "getsockname", "getsockopt", "gettid", "gettimeofday", 
-				"getuid", "listen", "lseek", "madvise", "mkdir", "mknod", "mmap", "mprotect", 
-				"munmap", "nanosleep", "open", "openat", "pipe", "pipe2", "poll", "prctl", 
-				"pread64", "prlimit64", "pwrite64", "read", "readlink", "recvfrom", "recvmsg", 
-				"rename", "rmdir", "rt_sigaction", "rt_sigprocmask", "rt_sigreturn", "rt_sigsuspend", 
-				"select", "sendfile", "sendmsg", "sendto", "set_robust_list", "set_tid_address", 
-				"setgid", "setgroups", "setitimer", "setpgid", "setrlimit", "setsid", "setsockopt", 
-				"setuid", "sigaltstack", "socket", "socketpair", "stat", "statfs", "symlink", 
-				"sysinfo", "umask", "uname", "unlink", "vfork", "wait4", "write", "writev"
+				"accept", "accept4", "access", "adjtimex", "alarm", "arch_prctl", "bind", "brk",
+				"cachestat", "capget", "capset", "chdir", "chmod", "chown", "clock_adjtime", "clock_getres",
+				"clock_gettime", "clock_nanosleep", "close", "close_range", "connect", "copy_file_range",
+				"creat", "dup", "dup2", "dup3", "epoll_create", "epoll_create1", "epoll_ctl",
+				"epoll_ctl_old", "epoll_pwait", "epoll_pwait2", "epoll_wait", "epoll_wait_old", "eventfd",
+				"eventfd2", "execve", "execveat", "exit", "exit_group", "faccessat", "faccessat2",
+				"fadvise64", "fallocate", "fanotify_mark", "fchdir", "fchmod", "fchmodat", "fchmodat2",
+				"fchown", "fchownat", "fcntl", "fdatasync", "fgetxattr", "flistxattr", "flock", "fork",
+				"fremovexattr", "fsetxattr", "fstat", "fstatfs", "fsync", "ftruncate", "futex",
+				"futex_requeue", "futex_wait", "futex_waitv", "futex_wake", "futimesat", "get_robust_list",
+				"get_thread_area", "getcpu", "getcwd", "getdents", "getdents64", "getegid", "geteuid",
+				"getgid", "getgroups", "getitimer", "getpeername", "getpgid", "getpgrp", "getpid",
+				"getppid", "getpriority", "getrandom", "getresgid", "getresuid", "getrlimit", "getrusage",
+				"getsid", "getsockname", "getsockopt", "gettid", "gettimeofday", "getuid", "getxattr",
+				"inotify_add_watch", "inotify_init", "inotify_init1", "inotify_rm_watch", "io_cancel",
+				"io_destroy", "io_getevents", "io_pgetevents", "io_setup", "io_submit", "ioctl",
+				"ioprio_get", "ioprio_set", "kill", "landlock_add_rule", "landlock_create_ruleset",
+				"landlock_restrict_self", "lchown", "lgetxattr", "link", "linkat", "listen", "listxattr",
+				"llistxattr", "lremovexattr", "lseek", "lsetxattr", "lstat", "madvise", "map_shadow_stack",
+				"membarrier", "memfd_create", "memfd_secret", "mincore", "mkdir", "mkdirat", "mknod",
+				"mknodat", "mlock", "mlock2", "mlockall", "mmap", "mprotect", "mq_getsetattr", "mq_notify",
+				"mq_open", "mq_timedreceive", "mq_timedsend", "mq_unlink", "mremap", "msgctl", "msgget",
+				"msgrcv", "msgsnd", "msync", "munlock", "munlockall", "munmap", "name_to_handle_at",
+				"nanosleep", "newfstatat", "open", "openat", "openat2", "pause", "pidfd_open",
+				"pidfd_send_signal", "pipe", "pipe2", "pkey_alloc", "pkey_free", "pkey_mprotect", "poll",
+				"ppoll", "prctl", "pread64", "preadv", "preadv2", "prlimit64", "process_mrelease",
+				"pselect6", "pwrite64", "pwritev", "pwritev2", "read", "readahead", "readlink",
+				"readlinkat", "readv", "recvfrom", "recvmmsg", "recvmsg", "remap_file_pages", "removexattr",
+				"rename", "renameat", "renameat2", "restart_syscall", "rmdir", "rseq", "rt_sigaction",
+				"rt_sigpending", "rt_sigprocmask", "rt_sigqueueinfo", "rt_sigreturn", "rt_sigsuspend",
+				"rt_sigtimedwait", "rt_tgsigqueueinfo", "sched_get_priority_max", "sched_get_priority_min",
+				"sched_getaffinity", "sched_getattr", "sched_getparam", "sched_getscheduler",
+				"sched_rr_get_interval", "sched_setaffinity", "sched_setattr", "sched_setparam",
+				"sched_setscheduler", "sched_yield", "seccomp", "select", "semctl", "semget", "semop",
+				"semtimedop", "sendfile", "sendmmsg", "sendmsg", "sendto", "set_robust_list",
+				"set_thread_area", "set_tid_address", "setfsgid", "setfsuid", "setgid", "setgroups",
+				"setitimer", "setpgid", "setpriority", "setregid", "setresgid", "setresuid", "setreuid",
+				"setrlimit", "setsid", "setsockopt", "setuid", "setxattr", "shmat", "shmctl", "shmdt",
+				"shmget", "shutdown", "sigaltstack", "signalfd", "signalfd4", "socket", "socketpair",
+				"splice", "stat", "statfs", "statx", "symlink", "symlinkat", "sync", "sync_file_range",
+				"syncfs", "sysinfo", "tee", "tgkill", "time", "timer_create", "timer_delete",
+				"timer_getoverrun", "timer_gettime", "timer_settime", "timerfd_create", "timerfd_gettime",
+				"timerfd_settime", "times", "tkill", "truncate", "umask", "uname", "unlink", "unlinkat",
+				"utime", "utimensat", "utimes", "vfork", "vmsplice", "wait4", "waitid", "write", "writev"
 			],
 			"action": "SCMP_ACT_ALLOW"
+		},
+		{
+			"names": ["personality"],
+			"action": "SCMP_ACT_ALLOW",
+			"args": [{"index": 0, "value": 0, "op": "SCMP_CMP_EQ"}]
+		},
+		{
+			"names": ["personality"],
+			"action": "SCMP_ACT_ALLOW",
+			"args": [{"index": 0, "value": 8, "op": "SCMP_CMP_EQ"}]
+		},
+		{
+			"names": ["personality"],
+			"action": "SCMP_ACT_ALLOW",
+			"args": [{"index": 0, "value": 4294967295, "op": "SCMP_CMP_EQ"}]
+		},
+		{
+			"names": ["clone"],
+			"action": "SCMP_ACT_ALLOW",
+			"args": [{"index": 0, "value": 2114060288, "valueTwo": 0, "op": "SCMP_CMP_MASKED_EQ"}],
+			"comment": "tanpa flag CLONE_NEW*"
+		},
+		{
+			"names": ["clone3"],
+			"action": "SCMP_ACT_ERRNO",
+			"errnoRet": 38
 		}
 	]
 }`
 
-	// Restricted profile (lebih ketat)
+	// Restricted profile (lebih ketat): tanpa perubahan filesystem, setuid,
+	// kill, atau server socket. clone hanya boleh membuat thread.
 	restrictedProfile := `{
 	"defaultAction": "SCMP_ACT_ERRNO",
-	"architectures": ["SCMP_ARCH_X86_64", "SCMP_ARCH_X86", "SCMP_ARCH_X32"],
+	"defaultErrnoRet": 1,
+	"archMap": [
+		{"architecture": "SCMP_ARCH_X86_64", "subArchitectures": ["SCMP_ARCH_X86", "SCMP_ARCH_X32"]},
+		{"architecture": "SCMP_ARCH_AARCH64", "subArchitectures": ["SCMP_ARCH_ARM"]}
+	],
 	"syscalls": [
 		{
 			"names": [
-				"accept", "access", "brk", "close", "dup", "dup2", "dup3", "epoll_create", "epoll_ctl",
-				"epoll_wait", "exit", "exit_group", "faccessat", "fchdir", "fcntl", "fdatasync", 
-				"flock", "fstat", "fstatfs", "fsync", "futex", "getcwd", "getdents", "getdents64", 
-				"getegid", "geteuid", "getgid", "getpeername", "getpgrp", "getpid", "getppid", 
-				"getpriority", "getrandom", "getresgid", "getresuid", "getrlimit", "getrusage", 
-				"getsockname", "getsockopt", "gettid", "gettimeofday", "getuid", "lseek", "madvise", 
-				"mmap", "mprotect", "munmap", "nanosleep", "open", "openat", "pipe", "pipe2", "poll", 
-				"prctl", "pread64", "prlimit64", "pwrite64", "read", "readlink", "recvfrom", "recvmsg", 
-				"rt_sigaction", "rt_sigprocmask", "rt_sigreturn", "rt_sigsuspend", "select", 
-				"sendfile", "sendmsg", "sendto", "set_robust_list", "set_tid_address", "setitimer", 
-				"sigaltstack", "socket", "socketpair", "stat", "statfs", "sysinfo", "umask", "uname", 
-				"wait4", "write", "writev"
+				"access", "arch_prctl", "brk", "capget", "capset", "clock_getres", "clock_gettime",
+				"clock_nanosleep", "close", "close_range", "dup", "dup2", "dup3", "epoll_create",
+				"epoll_create1", "epoll_ctl", "epoll_pwait", "epoll_pwait2", "epoll_wait", "eventfd2",
+				"execve", "exit", "exit_group", "faccessat", "faccessat2", "fadvise64", "fcntl",
+				"fdatasync", "flock", "fstat", "fstatfs", "fsync", "futex", "get_robust_list", "getcwd",
+				"getdents", "getdents64", "getegid", "geteuid", "getgid", "getgroups", "getpeername",
+				"getpgid", "getpgrp", "getpid", "getppid", "getpriority", "getrandom", "getresgid",
+				"getresuid", "getrlimit", "getrusage", "getsockname", "getsockopt", "gettid",
+				"gettimeofday", "getuid", "ioctl", "lseek", "madvise", "membarrier", "mincore", "mmap",
+				"mprotect", "mremap", "munmap", "nanosleep", "newfstatat", "open", "openat", "pipe",
+				"pipe2", "poll", "ppoll", "prctl", "pread64", "preadv", "prlimit64", "pselect6", "pwrite64",
+				"pwritev", "read", "readlink", "readlinkat", "readv", "recvfrom", "recvmsg",
+				"restart_syscall", "rseq", "rt_sigaction", "rt_sigpending", "rt_sigprocmask",
+				"rt_sigreturn", "rt_sigsuspend", "rt_sigtimedwait", "sched_getaffinity", "sched_yield",
+				"select", "sendfile", "sendmsg", "sendto", "set_robust_list", "set_tid_address",
+				"setitimer", "sigaltstack", "socket", "socketpair", "stat", "statfs", "statx", "sysinfo",
+				"tgkill", "time", "times", "tkill", "umask", "uname", "wait4", "waitid", "write", "writev"
 			],
 			"action": "SCMP_ACT_ALLOW"
+		},
+		{
+			"names": ["clone"],
+			"action": "SCMP_ACT_ALLOW",
+			"args": [{"index": 0, "value": 2114125824, "valueTwo": 65536, "op": "SCMP_CMP_MASKED_EQ"}],
+			"comment": "hanya CLONE_THREAD tanpa flag CLONE_NEW*"
+		},
+		{
+			"names": ["clone3"],
+			"action": "SCMP_ACT_ERRNO",
+			"errnoRet": 38
 		}
 	]
 }`
 
-	// Simpan profil ke file
-	os.WriteFile(filepath.Join(dir, "default.json"), []byte(defaultProfile), 0644)
-	os.WriteFile(filepath.Join(dir, "restricted.json"), []byte(restrictedProfile), 0644)
-}
-
-// GetCapabilities menerjemahkan nama capability ke nilai bitmask
-func GetCapabilities(caps []string) uint64 {
-	// Ini hanya simulasi, pada implementasi sebenarnya
-	// kita akan menggunakan library libcap
-	
-	// Beberapa capability umum dan nilai simulasi
-	capMap := map[string]uint64{
-		"CHOWN": 0x1,
-		"DAC_OVERRIDE": 0x2,
-		"DAC_READ_SEARCH": 0x4,
-		"FOWNER": 0x8,
-		"FSETID": 0x10,
-		"KILL": 0x20,
-		"SETGID": 0x40,
-		"SETUID": 0x80,
-		"SETPCAP": 0x100,
-		"NET_BIND_SERVICE": 0x200,
-		"NET_RAW": 0x400,
-		"SYS_CHROOT": 0x800,
-		"AUDIT_WRITE": 0x1000,
-		"SETFCAP": 0x2000,
-		"MAC_OVERRIDE": 0x4000,
-		"MAC_ADMIN": 0x8000,
-		"ALL": 0xFFFFFFFFFFFFFFFF,
-	}
-
-	var result uint64 = 0
-	
-	for _, cap := range caps {
-		if cap == "ALL" {
-			return 0xFFFFFFFFFFFFFFFF
+	profiles := map[string]string{
+		"default.json":    defaultProfile,
+		"restricted.json": restrictedProfile,
+	}
+	for name, content := range profiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
 		}
-		
-		if val, ok := capMap[cap]; ok {
-			result |= val
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("gagal menulis profil seccomp %s: %v", name, err)
 		}
 	}
-	
-	return result
-} 
\ No newline at end of file
+	return nil
+}
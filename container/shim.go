@@ -0,0 +1,505 @@
+package container
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// Shim adalah proses kecil per container (mirip containerd-shim) yang menjadi
+// parent dari proses container. Shim tetap berjalan setelah CLI minidocker
+// selesai, menunggu container berhenti, lalu mencatat exit code, waktu
+// selesai, dan status OOM ke config.json.
+//
+// File descriptor yang diterima shim:
+//   fd 3    : sync pipe untuk melaporkan pesan ke CLI
+//   fd 4-6  : stdin, stdout, stderr container (hanya dengan --attach)
+
+const (
+	shimSyncFd  = 3
+	shimStdioFd = 4
+
+	// startPipeFd adalah fd di child internal-start untuk melaporkan error
+	// setup ke shim. Pipe ini tertutup otomatis saat exec berhasil.
+	startPipeFd = 4
+)
+
+// Jenis pesan yang dikirim shim lewat sync pipe
+const (
+	shimMsgStarted = "started"
+	shimMsgExited  = "exited"
+	shimMsgError   = "error"
+)
+
+// shimMessage adalah pesan dari shim ke CLI
+type shimMessage struct {
+	Type     string `json:"type"`
+	Pid      int    `json:"pid,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// shimHandle adalah sisi CLI dari shim yang sedang berjalan
+type shimHandle struct {
+	pid  int
+	sync *os.File
+	dec  *json.Decoder
+}
+
+// startShim menjalankan shim untuk container. Jika cio tidak nil, stdio
+// container diteruskan ke shim untuk mode attached.
+func startShim(container *Container, cio *containerIO) (*shimHandle, error) {
+	syncR, syncW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat sync pipe: %v", err)
+	}
+
+	args := []string{"internal-shim"}
+	extraFiles := []*os.File{syncW}
+	if cio != nil {
+		args = append(args, "--attach")
+		extraFiles = append(extraFiles, cio.files()...)
+	}
+	args = append(args, container.ID)
+
+	// Output shim sendiri dipisahkan dari log container dan disimpan di
+	// exec-root karena hanya berguna selama shim berjalan
+	runtimeDir := containerRuntimeDir(container.ID)
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		syncR.Close()
+		syncW.Close()
+		return nil, fmt.Errorf("gagal membuat direktori runtime container: %v", err)
+	}
+	shimLog, err := os.OpenFile(filepath.Join(runtimeDir, "shim.log"),
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		syncR.Close()
+		syncW.Close()
+		return nil, fmt.Errorf("gagal membuka log shim: %v", err)
+	}
+	defer shimLog.Close()
+
+	cmd := exec.Command(ShimBinary, args...)
+	cmd.Env = append(os.Environ(), shimEnv...)
+	cmd.ExtraFiles = extraFiles
+	cmd.Stdout = shimLog
+	cmd.Stderr = shimLog
+	cmd.SysProcAttr = createShimSysProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		syncR.Close()
+		syncW.Close()
+		return nil, fmt.Errorf("gagal menjalankan shim: %v", err)
+	}
+	syncW.Close()
+	if cio != nil {
+		cio.closeContainerSide()
+	}
+
+	// Shim tetap berjalan di background, cukup pastikan tidak menjadi zombie
+	go cmd.Wait()
+
+	shim := &shimHandle{sync: syncR, dec: json.NewDecoder(syncR)}
+
+	msg, err := shim.next()
+	if err != nil {
+		shim.Close()
+		return nil, fmt.Errorf("shim berhenti sebelum container berjalan, lihat %s", shimLog.Name())
+	}
+	if msg.Type == shimMsgError {
+		shim.Close()
+		return nil, fmt.Errorf("gagal menjalankan container: %s", msg.Error)
+	}
+
+	shim.pid = msg.Pid
+	return shim, nil
+}
+
+// next membaca pesan berikutnya dari shim
+func (s *shimHandle) next() (shimMessage, error) {
+	var msg shimMessage
+	err := s.dec.Decode(&msg)
+	return msg, err
+}
+
+// wait menunggu shim melaporkan bahwa container sudah berhenti
+func (s *shimHandle) wait() (int, error) {
+	for {
+		msg, err := s.next()
+		if err != nil {
+			return -1, fmt.Errorf("shim berhenti tanpa melaporkan exit code container")
+		}
+		switch msg.Type {
+		case shimMsgExited:
+			return msg.ExitCode, nil
+		case shimMsgError:
+			return -1, fmt.Errorf("%s", msg.Error)
+		}
+	}
+}
+
+// Close menutup sync pipe. Shim tetap berjalan.
+func (s *shimHandle) Close() {
+	if s.sync != nil {
+		s.sync.Close()
+		s.sync = nil
+	}
+}
+
+// RunShim adalah titik masuk proses shim (perintah internal-shim). Shim
+// juga menjalankan ulang container sesuai restart policy.
+func RunShim(containerID string, attach bool) error {
+	syncPipe := os.NewFile(uintptr(shimSyncFd), "shim-sync")
+	report := func(msg shimMessage) {
+		// Error diabaikan: CLI mode detached sudah menutup sync pipe
+		json.NewEncoder(syncPipe).Encode(msg)
+	}
+
+	var stdio []*os.File
+	if attach {
+		stdio = []*os.File{
+			os.NewFile(uintptr(shimStdioFd), "stdin"),
+			os.NewFile(uintptr(shimStdioFd+1), "stdout"),
+			os.NewFile(uintptr(shimStdioFd+2), "stderr"),
+		}
+	}
+
+	container, err := getContainer(containerID)
+	if err != nil {
+		report(shimMessage{Type: shimMsgError, Error: err.Error()})
+		return err
+	}
+
+	var backoff restartBackoff
+	for {
+		startedAt := time.Now()
+		exitCode, err := runContainerOnce(&container, stdio, report)
+		// Stdio attached hanya dipakai proses pertama, proses hasil restart
+		// menulis output ke file log
+		for _, f := range stdio {
+			f.Close()
+		}
+		stdio = nil
+
+		// Baca ulang state untuk melihat stop manual atau perubahan policy
+		container, err = getContainer(containerID)
+		if err != nil || !shouldRestart(container, exitCode) {
+			break
+		}
+
+		delay := backoff.next(time.Since(startedAt))
+		updateContainer(containerID, func(c *Container) error {
+			c.Status = StateRestarting
+			return nil
+		})
+		fmt.Printf("[%s] Container %s akan di-restart dalam %v (policy %s, restart ke-%d)\n",
+			time.Now().Format(time.RFC3339), containerID, delay, container.RestartPolicy, container.RestartCount+1)
+		time.Sleep(delay)
+
+		// Container bisa dihentikan atau dihapus selama menunggu
+		if err := updateContainer(containerID, func(c *Container) error {
+			if c.ManuallyStopped {
+				return errManuallyStopped
+			}
+			c.RestartCount++
+			return nil
+		}); err != nil {
+			break
+		}
+		if container, err = getContainer(containerID); err != nil {
+			break
+		}
+	}
+
+	// --rm: hapus container beserta semua resource miliknya
+	if container.AutoRemove {
+		if err := removeContainerResources(containerID); err != nil {
+			fmt.Printf("Warning: gagal menghapus container: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// errManuallyStopped menandakan container dihentikan manual saat menunggu restart
+var errManuallyStopped = errors.New("container dihentikan manual")
+
+// runContainerOnce menjalankan proses container sampai berhenti, mencatat
+// state-nya ke config.json, dan mengembalikan exit code
+func runContainerOnce(container *Container, stdio []*os.File, report func(shimMessage)) (int, error) {
+	containerID := container.ID
+
+	proc, err := startContainerProcess(container, stdio)
+	if err != nil {
+		updateContainer(containerID, func(c *Container) error {
+			c.Status = StateStopped
+			c.Pid = 0
+			c.ExitCode = 127
+			c.FinishedAt = time.Now()
+			return nil
+		})
+		report(shimMessage{Type: shimMsgError, Error: err.Error()})
+		recordContainerEvent(*container, "die", map[string]string{"exitCode": "127"})
+		fmt.Printf("[%s] Container %s gagal dijalankan: %v\n", time.Now().Format(time.RFC3339), containerID, err)
+		return 127, err
+	}
+
+	if err := updateContainer(containerID, func(c *Container) error {
+		c.Status = StateRunning
+		c.Pid = proc.cmd.Process.Pid
+		c.ShimPid = os.Getpid()
+		c.StartedAt = time.Now()
+		c.FinishedAt = time.Time{}
+		c.ExitCode = 0
+		c.OOMKilled = false
+		if proc.cgroup != nil {
+			c.CgroupPath = proc.cgroup.path
+		}
+		return nil
+	}); err != nil {
+		fmt.Printf("Warning: gagal mencatat state container: %v\n", err)
+	}
+	report(shimMessage{Type: shimMsgStarted, Pid: proc.cmd.Process.Pid})
+	recordContainerEvent(*container, "start", nil)
+
+	exitCode, oomKilled := proc.wait()
+
+	if proc.portsMapped {
+		cleanupPortMapping(container.Ports)
+		recordNetworkEvent(*container, "disconnect")
+	}
+
+	if err := updateContainer(containerID, func(c *Container) error {
+		c.Status = StateStopped
+		c.Pid = 0
+		c.ExitCode = exitCode
+		c.OOMKilled = oomKilled
+		c.FinishedAt = time.Now()
+		return nil
+	}); err != nil {
+		fmt.Printf("Warning: gagal mencatat exit code container: %v\n", err)
+	}
+	report(shimMessage{Type: shimMsgExited, ExitCode: exitCode})
+	if oomKilled {
+		recordContainerEvent(*container, "oom", nil)
+	}
+	recordContainerEvent(*container, "die", map[string]string{"exitCode": strconv.Itoa(exitCode)})
+
+	fmt.Printf("[%s] Container %s berhenti dengan exit code %d (oom_killed=%t)\n",
+		time.Now().Format(time.RFC3339), containerID, exitCode, oomKilled)
+	return exitCode, nil
+}
+
+// containerProcess adalah proses container yang dijalankan oleh shim
+type containerProcess struct {
+	cmd       *exec.Cmd
+	cgroup    *cgroup
+	oomBefore int
+	// portsMapped menandai port mapping yang harus dibersihkan
+	portsMapped bool
+}
+
+// startContainerProcess menjalankan child internal-start untuk container.
+// Fungsi ini kembali setelah perintah user berhasil di-exec di dalam
+// container, atau dengan error jika setup container gagal.
+func startContainerProcess(container *Container, stdio []*os.File) (*containerProcess, error) {
+	// Profil seccomp dikompilasi di shim agar profil yang salah
+	// menggagalkan start sebelum child dibuat
+	filter, err := seccompFilter(container.Security)
+	if err != nil {
+		return nil, err
+	}
+
+	// Profil AppArmor dimuat di shim karena child sudah berada di mount
+	// namespace container ketika profil dipakai
+	appArmorProfile, err := ensureAppArmorProfile(container.Security.AppArmorProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fork child process dengan namespace baru
+	cmd := exec.Command("/proc/self/exe", "internal-start")
+
+	// Setup namespaces
+	// Catatan: Ini hanya bekerja di Linux
+	// Di Windows dan OS lain, kode ini diabaikan
+	if utils.IsLinux() {
+		cmd.SysProcAttr = createLinuxSysProcAttr()
+	}
+
+	if stdio != nil {
+		cmd.Stdin = stdio[0]
+		cmd.Stdout = stdio[1]
+		cmd.Stderr = stdio[2]
+		if container.TTY {
+			setControllingTTY(cmd.SysProcAttr)
+		}
+	} else {
+		// Tanpa attach, output container ditulis ke file log
+		logOutput, err := openContainerLog(container)
+		if err != nil {
+			return nil, err
+		}
+		defer logOutput.Close()
+		cmd.Stdout = logOutput
+		cmd.Stderr = logOutput
+	}
+
+	// Cgroup dibuat oleh shim sebelum child berjalan
+	cg, err := setupContainerCgroup(container)
+	if err != nil {
+		return nil, err
+	}
+
+	// Child tidak mewarisi environment host, semua konfigurasi
+	// dikirim lewat init pipe
+	cmd.Env = []string{}
+
+	initPipe, err := newInitPipe(cmd)
+	if err != nil {
+		destroyCgroup(cg)
+		return nil, err
+	}
+
+	startR, startW, err := os.Pipe()
+	if err != nil {
+		initPipe.Close()
+		destroyCgroup(cg)
+		return nil, fmt.Errorf("gagal membuat start pipe: %v", err)
+	}
+	defer startR.Close()
+	cmd.ExtraFiles = append(cmd.ExtraFiles, startW)
+
+	err = cmd.Start()
+	// Salinan ujung pipe milik child tidak diperlukan lagi di shim
+	for _, f := range cmd.ExtraFiles {
+		f.Close()
+	}
+	if err != nil {
+		initPipe.Close()
+		destroyCgroup(cg)
+		return nil, fmt.Errorf("gagal menjalankan container: %v", err)
+	}
+
+	// Child masih menunggu init config, sehingga perintah user selalu
+	// berjalan di dalam cgroup container
+	if cg != nil {
+		if err := cg.addProcess(cmd.Process.Pid); err != nil {
+			initPipe.Close()
+			cmd.Process.Kill()
+			cmd.Wait()
+			destroyCgroup(cg)
+			return nil, err
+		}
+	}
+
+	// Kirim spesifikasi proses ke child
+	if err := sendInitConfig(initPipe, initConfig{
+		Rootfs:   filepath.Join(ContainerDir, container.ID, "rootfs"),
+		Args:     container.Command,
+		Env:      container.Env,
+		Security: container.Security,
+		Seccomp:  filter,
+		Tmpfs:    container.Tmpfs,
+
+		AppArmorProfile: appArmorProfile,
+	}); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		destroyCgroup(cg)
+		return nil, err
+	}
+
+	// Start pipe mencapai EOF ketika child berhasil exec. Jika ada isi,
+	// itu adalah pesan error dari child.
+	startErr, _ := ioutil.ReadAll(startR)
+	if len(startErr) > 0 {
+		cmd.Wait()
+		destroyCgroup(cg)
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(startErr)))
+	}
+
+	proc := &containerProcess{cmd: cmd, cgroup: cg}
+	if cg != nil {
+		proc.oomBefore = cg.oomKillCount()
+	}
+
+	// Port mapping dipasang setelah child berhasil dijalankan sehingga setup
+	// yang gagal tidak meninggalkan mapping tanpa proses
+	if len(container.Ports) > 0 {
+		if err := setupPortMapping(container.Ports); err != nil {
+			fmt.Printf("Warning: gagal setup port mapping: %v\n", err)
+		} else {
+			proc.portsMapped = true
+			recordNetworkEvent(*container, "connect")
+		}
+	}
+	return proc, nil
+}
+
+// setupContainerCgroup membuat cgroup minidocker/<id> dan menerapkan
+// resource limits container. Host tanpa cgroup hanya menghasilkan warning,
+// tetapi batas yang gagal diterapkan menggagalkan container.
+func setupContainerCgroup(container *Container) (*cgroup, error) {
+	cg := newCgroup(containerCgroupPath(container.ID))
+	if err := cg.create(); err != nil {
+		fmt.Printf("Warning: gagal setup cgroups, container berjalan tanpa resource limits: %v\n", err)
+		cg.destroy()
+		return nil, nil
+	}
+	if err := cg.applyResources(container.Resources); err != nil {
+		cg.destroy()
+		return nil, fmt.Errorf("gagal menerapkan resource limits: %v", err)
+	}
+	return cg, nil
+}
+
+// destroyCgroup menghapus cgroup container jika ada
+func destroyCgroup(cg *cgroup) {
+	if cg == nil {
+		return
+	}
+	if err := cg.destroy(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// wait menunggu proses container berhenti dan mengembalikan exit code serta
+// apakah proses dihentikan oleh OOM killer
+func (p *containerProcess) wait() (int, bool) {
+	exitCode, err := exitCodeFromError(p.cmd.Wait())
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	// Cgroup dihapus setelah status OOM terbaca
+	oomKilled := false
+	if p.cgroup != nil {
+		oomKilled = p.cgroup.oomKillCount() > p.oomBefore
+		destroyCgroup(p.cgroup)
+	}
+	return exitCode, oomKilled
+}
+
+// parseKeyedCount membaca nilai dari file cgroup berformat "key value"
+func parseKeyedCount(r io.Reader, key string) int {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			value, _ := strconv.Atoi(fields[1])
+			return value
+		}
+	}
+	return 0
+}
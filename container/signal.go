@@ -0,0 +1,109 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultStopSignal dipakai jika image tidak menentukan StopSignal
+const defaultStopSignal = syscall.SIGTERM
+
+// DefaultStopTimeout adalah waktu tunggu stop sebelum SIGKILL
+const DefaultStopTimeout = 10 * time.Second
+
+// ParseSignal mengubah nama sinyal ("SIGTERM", "TERM", "term") atau nomor
+// sinyal ("15") menjadi syscall.Signal
+func ParseSignal(value string) (syscall.Signal, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		if n <= 0 || n > maxSignal {
+			return 0, fmt.Errorf("nomor sinyal tidak valid: %d", n)
+		}
+		return syscall.Signal(n), nil
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(value), "SIG")
+	if sig, ok := signalNames[name]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("sinyal tidak dikenal: %q", value)
+}
+
+// stopSignal mengembalikan sinyal stop container
+func stopSignal(container Container) syscall.Signal {
+	if container.StopSignal == "" {
+		return defaultStopSignal
+	}
+	sig, err := ParseSignal(container.StopSignal)
+	if err != nil {
+		return defaultStopSignal
+	}
+	return sig
+}
+
+// KillContainer mengirim sinyal ke proses utama container
+func KillContainer(containerID, signal string) error {
+	sig, err := ParseSignal(signal)
+	if err != nil {
+		return errorf(ErrInvalidParameter, "%v", err)
+	}
+
+	container, err := getContainer(containerID)
+	if err != nil {
+		return err
+	}
+	if !isContainerActive(container) {
+		return errorf(ErrConflict, "container %s tidak berjalan", containerID)
+	}
+
+	if err := signalProcess(container.Pid, sig); err != nil {
+		return fmt.Errorf("gagal mengirim sinyal %v ke container %s: %v", sig, containerID, err)
+	}
+	// Di cgroup v1, SIGKILL baru diproses setelah cgroup dicairkan
+	if sig == syscall.SIGKILL && container.Status == StatePaused {
+		thawContainer(container)
+	}
+	recordContainerEvent(container, "kill", map[string]string{"signal": strconv.Itoa(int(sig))})
+	return nil
+}
+
+// signalProcess mengirim sinyal ke proses. Proses yang sudah berhenti
+// tidak dianggap error.
+func signalProcess(pid int, sig syscall.Signal) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("proses container tidak ditemukan: %v", err)
+	}
+	if err := process.Signal(sig); err != nil {
+		if !processExists(pid) {
+			return nil
+		}
+		// Platform tanpa sinyal (Windows) hanya mendukung Kill
+		if sig == syscall.SIGKILL || sig == syscall.SIGTERM {
+			return process.Kill()
+		}
+		return err
+	}
+	return nil
+}
+
+// waitForContainerExit menunggu proses container berhenti. Jika shim
+// berjalan, tunggu sampai shim mencatat exit code container.
+func waitForContainerExit(container Container, timeout time.Duration) bool {
+	if processExists(container.ShimPid) {
+		return waitForShimExit(container, timeout)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for processExists(container.Pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+// +build !linux
+
+package container
+
+import "syscall"
+
+// signalNames adalah daftar sinyal yang tersedia di semua platform
+var signalNames = map[string]syscall.Signal{
+	"ABRT": syscall.SIGABRT,
+	"ALRM": syscall.SIGALRM,
+	"BUS":  syscall.SIGBUS,
+	"FPE":  syscall.SIGFPE,
+	"HUP":  syscall.SIGHUP,
+	"ILL":  syscall.SIGILL,
+	"INT":  syscall.SIGINT,
+	"KILL": syscall.SIGKILL,
+	"PIPE": syscall.SIGPIPE,
+	"QUIT": syscall.SIGQUIT,
+	"SEGV": syscall.SIGSEGV,
+	"TERM": syscall.SIGTERM,
+	"TRAP": syscall.SIGTRAP,
+}
+
+// maxSignal adalah nomor sinyal tertinggi yang diterima
+const maxSignal = 31
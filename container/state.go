@@ -0,0 +1,63 @@
+package container
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/user/minidocker/pkg/store"
+)
+
+// Skema metadata yang disimpan di disk. Naikkan Version dan tambahkan
+// migrasi setiap kali format JSON berubah secara tidak kompatibel.
+var (
+	containerSchema = store.Schema{
+		Name:    "container",
+		Version: 1,
+		Migrations: map[int]store.Migration{
+			0: migrateContainerV0,
+		},
+	}
+
+	volumeSchema = store.Schema{
+		Name:    "volume",
+		Version: 1,
+		Migrations: map[int]store.Migration{
+			0: stampVersion,
+		},
+	}
+
+	imageInfoSchema = store.Schema{
+		Name:    "image",
+		Version: 1,
+		Migrations: map[int]store.Migration{
+			0: stampVersion,
+		},
+	}
+)
+
+// containerConfigPath mengembalikan lokasi metadata container
+func containerConfigPath(id string) string {
+	return filepath.Join(ContainerDir, id, "config.json")
+}
+
+// migrateContainerV0 memigrasi metadata yang ditulis sebelum ada
+// schema_version. Versi lama menulis konfigurasi image ke config.json yang
+// sama, sehingga file tanpa id bukan metadata container.
+func migrateContainerV0(doc map[string]interface{}) error {
+	if id, _ := doc["id"].(string); id == "" {
+		return fmt.Errorf("file bukan metadata container (tidak ada id)")
+	}
+
+	// Container dari sebelum ada restart policy berperilaku seperti "no"
+	policy, _ := doc["restart_policy"].(map[string]interface{})
+	if name, _ := policy["name"].(string); name == "" {
+		doc["restart_policy"] = map[string]interface{}{"name": RestartNo}
+	}
+	return nil
+}
+
+// stampVersion adalah migrasi untuk skema yang formatnya tidak berubah;
+// dokumen hanya mendapat schema_version saat ditulis berikutnya
+func stampVersion(doc map[string]interface{}) error {
+	return nil
+}
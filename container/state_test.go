@@ -0,0 +1,62 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateContainerV0(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		policy  RestartPolicy
+		wantErr bool
+	}{
+		{
+			name:   "tanpa restart policy",
+			doc:    `{"id": "abc", "status": "stopped"}`,
+			policy: RestartPolicy{Name: RestartNo},
+		},
+		{
+			name:   "restart policy kosong",
+			doc:    `{"id": "abc", "restart_policy": {"name": ""}}`,
+			policy: RestartPolicy{Name: RestartNo},
+		},
+		{
+			name:   "restart policy dipertahankan",
+			doc:    `{"id": "abc", "restart_policy": {"name": "on-failure", "maximum_retry_count": 3}}`,
+			policy: RestartPolicy{Name: RestartOnFailure, MaximumRetryCount: 3},
+		},
+		{
+			// Versi lama menulis konfigurasi image ke config.json yang sama
+			name:    "konfigurasi image",
+			doc:     `{"name": "alpine", "cmd": ["/bin/sh"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.doc), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var c Container
+			err := containerSchema.Read(path, &c)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Read berhasil, ingin error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if c.ID != "abc" || c.RestartPolicy != tt.policy {
+				t.Errorf("hasil = id %q, restart policy %+v, ingin id abc, %+v", c.ID, c.RestartPolicy, tt.policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContainerStats adalah penggunaan resource container pada satu waktu,
+// dibaca dari file accounting cgroup container
+type ContainerStats struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name"`
+	Read time.Time `json:"read"`
+
+	// CPUUsage adalah total waktu CPU dalam nanodetik sejak container berjalan.
+	// CPUPercent dihitung dari selisih dua sampel (100% = satu core penuh).
+	CPUUsage   uint64  `json:"cpu_usage_ns"`
+	CPUPercent float64 `json:"cpu_percent"`
+
+	// MemoryUsage tidak termasuk page cache yang tidak aktif, sama seperti docker stats
+	MemoryUsage   uint64  `json:"memory_usage"`
+	MemoryLimit   uint64  `json:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent"`
+
+	BlockRead  uint64 `json:"block_read"`
+	BlockWrite uint64 `json:"block_write"`
+
+	// PidsLimit bernilai 0 jika jumlah proses tidak dibatasi
+	PidsCurrent uint64 `json:"pids_current"`
+	PidsLimit   uint64 `json:"pids_limit"`
+}
+
+// GetContainerStats mengambil statistik satu container. Dua sampel diambil
+// dengan jeda interval untuk menghitung persentase CPU.
+func GetContainerStats(containerID string, interval time.Duration) (ContainerStats, error) {
+	c, err := getContainer(containerID)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+
+	first, err := readContainerStats(c)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+	time.Sleep(interval)
+	second, err := readContainerStats(c)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+
+	second.CPUPercent = cpuPercent(first, second)
+	return second, nil
+}
+
+// CollectContainerStats mengambil statistik beberapa container sekaligus
+// (semua container yang berjalan jika containerIDs kosong) dengan dua
+// sampel berjeda interval
+func CollectContainerStats(containerIDs []string, interval time.Duration) ([]ContainerStats, error) {
+	containers, err := statsTargets(containerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	first := make(map[string]ContainerStats)
+	for _, c := range containers {
+		if sample, err := readContainerStats(c); err == nil {
+			first[c.ID] = sample
+		}
+	}
+	time.Sleep(interval)
+
+	var stats []ContainerStats
+	for _, c := range containers {
+		prev, ok := first[c.ID]
+		if !ok {
+			continue
+		}
+		current, err := readContainerStats(c)
+		if err != nil {
+			continue
+		}
+		current.CPUPercent = cpuPercent(prev, current)
+		stats = append(stats, current)
+	}
+	return stats, nil
+}
+
+// StreamContainerStats memanggil fn dengan statistik semua container setiap
+// interval sampai ctx dibatalkan atau fn mengembalikan error. Jika
+// containerIDs kosong, semua container yang sedang berjalan dipantau.
+// Container yang berhenti di tengah jalan tidak lagi dilaporkan.
+func StreamContainerStats(ctx context.Context, containerIDs []string, interval time.Duration, fn func([]ContainerStats) error) error {
+	containers, err := statsTargets(containerIDs)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]ContainerStats)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var stats []ContainerStats
+		for _, c := range containers {
+			current, err := readContainerStats(c)
+			if err != nil {
+				// Container sudah berhenti
+				continue
+			}
+			if prev, ok := previous[c.ID]; ok {
+				current.CPUPercent = cpuPercent(prev, current)
+			}
+			previous[c.ID] = current
+			stats = append(stats, current)
+		}
+
+		if err := fn(stats); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// statsTargets mencari container yang akan dipantau
+func statsTargets(containerIDs []string) ([]Container, error) {
+	if len(containerIDs) == 0 {
+		all, err := getContainers()
+		if err != nil {
+			return nil, err
+		}
+		var running []Container
+		for _, c := range all {
+			if isContainerActive(c) {
+				running = append(running, c)
+			}
+		}
+		return running, nil
+	}
+
+	var containers []Container
+	for _, id := range containerIDs {
+		c, err := getContainer(id)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// readContainerStats membaca satu sampel statistik dari cgroup container
+func readContainerStats(c Container) (ContainerStats, error) {
+	if current, err := getContainer(c.ID); err != nil || !isContainerActive(current) {
+		return ContainerStats{}, fmt.Errorf("container %s tidak sedang berjalan", c.ID)
+	}
+	if c.CgroupPath == "" {
+		return ContainerStats{}, fmt.Errorf("container %s tidak memiliki cgroup", c.ID)
+	}
+
+	cg := newCgroup(c.CgroupPath)
+	stats := ContainerStats{ID: c.ID, Name: c.Name, Read: time.Now()}
+
+	if cg.v2 {
+		cpuStat, _ := readKeyedFile(cg.dir("cpu"), "cpu.stat")
+		stats.CPUUsage = cpuStat["usage_usec"] * 1000
+
+		memStat, _ := readKeyedFile(cg.dir("memory"), "memory.stat")
+		stats.MemoryUsage = subtractFloor(readUint(cg, "memory", "memory.current"), memStat["inactive_file"])
+		stats.MemoryLimit = readUint(cg, "memory", "memory.max")
+
+		stats.BlockRead, stats.BlockWrite = readIOStatV2(cg.dir("io"))
+	} else {
+		stats.CPUUsage = readUint(cg, "cpuacct", "cpuacct.usage")
+
+		memStat, _ := readKeyedFile(cg.dir("memory"), "memory.stat")
+		stats.MemoryUsage = subtractFloor(readUint(cg, "memory", "memory.usage_in_bytes"), memStat["total_inactive_file"])
+		stats.MemoryLimit = readUint(cg, "memory", "memory.limit_in_bytes")
+
+		stats.BlockRead, stats.BlockWrite = readBlkioStatV1(cg.dir("blkio"))
+	}
+
+	// Batas memory tak terhingga ditampilkan sebagai total memory host
+	if hostMemory := hostMemoryTotal(); hostMemory > 0 && (stats.MemoryLimit == 0 || stats.MemoryLimit > hostMemory) {
+		stats.MemoryLimit = hostMemory
+	}
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+
+	stats.PidsCurrent = readUint(cg, "pids", "pids.current")
+	stats.PidsLimit = readUint(cg, "pids", "pids.max")
+	return stats, nil
+}
+
+// cpuPercent menghitung penggunaan CPU di antara dua sampel
+func cpuPercent(prev, current ContainerStats) float64 {
+	elapsed := current.Read.Sub(prev.Read)
+	if elapsed <= 0 || current.CPUUsage < prev.CPUUsage {
+		return 0
+	}
+	return float64(current.CPUUsage-prev.CPUUsage) / float64(elapsed.Nanoseconds()) * 100
+}
+
+// readUint membaca file cgroup berisi satu angka. Nilai "max" dan file
+// yang tidak ada dianggap 0.
+func readUint(cg *cgroup, controller, file string) uint64 {
+	value, err := cg.read(controller, file)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseUint(value, 10, 64)
+	return n
+}
+
+// readKeyedFile membaca file cgroup berformat "key value" per baris
+func readKeyedFile(dir, name string) (map[string]uint64, error) {
+	file, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values, scanner.Err()
+}
+
+// readIOStatV2 menjumlahkan rbytes dan wbytes dari io.stat (cgroup v2).
+// Format: "8:0 rbytes=1459200 wbytes=314773504 rios=192 wios=353 ..."
+func readIOStatV2(dir string) (uint64, uint64) {
+	file, err := os.Open(filepath.Join(dir, "io.stat"))
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	var read, write uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		for _, field := range strings.Fields(scanner.Text()) {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			n, _ := strconv.ParseUint(parts[1], 10, 64)
+			switch parts[0] {
+			case "rbytes":
+				read += n
+			case "wbytes":
+				write += n
+			}
+		}
+	}
+	return read, write
+}
+
+// readBlkioStatV1 menjumlahkan byte baca dan tulis dari
+// blkio.throttle.io_service_bytes (cgroup v1).
+// Format: "8:0 Read 1459200" dan "8:0 Write 314773504"
+func readBlkioStatV1(dir string) (uint64, uint64) {
+	file, err := os.Open(filepath.Join(dir, "blkio.throttle.io_service_bytes"))
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	var read, write uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		n, _ := strconv.ParseUint(fields[2], 10, 64)
+		switch fields[1] {
+		case "Read":
+			read += n
+		case "Write":
+			write += n
+		}
+	}
+	return read, write
+}
+
+// hostMemoryTotal membaca total memory host dari /proc/meminfo
+func hostMemoryTotal() uint64 {
+	// Baris meminfo berformat "MemTotal:  16318480 kB"
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, _ := strconv.ParseUint(fields[1], 10, 64)
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+// subtractFloor mengurangi b dari a tanpa underflow
+func subtractFloor(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
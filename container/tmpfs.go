@@ -0,0 +1,59 @@
+package container
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// tmpfsFlagOptions adalah opsi --tmpfs yang menjadi flag mount, sisanya
+// (size, mode, uid, gid, nr_inodes, nr_blocks) diteruskan ke tmpfs
+var tmpfsFlagOptions = map[string]bool{
+	"ro": true, "rw": true,
+	"exec": true, "noexec": true,
+	"suid": true, "nosuid": true,
+	"dev": true, "nodev": true,
+}
+
+var tmpfsDataOptions = map[string]bool{
+	"size": true, "mode": true, "uid": true, "gid": true, "nr_inodes": true, "nr_blocks": true,
+}
+
+// ParseTmpfs mem-parse nilai --tmpfs berformat PATH[:OPTIONS], misalnya
+// /run:size=64m,mode=1777, menjadi map path ke opsi
+func ParseTmpfs(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	tmpfs := map[string]string{}
+	for _, spec := range specs {
+		target, options, _ := strings.Cut(spec, ":")
+		tmpfs[target] = options
+	}
+	if err := ValidateTmpfs(tmpfs); err != nil {
+		return nil, err
+	}
+	return tmpfs, nil
+}
+
+// ValidateTmpfs memeriksa path dan opsi mount tmpfs
+func ValidateTmpfs(tmpfs map[string]string) error {
+	for target, options := range tmpfs {
+		if !path.IsAbs(target) || path.Clean(target) != target {
+			return fmt.Errorf("path tmpfs harus absolut: %q", target)
+		}
+		if target == "/" {
+			return fmt.Errorf("tmpfs tidak bisa dipasang di /")
+		}
+		if options == "" {
+			continue
+		}
+		for _, opt := range strings.Split(options, ",") {
+			key, _, hasValue := strings.Cut(opt, "=")
+			if (hasValue && !tmpfsDataOptions[key]) || (!hasValue && !tmpfsFlagOptions[key]) {
+				return fmt.Errorf("opsi tmpfs %q untuk %s tidak didukung", opt, target)
+			}
+		}
+	}
+	return nil
+}
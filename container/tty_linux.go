@@ -0,0 +1,136 @@
+//go:build linux
+// +build linux
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// winsize adalah representasi struct winsize milik kernel
+type winsize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+// ioctl memanggil syscall ioctl dengan argumen pointer
+func ioctl(fd uintptr, request uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// openPTY mengalokasikan pasangan pseudo-terminal (master dan slave)
+func openPTY() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membuka /dev/ptmx: %v", err)
+	}
+
+	// Buka kunci slave agar bisa dibuka
+	var unlock int32
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("gagal membuka kunci pty: %v", err)
+	}
+
+	// Dapatkan nomor slave
+	var ptyNumber uint32
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, unsafe.Pointer(&ptyNumber)); err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("gagal mendapatkan nomor pty: %v", err)
+	}
+
+	slavePath := fmt.Sprintf("/dev/pts/%d", ptyNumber)
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("gagal membuka %s: %v", slavePath, err)
+	}
+
+	return master, slave, nil
+}
+
+// isTerminal memeriksa apakah file merupakan terminal
+func isTerminal(f *os.File) bool {
+	var termios syscall.Termios
+	return ioctl(f.Fd(), syscall.TCGETS, unsafe.Pointer(&termios)) == nil
+}
+
+// makeRawTerminal mengubah terminal ke mode raw dan mengembalikan fungsi
+// untuk memulihkan mode semula
+func makeRawTerminal(f *os.File) (func(), error) {
+	var original syscall.Termios
+	if err := ioctl(f.Fd(), syscall.TCGETS, unsafe.Pointer(&original)); err != nil {
+		return nil, fmt.Errorf("gagal membaca mode terminal: %v", err)
+	}
+
+	// Sama seperti cfmakeraw(3)
+	raw := original
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := ioctl(f.Fd(), syscall.TCSETS, unsafe.Pointer(&raw)); err != nil {
+		return nil, fmt.Errorf("gagal mengubah terminal ke mode raw: %v", err)
+	}
+
+	return func() {
+		ioctl(f.Fd(), syscall.TCSETS, unsafe.Pointer(&original))
+	}, nil
+}
+
+// copyWindowSize menyalin ukuran jendela terminal dari src ke pty dst
+func copyWindowSize(src, dst *os.File) error {
+	var ws winsize
+	if err := ioctl(src.Fd(), syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return err
+	}
+	return ioctl(dst.Fd(), syscall.TIOCSWINSZ, unsafe.Pointer(&ws))
+}
+
+// watchWindowSize menyelaraskan ukuran pty dengan terminal host setiap kali
+// SIGWINCH diterima. Fungsi yang dikembalikan menghentikan pemantauan.
+func watchWindowSize(src, dst *os.File) func() {
+	copyWindowSize(src, dst)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				copyWindowSize(src, dst)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
+
+// setControllingTTY mengatur child agar menjadikan stdin (pty slave) sebagai
+// controlling terminal di session baru
+func setControllingTTY(attr *syscall.SysProcAttr) {
+	attr.Setsid = true
+	attr.Setctty = true
+	attr.Ctty = 0
+}
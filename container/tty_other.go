@@ -0,0 +1,33 @@
+//go:build !linux
+// +build !linux
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// openPTY tidak didukung di platform non-Linux
+func openPTY() (*os.File, *os.File, error) {
+	return nil, nil, fmt.Errorf("TTY hanya didukung di Linux")
+}
+
+// isTerminal selalu false di platform non-Linux
+func isTerminal(f *os.File) bool {
+	return false
+}
+
+// makeRawTerminal tidak didukung di platform non-Linux
+func makeRawTerminal(f *os.File) (func(), error) {
+	return nil, fmt.Errorf("mode raw terminal hanya didukung di Linux")
+}
+
+// watchWindowSize tidak melakukan apa-apa di platform non-Linux
+func watchWindowSize(src, dst *os.File) func() {
+	return func() {}
+}
+
+// setControllingTTY tidak melakukan apa-apa di platform non-Linux
+func setControllingTTY(attr *syscall.SysProcAttr) {}
@@ -1,11 +1,12 @@
 package container
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/user/minidocker/pkg/config"
 )
 
 // Volume merepresentasikan informasi volume
@@ -18,9 +19,8 @@ type Volume struct {
 	Labels    map[string]string `json:"labels"`
 }
 
-const (
-	VolumeDir = "/var/run/minidocker/volumes"
-)
+// VolumeDir menyimpan data volume, diatur oleh Configure
+var VolumeDir = filepath.Join(config.DefaultRoot, "volumes")
 
 // InitVolumeDir membuat direktori untuk menyimpan data volume
 func InitVolumeDir() error {
@@ -44,7 +44,7 @@ func CreateVolume(name string, labels map[string]string) (*Volume, error) {
 
 	for _, v := range volumes {
 		if v.Name == name {
-			return &v, fmt.Errorf("volume dengan nama '%s' sudah ada", name)
+			return &v, errorf(ErrConflict, "volume dengan nama '%s' sudah ada", name)
 		}
 	}
 
@@ -78,20 +78,12 @@ func CreateVolume(name string, labels map[string]string) (*Volume, error) {
 	}
 
 	// Simpan metadata volume
-	volumeJSON, err := json.Marshal(volume)
-	if err != nil {
+	if err := volumeSchema.Write(filepath.Join(volumePath, "config.json"), volume); err != nil {
 		return nil, fmt.Errorf("gagal menyimpan metadata volume: %v", err)
 	}
 
-	if err := os.WriteFile(
-		filepath.Join(volumePath, "config.json"),
-		volumeJSON,
-		0644,
-	); err != nil {
-		return nil, fmt.Errorf("gagal menulis config.json: %v", err)
-	}
-
-	fmt.Printf("Volume %s berhasil dibuat di %s\n", volume.Name, volume.Mountpoint)
+	recordEvent(EventTypeVolume, "create", volume.Name, map[string]string{"driver": volume.Driver})
+	fmt.Fprintf(output, "Volume %s berhasil dibuat di %s\n", volume.Name, volume.Mountpoint)
 	return &volume, nil
 }
 
@@ -115,14 +107,8 @@ func ListVolumes() ([]Volume, error) {
 			continue
 		}
 
-		configPath := filepath.Join(VolumeDir, file.Name(), "config.json")
-		data, err := os.ReadFile(configPath)
-		if err != nil {
-			continue
-		}
-
 		var volume Volume
-		if err := json.Unmarshal(data, &volume); err != nil {
+		if err := volumeSchema.Read(filepath.Join(VolumeDir, file.Name(), "config.json"), &volume); err != nil {
 			continue
 		}
 
@@ -145,7 +131,7 @@ func GetVolume(nameOrID string) (*Volume, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("volume '%s' tidak ditemukan", nameOrID)
+	return nil, errorf(ErrNotFound, "volume '%s' tidak ditemukan", nameOrID)
 }
 
 // RemoveVolume menghapus volume
@@ -167,7 +153,7 @@ func RemoveVolume(nameOrID string, force bool) error {
 			if c.Status == StateRunning {
 				for _, v := range c.Volumes {
 					if volumeInUse(v, volume.Name) || volumeInUse(v, volume.ID) {
-						return fmt.Errorf("volume '%s' masih digunakan oleh container '%s'", volume.Name, c.ID)
+						return errorf(ErrConflict, "volume '%s' masih digunakan oleh container '%s'", volume.Name, c.ID)
 					}
 				}
 			}
@@ -180,7 +166,8 @@ func RemoveVolume(nameOrID string, force bool) error {
 		return fmt.Errorf("gagal menghapus volume: %v", err)
 	}
 
-	fmt.Printf("Volume %s berhasil dihapus\n", volume.Name)
+	recordEvent(EventTypeVolume, "destroy", volume.Name, map[string]string{"driver": volume.Driver})
+	fmt.Fprintf(output, "Volume %s berhasil dihapus\n", volume.Name)
 	return nil
 }
 
@@ -217,7 +204,7 @@ func MountVolume(volumeSpec string, containerRootfs string) error {
 
 	// Buat link ke volume
 	// Pada sistem nyata, ini akan menggunakan mount point
-	fmt.Printf("Memasang volume %s ke %s\n", volume.Name, targetPath)
+	fmt.Fprintf(output, "Memasang volume %s ke %s\n", volume.Name, targetPath)
 
 	return nil
 }
@@ -236,7 +223,7 @@ func BackupVolumeData(volumeName, backupPath string) error {
 	}
 
 	// Simulasi proses backup
-	fmt.Printf("Membuat backup volume %s ke %s\n", volume.Name, backupPath)
+	fmt.Fprintf(output, "Membuat backup volume %s ke %s\n", volume.Name, backupPath)
 	
 	// Di implementasi nyata, ini akan menggunakan tar untuk mengompresi data
 	// cmd := exec.Command("tar", "-czf", backupPath, "-C", volume.Mountpoint, ".")
@@ -263,11 +250,11 @@ func RestoreVolumeData(volumeName, backupPath string) error {
 
 	// Periksa apakah file backup ada
 	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
-		return fmt.Errorf("file backup tidak ditemukan: %s", backupPath)
+		return errorf(ErrNotFound, "file backup tidak ditemukan: %s", backupPath)
 	}
 
 	// Simulasi proses restore
-	fmt.Printf("Memulihkan backup ke volume %s dari %s\n", volume.Name, backupPath)
+	fmt.Fprintf(output, "Memulihkan backup ke volume %s dari %s\n", volume.Name, backupPath)
 	
 	// Di implementasi nyata, ini akan menggunakan tar untuk mengekstrak data
 	// cmd := exec.Command("tar", "-xzf", backupPath, "-C", volume.Mountpoint)
@@ -0,0 +1,321 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlToJSON mengubah dokumen YAML sederhana menjadi JSON sehingga profil
+// YAML bisa di-decode dengan encoding/json seperti profil JSON. Yang didukung
+// hanya subset YAML yang dibutuhkan file konfigurasi: mapping dan list
+// bertingkat dengan indentasi spasi, list flow [a, b], string ber-quote,
+// boolean, null, angka, dan komentar #. Anchor, block scalar (| dan >),
+// mapping flow, dan beberapa dokumen dalam satu file tidak didukung.
+func yamlToJSON(data []byte) ([]byte, error) {
+	lines, err := yamlLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return []byte("{}"), nil
+	}
+
+	p := &yamlParser{lines: lines}
+	value, err := p.parseNode(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("indentasi tidak valid")
+	}
+	return json.Marshal(value)
+}
+
+// yamlLine adalah satu baris YAML tanpa indentasi dan komentar
+type yamlLine struct {
+	indent int
+	text   string
+	num    int
+}
+
+// yamlLines memecah dokumen menjadi baris, membuang baris kosong, komentar,
+// dan penanda awal dokumen "---"
+func yamlLines(doc string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		content := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("baris %d: indentasi YAML tidak boleh memakai tab", i+1)
+		}
+		content = strings.TrimSpace(stripYAMLComment(content))
+		if content == "" || (content == "---" && len(lines) == 0) {
+			continue
+		}
+		if content == "---" || content == "..." {
+			return nil, fmt.Errorf("baris %d: file hanya boleh berisi satu dokumen YAML", i+1)
+		}
+		lines = append(lines, yamlLine{indent: len(raw) - len(strings.TrimLeft(raw, " ")), text: content, num: i + 1})
+	}
+	return lines, nil
+}
+
+// stripYAMLComment membuang komentar "#" yang berada di luar string ber-quote
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return fmt.Errorf("baris %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+// parseNode mem-parse mapping atau list yang dimulai di baris saat ini
+func (p *yamlParser) parseNode(indent int) (interface{}, error) {
+	if isYAMLListItem(p.lines[p.pos].text) {
+		return p.parseList(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]interface{}, error) {
+	mapping := map[string]interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && isYAMLListItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("indentasi tidak valid")
+		}
+
+		key, value, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, p.errorf("diharapkan 'key: value', bukan %q", line.text)
+		}
+		if _, dup := mapping[key]; dup {
+			return nil, p.errorf("key %q ditulis dua kali", key)
+		}
+		if value != "" {
+			parsed, err := parseYAMLValue(value)
+			if err != nil {
+				return nil, p.errorf("%s: %v", key, err)
+			}
+			mapping[key] = parsed
+			p.pos++
+			continue
+		}
+		p.pos++
+
+		// Nilai blok ada di baris berikutnya. List boleh sejajar dengan key.
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isYAMLListItem(next.text)) {
+				child, err := p.parseNode(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				mapping[key] = child
+				continue
+			}
+		}
+		mapping[key] = nil
+	}
+	return mapping, nil
+}
+
+func (p *yamlParser) parseList(indent int) ([]interface{}, error) {
+	list := []interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isYAMLListItem(line.text) {
+			if line.indent > indent {
+				return nil, p.errorf("indentasi tidak valid")
+			}
+			break
+		}
+
+		item := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if item == "" {
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				child, err := p.parseNode(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, child)
+			} else {
+				list = append(list, nil)
+			}
+			continue
+		}
+
+		// "- key: value" dan "- - item" memulai mapping atau list yang
+		// kolomnya sejajar dengan item tersebut
+		if _, _, ok := splitYAMLKey(item); isYAMLListItem(item) || (ok && !strings.HasPrefix(item, "[")) {
+			column := line.indent + len(line.text) - len(item)
+			p.lines[p.pos] = yamlLine{indent: column, text: item, num: line.num}
+			child, err := p.parseNode(column)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, child)
+			continue
+		}
+
+		parsed, err := parseYAMLValue(item)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		list = append(list, parsed)
+		p.pos++
+	}
+	return list, nil
+}
+
+func isYAMLListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey memisahkan "key: value" di titik dua pertama di luar quote
+func splitYAMLKey(text string) (string, string, bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			key, err := parseYAMLScalar(strings.TrimSpace(text[:i]))
+			if err != nil {
+				return "", "", false
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				keyString = strings.TrimSpace(text[:i])
+			}
+			return keyString, strings.TrimSpace(text[i+1:]), keyString != ""
+		}
+	}
+	return "", "", false
+}
+
+// parseYAMLValue mem-parse nilai dalam satu baris: scalar atau list flow
+func parseYAMLValue(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("list %q tidak ditutup", value)
+		}
+		list := []interface{}{}
+		for _, item := range splitYAMLFlow(value[1 : len(value)-1]) {
+			parsed, err := parseYAMLScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, parsed)
+		}
+		return list, nil
+	case strings.HasPrefix(value, "{"):
+		return nil, fmt.Errorf("mapping flow {...} tidak didukung")
+	case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+		return nil, fmt.Errorf("block scalar tidak didukung")
+	case strings.HasPrefix(value, "&") || strings.HasPrefix(value, "*"):
+		return nil, fmt.Errorf("anchor dan alias tidak didukung")
+	}
+	return parseYAMLScalar(value)
+}
+
+// splitYAMLFlow memecah isi list flow di koma yang berada di luar quote
+func splitYAMLFlow(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	return items
+}
+
+// parseYAMLScalar mengubah scalar menjadi string, bool, angka, atau nil
+func parseYAMLScalar(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("string %s tidak valid", s)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, fmt.Errorf("string %s tidak ditutup", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	// Hanya angka desimal biasa, sehingga kata seperti "inf" tetap string
+	if strings.Trim(s, "0123456789.-+eE") == "" {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, nil
+		}
+	}
+	return s, nil
+}
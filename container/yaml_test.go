@@ -0,0 +1,124 @@
+package container
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "dokumen kosong",
+			yaml: "# hanya komentar\n\n",
+			want: `{}`,
+		},
+		{
+			name: "scalar",
+			yaml: "s: teks biasa\nn: 42\nf: 1.5\nneg: -3\nt: true\nF: False\nz: null\ntilde: ~\nkosong:\nword: inf\nver: 1.2.3",
+			want: `{"F":false,"f":1.5,"kosong":null,"n":42,"neg":-3,"s":"teks biasa","t":true,"tilde":null,"ver":"1.2.3","word":"inf","z":null}`,
+		},
+		{
+			name: "mapping bertingkat",
+			yaml: "a:\n  b:\n    c: 1\n  d: 2\ne: 3",
+			want: `{"a":{"b":{"c":1},"d":2},"e":3}`,
+		},
+		{
+			name: "list berindentasi dan sejajar key",
+			yaml: "caps:\n  - CHOWN\n  - KILL\ndrop:\n- ALL\n",
+			want: `{"caps":["CHOWN","KILL"],"drop":["ALL"]}`,
+		},
+		{
+			name: "list berisi mapping",
+			yaml: "items:\n  - name: a\n    value: 1\n  - name: b\n",
+			want: `{"items":[{"name":"a","value":1},{"name":"b"}]}`,
+		},
+		{
+			name: "list bertingkat",
+			yaml: "- - a\n  - b\n- -\n    - c\n",
+			want: `[["a","b"],[["c"]]]`,
+		},
+		{
+			name: "item list kosong",
+			yaml: "l:\n  -\n  - x",
+			want: `{"l":[null,"x"]}`,
+		},
+		{
+			name: "list flow",
+			yaml: `l: [a, "b, c", 'd', 1, true]` + "\nkosong: []",
+			want: `{"kosong":[],"l":["a","b, c","d",1,true]}`,
+		},
+		{
+			name: "quote dan escape",
+			yaml: `d: "baris\nbaru \"kutip\" \u00e9"` + "\n" + `s: 'it''s \n'` + "\n" + `angka: "42"` + "\n" + `b: 'true'`,
+			want: `{"angka":"42","b":"true","d":"baris\nbaru \"kutip\" é","s":"it's \\n"}`,
+		},
+		{
+			name: "komentar",
+			yaml: "# komentar\na: 1 # komentar\nb: \"x # bukan komentar\"\nc: 'y # juga bukan'\nd: z#bukan\ne: \"\\\"# tetap string\"",
+			want: `{"a":1,"b":"x # bukan komentar","c":"y # juga bukan","d":"z#bukan","e":"\"# tetap string"}`,
+		},
+		{
+			name: "key ber-quote dan titik dua di nilai",
+			yaml: `"a: b": 1` + "\nurl: http://host:8080/x\n'k': v",
+			want: `{"a: b":1,"k":"v","url":"http://host:8080/x"}`,
+		},
+		{
+			name: "penanda dokumen dan CRLF",
+			yaml: "---\r\na: 1\r\nb:\r\n  - x\r\n",
+			want: `{"a":1,"b":["x"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("yamlToJSON: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("yamlToJSON =\n%s\ningin\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		// want adalah potongan pesan error, termasuk nomor baris
+		want string
+	}{
+		{"indentasi tab", "a:\n\tb: 1", "baris 2: indentasi YAML tidak boleh memakai tab"},
+		{"tab setelah spasi", "a:\n  \tb: 1", "baris 2: indentasi YAML tidak boleh memakai tab"},
+		{"mapping terlalu menjorok", "a: 1\n  b: 2", "baris 2: indentasi tidak valid"},
+		{"list terlalu menjorok", "l:\n  - a\n    - b", "baris 3: indentasi tidak valid"},
+		{"kembali ke indentasi tanpa induk", "a:\n    b: 1\n  c: 2", "baris 3: indentasi tidak valid"},
+		{"bukan key value", "a: 1\njust text", "baris 2: diharapkan 'key: value'"},
+		{"key ganda", "a: 1\na: 2", `baris 2: key "a" ditulis dua kali`},
+		{"list flow tidak ditutup", "a: 1\nl: [a, b", "baris 2: l: list"},
+		{"string tidak valid", `a: "tidak ditutup`, "baris 1: a: string"},
+		{"quote tunggal tidak ditutup", "a: 'x", "baris 1: a: string 'x tidak ditutup"},
+		{"mapping flow", "a: {b: 1}", "baris 1: a: mapping flow"},
+		{"block scalar", "a: |\n  teks", "baris 1: a: block scalar tidak didukung"},
+		{"anchor", "a: &x 1", "baris 1: a: anchor dan alias tidak didukung"},
+		{"beberapa dokumen", "a: 1\n---\nb: 2", "baris 2: file hanya boleh berisi satu dokumen YAML"},
+		{"item list tidak valid", "l:\n  - \"x", "baris 2: string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(tt.yaml))
+			if err == nil {
+				t.Fatalf("yamlToJSON berhasil (%s), ingin error %q", got, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, ingin mengandung %q", err, tt.want)
+			}
+		})
+	}
+}
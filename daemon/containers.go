@@ -0,0 +1,667 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/config"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// containerCreate menangani POST /containers/create
+func (d *Daemon) containerCreate(w http.ResponseWriter, r *http.Request, _ []string) error {
+	var req api.ContainerCreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Image == "" {
+		return httpError(http.StatusBadRequest, "image wajib diisi")
+	}
+
+	// Docker SDK mengirim nama dengan atau tanpa "/" di depan
+	name := strings.TrimPrefix(r.URL.Query().Get("name"), "/")
+	opts, secProfile, err := d.runOptions(name, req)
+	if err != nil {
+		return httpError(http.StatusBadRequest, "%v", err)
+	}
+
+	c, err := container.CreateContainer(opts, secProfile)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusCreated, api.ContainerCreateResponse{ID: c.ID, Warnings: []string{}})
+}
+
+// runOptions mengubah request create Docker menjadi RunOptions. Nilai yang
+// tidak diisi request memakai default dari config.json, sama seperti CLI.
+func (d *Daemon) runOptions(name string, req api.ContainerCreateRequest) (container.RunOptions, container.SecurityProfile, error) {
+	cfg := d.opts.Config
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	host := req.HostConfig
+	if host == nil {
+		host = &api.HostConfig{}
+	}
+	// Tanpa endpoint attach, container Tty akan berjalan tanpa pty dan
+	// stdin-nya tidak pernah tersambung
+	if req.Tty || req.OpenStdin {
+		return container.RunOptions{}, container.SecurityProfile{}, fmt.Errorf("Tty dan OpenStdin tidak didukung karena daemon tidak memiliki endpoint attach, gunakan minidocker run -it tanpa daemon")
+	}
+
+	opts := container.RunOptions{
+		Image:       req.Image,
+		Name:        name,
+		Command:     append(append([]string{}, req.Entrypoint...), req.Cmd...),
+		Env:         req.Env,
+		Volumes:     append([]string{}, host.Binds...),
+		Tmpfs:       host.Tmpfs,
+		Labels:      req.Labels,
+		Annotations: host.Annotations,
+		Detach:      true,
+		AutoRemove:  host.AutoRemove,
+		LogDriver:   cfg.LogDriver,
+		Resources: container.Resources{
+			Memory: cfg.DefaultMemory,
+			CPU:    cfg.DefaultCPU,
+		},
+	}
+
+	// Volume anonim dari Config.Volumes, diurutkan agar hasilnya stabil
+	var anonymous []string
+	for path := range req.Volumes {
+		anonymous = append(anonymous, path)
+	}
+	sort.Strings(anonymous)
+	opts.Volumes = append(opts.Volumes, anonymous...)
+
+	ports, err := portSpecs(host.PortBindings)
+	if err != nil {
+		return opts, container.SecurityProfile{}, err
+	}
+	opts.Ports = ports
+
+	if err := applyResources(host.Resources, &opts.Resources); err != nil {
+		return opts, container.SecurityProfile{}, err
+	}
+
+	policy := host.RestartPolicy.Name
+	if policy == container.RestartOnFailure && host.RestartPolicy.MaximumRetryCount > 0 {
+		policy += ":" + strconv.Itoa(host.RestartPolicy.MaximumRetryCount)
+	}
+	opts.RestartPolicy, err = container.ParseRestartPolicy(policy)
+	if err != nil {
+		return opts, container.SecurityProfile{}, err
+	}
+
+	switch host.LogConfig.Type {
+	case "":
+	case "json-file", "local":
+		opts.LogDriver = config.LogDriverFile
+	default:
+		opts.LogDriver = host.LogConfig.Type
+	}
+
+	// "profile=NAMA" memilih profil, opsi lain menimpa field profil
+	profileName := cfg.SecurityProfile
+	var securityOpts []string
+	for _, opt := range host.SecurityOpt {
+		if key, value, _ := strings.Cut(opt, "="); key == "profile" {
+			profileName = value
+			continue
+		}
+		securityOpts = append(securityOpts, opt)
+	}
+	if host.Privileged {
+		profileName = "privileged"
+	}
+	secProfile, err := container.BuildSecurityProfile(profileName, host.ReadonlyRootfs, securityOpts, host.CapAdd, host.CapDrop)
+	return opts, secProfile, err
+}
+
+// portSpecs mengubah PortBindings Docker menjadi spesifikasi HOST:CONTAINER[/PROTO]
+func portSpecs(bindings map[string][]api.PortBinding) ([]string, error) {
+	var keys []string
+	for key := range bindings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var ports []string
+	for _, key := range keys {
+		containerPort := strings.TrimSuffix(key, "/tcp")
+		for _, binding := range bindings[key] {
+			if binding.HostPort == "" {
+				return nil, fmt.Errorf("port host untuk %s wajib diisi", key)
+			}
+			ports = append(ports, binding.HostPort+":"+containerPort)
+		}
+	}
+	return ports, nil
+}
+
+// applyResources menimpa resources dengan batas yang diisi di host. Dipakai
+// untuk HostConfig saat create dan body request update.
+func applyResources(host api.Resources, resources *container.Resources) error {
+	if host.Memory > 0 {
+		resources.Memory = strconv.FormatInt(host.Memory, 10)
+	}
+	switch {
+	case host.NanoCPUs > 0:
+		resources.NanoCPUs = host.NanoCPUs
+		resources.CPU = ""
+	case host.CPUQuota > 0:
+		period := host.CPUPeriod
+		if period <= 0 {
+			period = 100000
+		}
+		resources.CPU = strconv.FormatInt(host.CPUQuota*100/period, 10)
+		resources.NanoCPUs = 0
+	}
+	if host.CPUShares > 0 {
+		resources.CPUShares = uint64(host.CPUShares)
+	}
+	if host.CpusetCpus != "" {
+		resources.CpusetCpus = host.CpusetCpus
+	}
+	if host.CpusetMems != "" {
+		resources.CpusetMems = host.CpusetMems
+	}
+	if host.PidsLimit != nil {
+		resources.PidsLimit = *host.PidsLimit
+	}
+	if host.MemorySwap != 0 {
+		resources.MemorySwap = host.MemorySwap
+	}
+	if host.MemoryReservation > 0 {
+		resources.MemoryReservation = host.MemoryReservation
+	}
+
+	for _, d := range host.BlkioDeviceReadBps {
+		device, err := container.ParseThrottleDevice(fmt.Sprintf("%s:%d", d.Path, d.Rate))
+		if err != nil {
+			return err
+		}
+		resources.DeviceReadBps = container.SetThrottleDevice(resources.DeviceReadBps, device)
+	}
+	for _, d := range host.BlkioDeviceWriteBps {
+		device, err := container.ParseThrottleDevice(fmt.Sprintf("%s:%d", d.Path, d.Rate))
+		if err != nil {
+			return err
+		}
+		resources.DeviceWriteBps = container.SetThrottleDevice(resources.DeviceWriteBps, device)
+	}
+	return nil
+}
+
+// containerList menangani GET /containers/json
+func (d *Daemon) containerList(w http.ResponseWriter, r *http.Request, _ []string) error {
+	filters, err := filtersValue(r)
+	if err != nil {
+		return err
+	}
+	// Docker menyebut container yang berhenti "exited"
+	for i, status := range filters["status"] {
+		if status == "exited" {
+			filters["status"][i] = container.StateStopped
+		}
+	}
+
+	summaries, err := container.ListContainers(boolValue(r, "all"), filters)
+	if err != nil {
+		return httpError(http.StatusBadRequest, "%v", err)
+	}
+
+	list := []api.Container{}
+	for _, s := range summaries {
+		info, err := container.InspectContainer(s.ID)
+		if err != nil {
+			// Container dihapus di antara list dan inspect
+			continue
+		}
+		item := api.Container{
+			ID:      s.ID,
+			Names:   dockerNames(s.Name),
+			Image:   s.Image,
+			ImageID: s.Image,
+			Command: strings.Join(s.Command, " "),
+			Created: s.CreatedAt.Unix(),
+			Ports:   apiPorts(info.NetworkSettings.Ports),
+			Labels:  s.Labels,
+			State:   dockerState(s.State),
+			Status:  dockerStatus(info),
+		}
+		if item.Labels == nil {
+			item.Labels = map[string]string{}
+		}
+		list = append(list, item)
+	}
+	return writeJSON(w, http.StatusOK, list)
+}
+
+// dockerState mengubah status minidocker menjadi nama state Docker
+func dockerState(status string) string {
+	if status == container.StateStopped {
+		return "exited"
+	}
+	return status
+}
+
+// dockerStatus membuat teks Status seperti yang ditampilkan docker ps
+func dockerStatus(info *container.ContainerInspect) string {
+	state := info.State
+	switch {
+	case state.Paused:
+		return fmt.Sprintf("Up %s (Paused)", utils.HumanDuration(time.Since(state.StartedAt)))
+	case state.Running:
+		return "Up " + utils.HumanDuration(time.Since(state.StartedAt))
+	case state.Restarting:
+		return fmt.Sprintf("Restarting (%d) %s ago", state.ExitCode, utils.HumanDuration(time.Since(state.FinishedAt)))
+	case state.Status == container.StateStopped && !state.FinishedAt.IsZero():
+		return fmt.Sprintf("Exited (%d) %s ago", state.ExitCode, utils.HumanDuration(time.Since(state.FinishedAt)))
+	}
+	return "Created"
+}
+
+// apiPorts mengubah port binding minidocker menjadi daftar port Docker
+func apiPorts(bindings []container.PortBinding) []api.Port {
+	ports := []api.Port{}
+	for _, b := range bindings {
+		private, _ := strconv.ParseUint(b.ContainerPort, 10, 16)
+		public, _ := strconv.ParseUint(b.HostPort, 10, 16)
+		ports = append(ports, api.Port{
+			IP:          "0.0.0.0",
+			PrivatePort: uint16(private),
+			PublicPort:  uint16(public),
+			Type:        b.Protocol,
+		})
+	}
+	return ports
+}
+
+// containerInspect menangani GET /containers/{id}/json
+func (d *Daemon) containerInspect(w http.ResponseWriter, r *http.Request, params []string) error {
+	info, err := container.InspectContainer(params[0])
+	if err != nil {
+		return err
+	}
+
+	state := &api.ContainerState{
+		Status:     dockerState(info.State.Status),
+		Running:    info.State.Running,
+		Paused:     info.State.Paused,
+		Restarting: info.State.Restarting,
+		OOMKilled:  info.State.OOMKilled,
+		Pid:        info.State.Pid,
+		ExitCode:   info.State.ExitCode,
+		StartedAt:  formatTime(info.State.StartedAt),
+		FinishedAt: formatTime(info.State.FinishedAt),
+	}
+
+	res := info.HostConfig.Resources
+	host := &api.HostConfig{
+		Binds: info.HostConfig.Binds,
+		RestartPolicy: api.RestartPolicy{
+			Name:              info.HostConfig.RestartPolicy.Name,
+			MaximumRetryCount: info.HostConfig.RestartPolicy.MaximumRetryCount,
+		},
+		AutoRemove:     info.HostConfig.AutoRemove,
+		Privileged:     info.HostConfig.SecurityProfile.Name == "privileged",
+		ReadonlyRootfs: info.HostConfig.SecurityProfile.ReadOnlyRootfs,
+		LogConfig:      api.LogConfig{Type: config.LogDriverFile},
+		SecurityOpt:    append([]string{"profile=" + info.HostConfig.SecurityProfile.Name}, info.HostConfig.SecurityProfile.SecurityOpt...),
+		CapAdd:         info.HostConfig.SecurityProfile.CapAdd,
+		CapDrop:        info.HostConfig.SecurityProfile.CapDrop,
+		Tmpfs:          info.HostConfig.Tmpfs,
+		Annotations:    info.Config.Annotations,
+		Resources: api.Resources{
+			NanoCPUs:          res.NanoCPUs,
+			CPUShares:         int64(res.CPUShares),
+			CpusetCpus:        res.CpusetCpus,
+			CpusetMems:        res.CpusetMems,
+			MemorySwap:        res.MemorySwap,
+			MemoryReservation: res.MemoryReservation,
+		},
+	}
+	if info.LogPath == "" {
+		host.LogConfig.Type = config.LogDriverNone
+	}
+	if memory, err := container.ParseByteSize(res.Memory); err == nil {
+		host.Memory = memory
+	}
+	if pct, err := strconv.ParseInt(res.CPU, 10, 64); err == nil && res.NanoCPUs == 0 {
+		host.CPUPeriod = 100000
+		host.CPUQuota = pct * 1000
+	}
+	for _, d := range res.DeviceReadBps {
+		host.BlkioDeviceReadBps = append(host.BlkioDeviceReadBps, api.ThrottleDevice{Path: d.Path, Rate: d.Rate})
+	}
+	for _, d := range res.DeviceWriteBps {
+		host.BlkioDeviceWriteBps = append(host.BlkioDeviceWriteBps, api.ThrottleDevice{Path: d.Path, Rate: d.Rate})
+	}
+	if res.PidsLimit != 0 {
+		limit := res.PidsLimit
+		host.PidsLimit = &limit
+	}
+
+	ports := map[string][]api.PortBinding{}
+	for _, b := range info.NetworkSettings.Ports {
+		key := b.ContainerPort + "/" + b.Protocol
+		ports[key] = append(ports[key], api.PortBinding{HostIP: "0.0.0.0", HostPort: b.HostPort})
+	}
+	host.PortBindings = ports
+
+	mounts := []api.MountPoint{}
+	for _, m := range info.Mounts {
+		mounts = append(mounts, api.MountPoint{
+			Type:        m.Type,
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			Mode:        m.Mode,
+			RW:          m.RW,
+		})
+	}
+
+	return writeJSON(w, http.StatusOK, api.ContainerJSON{
+		ID:           info.ID,
+		Created:      formatTime(info.Created),
+		Path:         info.Path,
+		Args:         info.Args,
+		State:        state,
+		Image:        info.Image,
+		Name:         dockerName(info.Name),
+		RestartCount: info.RestartCount,
+		LogPath:      info.LogPath,
+		HostConfig:   host,
+
+		AppArmorProfile: info.HostConfig.SecurityProfile.AppArmorProfile,
+		Config: &api.ContainerConfig{
+			Image:      info.Config.Image,
+			Cmd:        info.Config.Cmd,
+			Env:        info.Config.Env,
+			Labels:     info.Config.Labels,
+			Tty:        info.Config.TTY,
+			OpenStdin:  info.Config.OpenStdin,
+			StopSignal: info.Config.StopSignal,
+		},
+		Mounts:          mounts,
+		NetworkSettings: &api.NetworkSettings{Ports: ports},
+	})
+}
+
+// formatTime memformat waktu seperti Docker, waktu kosong menjadi zero time
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// containerStart menangani POST /containers/{id}/start
+func (d *Daemon) containerStart(w http.ResponseWriter, r *http.Request, params []string) error {
+	info, err := container.InspectContainer(params[0])
+	if err != nil {
+		return err
+	}
+	if info.State.Running {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	if err := container.StartContainer(info.ID); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// containerStop menangani POST /containers/{id}/stop
+func (d *Daemon) containerStop(w http.ResponseWriter, r *http.Request, params []string) error {
+	timeout := container.DefaultStopTimeout
+	if value := r.URL.Query().Get("t"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return httpError(http.StatusBadRequest, "timeout tidak valid: %q", value)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	info, err := container.InspectContainer(params[0])
+	if err != nil {
+		return err
+	}
+	if !info.State.Running && !info.State.Restarting {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	if err := container.StopContainer(info.ID, timeout); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// containerKill menangani POST /containers/{id}/kill. Tanpa parameter
+// signal, container dikirim SIGKILL seperti di Docker.
+func (d *Daemon) containerKill(w http.ResponseWriter, r *http.Request, params []string) error {
+	signal := r.URL.Query().Get("signal")
+	if signal == "" {
+		signal = "KILL"
+	}
+	if err := container.KillContainer(params[0], signal); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// containerPause menangani POST /containers/{id}/pause
+func (d *Daemon) containerPause(w http.ResponseWriter, r *http.Request, params []string) error {
+	if err := container.PauseContainer(params[0]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// containerUnpause menangani POST /containers/{id}/unpause
+func (d *Daemon) containerUnpause(w http.ResponseWriter, r *http.Request, params []string) error {
+	if err := container.UnpauseContainer(params[0]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// containerUpdate menangani POST /containers/{id}/update. Hanya resource
+// limits yang diisi body yang diubah.
+func (d *Daemon) containerUpdate(w http.ResponseWriter, r *http.Request, params []string) error {
+	var req api.Resources
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	err := container.UpdateContainerResources(params[0], func(resources *container.Resources) error {
+		return applyResources(req, resources)
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, api.ContainerUpdateResponse{Warnings: []string{}})
+}
+
+// containerWait menangani POST /containers/{id}/wait. Header dikirim lebih
+// dulu agar client tahu container ada, lalu body dikirim ketika kondisi
+// terpenuhi.
+func (d *Daemon) containerWait(w http.ResponseWriter, r *http.Request, params []string) error {
+	condition := r.URL.Query().Get("condition")
+	switch condition {
+	case "", "not-running", "next-exit", "removed":
+	default:
+		return httpError(http.StatusBadRequest, "condition tidak valid: %q", condition)
+	}
+
+	info, err := container.InspectContainer(params[0])
+	if err != nil {
+		return err
+	}
+	id := info.ID
+	lastExit := info.State.ExitCode
+	finished := info.State.FinishedAt
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		info, err := container.InspectContainer(id)
+		if err != nil {
+			// Container sudah dihapus (misalnya AutoRemove), pakai exit code
+			// terakhir yang terlihat
+			return writeBody(w, api.WaitResponse{StatusCode: int64(lastExit)})
+		}
+		lastExit = info.State.ExitCode
+
+		stopped := !info.State.Running && !info.State.Restarting
+		if (condition == "" || condition == "not-running") && stopped ||
+			condition == "next-exit" && stopped && info.State.FinishedAt.After(finished) {
+			return writeBody(w, api.WaitResponse{StatusCode: int64(lastExit)})
+		}
+
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeBody menulis body JSON setelah header response terkirim
+func writeBody(w http.ResponseWriter, v interface{}) error {
+	json.NewEncoder(w).Encode(v)
+	return nil
+}
+
+// containerLogs menangani GET /containers/{id}/logs
+func (d *Daemon) containerLogs(w http.ResponseWriter, r *http.Request, params []string) error {
+	stdout, stderr := boolValue(r, "stdout"), boolValue(r, "stderr")
+	if !stdout && !stderr {
+		return httpError(http.StatusBadRequest, "pilih minimal satu dari stdout atau stderr")
+	}
+
+	info, err := container.InspectContainer(params[0])
+	if err != nil {
+		return err
+	}
+
+	// Header baru dikirim saat ada data pertama sehingga error sebelum itu
+	// (misalnya log driver none) masih bisa dilaporkan sebagai JSON
+	out := &lazyWriter{w: w, contentType: api.MediaTypeRawStream}
+	var dst io.Writer = out
+	if !info.Config.TTY {
+		out.contentType = api.MediaTypeMultiplexedStream
+		muxOut, muxErr := api.NewMultiplexedWriters(out)
+		dst = muxOut
+		if !stdout {
+			dst = muxErr
+		}
+	}
+
+	if err := container.WriteContainerLogs(r.Context(), info.ID, boolValue(r, "follow"), dst); err != nil {
+		if out.started {
+			return nil
+		}
+		return err
+	}
+	out.start()
+	return nil
+}
+
+// lazyWriter menunda pengiriman header response sampai Write pertama dan
+// mem-flush setiap Write agar log follow langsung sampai ke client
+type lazyWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+func (l *lazyWriter) start() {
+	if l.started {
+		return
+	}
+	l.started = true
+	l.w.Header().Set("Content-Type", l.contentType)
+	l.w.WriteHeader(http.StatusOK)
+}
+
+func (l *lazyWriter) Write(p []byte) (int, error) {
+	l.start()
+	n, err := l.w.Write(p)
+	if f, ok := l.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
+// containerRemove menangani DELETE /containers/{id}
+func (d *Daemon) containerRemove(w http.ResponseWriter, r *http.Request, params []string) error {
+	if err := container.RemoveContainer(params[0], boolValue(r, "force")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// containerPrune menangani POST /containers/prune. Minidocker tidak
+// menghitung ukuran rootfs, jadi SpaceReclaimed selalu 0.
+func (d *Daemon) containerPrune(w http.ResponseWriter, r *http.Request, _ []string) error {
+	filters, err := filtersValue(r)
+	if err != nil {
+		return err
+	}
+	removed, err := container.PruneContainers(filters)
+	if err != nil {
+		return err
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+	return writeJSON(w, http.StatusOK, api.ContainersPruneReport{ContainersDeleted: removed})
+}
+
+// containerResume menangani POST /containers/resume
+func (d *Daemon) containerResume(w http.ResponseWriter, r *http.Request, _ []string) error {
+	resumed, err := container.ResumeContainers()
+	if err != nil {
+		return err
+	}
+	if resumed == nil {
+		resumed = []string{}
+	}
+	return writeJSON(w, http.StatusOK, api.ContainersResumeReport{ContainersResumed: resumed})
+}
+
+// dockerName menambahkan prefix "/" seperti nama container Docker. Container
+// tanpa nama tetap bernama kosong, bukan "/".
+func dockerName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "/" + name
+}
+
+// dockerNames mengembalikan field Names untuk GET /containers/json
+func dockerNames(name string) []string {
+	if name == "" {
+		return []string{}
+	}
+	return []string{dockerName(name)}
+}
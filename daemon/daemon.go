@@ -0,0 +1,166 @@
+// Package daemon menjalankan minidockerd: proses jangka panjang yang memiliki
+// state container dan melayani subset Docker Engine API lewat Unix socket.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/config"
+)
+
+// Options berisi pengaturan daemon
+type Options struct {
+	// Socket adalah path Unix socket tempat API dilayani
+	Socket string
+	// PidFile mencatat PID daemon yang sedang berjalan
+	PidFile string
+	// Config memberi nilai default untuk container yang dibuat lewat API
+	Config *config.Config
+}
+
+// Daemon adalah server API minidocker
+type Daemon struct {
+	opts   Options
+	server *http.Server
+	execs  *execStore
+}
+
+// New membuat daemon baru
+func New(opts Options) *Daemon {
+	d := &Daemon{opts: opts, execs: newExecStore()}
+	d.server = &http.Server{
+		Handler:           d.logRequests(http.HandlerFunc(d.route)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return d
+}
+
+// Run melayani API sampai daemon menerima SIGINT atau SIGTERM
+func (d *Daemon) Run() error {
+	listener, err := d.listen()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(d.opts.Socket)
+
+	if d.opts.PidFile != "" {
+		if err := os.MkdirAll(filepath.Dir(d.opts.PidFile), 0755); err != nil {
+			listener.Close()
+			return fmt.Errorf("gagal membuat direktori pid file: %v", err)
+		}
+		if err := os.WriteFile(d.opts.PidFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+			listener.Close()
+			return fmt.Errorf("gagal menulis pid file: %v", err)
+		}
+		defer os.Remove(d.opts.PidFile)
+	}
+
+	// Container dengan restart policy always/unless-stopped dijalankan
+	// kembali saat daemon mulai, misalnya setelah reboot
+	resumed, err := container.ResumeContainers()
+	if err != nil {
+		log.Printf("Warning: gagal menjalankan kembali container: %v", err)
+	}
+	for _, id := range resumed {
+		log.Printf("Container %s dijalankan kembali", container.ShortID(id))
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- d.server.Serve(listener)
+	}()
+	log.Printf("minidockerd mendengarkan di %s (API %s)", d.opts.Socket, api.Version)
+
+	select {
+	case err := <-errc:
+		return err
+	case sig := <-signals:
+		log.Printf("Menerima %s, menghentikan daemon", sig)
+	}
+
+	// Container tetap berjalan di bawah shim masing-masing, hanya API yang
+	// dihentikan
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("gagal menghentikan server API: %v", err)
+	}
+	return nil
+}
+
+// listen membuka Unix socket daemon. Socket sisa daemon yang sudah mati
+// dihapus, tetapi socket yang masih dilayani daemon lain tidak disentuh.
+func (d *Daemon) listen() (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(d.opts.Socket), 0755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori socket: %v", err)
+	}
+	if _, err := os.Stat(d.opts.Socket); err == nil {
+		if conn, err := net.DialTimeout("unix", d.opts.Socket, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("daemon lain sudah berjalan di %s", d.opts.Socket)
+		}
+		if err := os.Remove(d.opts.Socket); err != nil {
+			return nil, fmt.Errorf("gagal menghapus socket lama: %v", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", d.opts.Socket)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka socket %s: %v", d.opts.Socket, err)
+	}
+	// Akses ke socket setara dengan root, jadi hanya root dan grup pemilik
+	if err := os.Chmod(d.opts.Socket, 0660); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("gagal mengatur izin socket: %v", err)
+	}
+	return listener, nil
+}
+
+// statusRecorder mencatat kode status response untuk log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush meneruskan flush untuk response streaming seperti logs
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap dipakai http.ResponseController untuk hijack koneksi exec
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// logRequests mencatat setiap request ke log daemon
+func (d *Daemon) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
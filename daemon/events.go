@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+)
+
+// events menangani GET /events. Seperti Docker, tanpa since dan until hanya
+// event baru yang dikirim, dan stream berjalan terus sampai until atau
+// client memutus koneksi.
+func (d *Daemon) events(w http.ResponseWriter, r *http.Request, _ []string) error {
+	filters, err := filtersValue(r)
+	if err != nil {
+		return err
+	}
+	if err := container.ValidateEventFilters(filters); err != nil {
+		return httpError(http.StatusBadRequest, "%v", err)
+	}
+
+	now := time.Now()
+	since, err := container.ParseEventTime(r.URL.Query().Get("since"), now)
+	if err != nil {
+		return httpError(http.StatusBadRequest, "since: %v", err)
+	}
+	until, err := container.ParseEventTime(r.URL.Query().Get("until"), now)
+	if err != nil {
+		return httpError(http.StatusBadRequest, "until: %v", err)
+	}
+	if since.IsZero() && until.IsZero() {
+		since = now
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	enc := json.NewEncoder(w)
+	opts := container.EventOptions{
+		Since:   since,
+		Until:   until,
+		Filters: filters,
+		Follow:  until.IsZero() || until.After(now),
+	}
+	container.Events(r.Context(), opts, func(event container.Event) error {
+		if err := enc.Encode(apiEvent(event)); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	return nil
+}
+
+// apiEvent mengubah event jurnal ke format Docker
+func apiEvent(event container.Event) api.EventMessage {
+	msg := api.EventMessage{
+		Type:     event.Type,
+		Action:   event.Action,
+		Actor:    api.EventActor{ID: event.ID, Attributes: event.Attributes},
+		Scope:    "local",
+		Time:     event.Time.Unix(),
+		TimeNano: event.Time.UnixNano(),
+	}
+	if msg.Actor.Attributes == nil {
+		msg.Actor.Attributes = map[string]string{}
+	}
+	if event.Type == container.EventTypeContainer {
+		msg.Status = event.Action
+		msg.ID = event.ID
+		msg.From = event.Attributes["image"]
+	}
+	return msg
+}
@@ -0,0 +1,243 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// execInstance adalah perintah exec yang dibuat lewat POST /containers/{id}/exec
+type execInstance struct {
+	id          string
+	containerID string
+	config      api.ExecConfig
+
+	started  bool
+	running  bool
+	exitCode int
+	pid      int
+}
+
+// execRetention adalah lama instance exec disimpan setelah selesai, atau
+// setelah dibuat jika tidak pernah dijalankan, agar exit code-nya masih bisa
+// dibaca lewat GET /exec/{id}/json
+const execRetention = 5 * time.Minute
+
+// execStore menyimpan instance exec selama daemon berjalan. Seperti Docker,
+// instance exec tidak disimpan ke disk dan dihapus setelah execRetention.
+type execStore struct {
+	mu    sync.Mutex
+	execs map[string]*execInstance
+}
+
+func newExecStore() *execStore {
+	return &execStore{execs: map[string]*execInstance{}}
+}
+
+func (s *execStore) add(e *execInstance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.execs[e.id] = e
+	time.AfterFunc(execRetention, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !e.started {
+			delete(s.execs, e.id)
+		}
+	})
+}
+
+// inspect mengembalikan salinan state exec
+func (s *execStore) inspect(id string) (api.ExecInspect, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.execs[id]
+	if !ok {
+		return api.ExecInspect{}, false
+	}
+	return api.ExecInspect{
+		ID:          e.id,
+		ContainerID: e.containerID,
+		Running:     e.running,
+		ExitCode:    e.exitCode,
+		Pid:         e.pid,
+	}, true
+}
+
+// start menandai exec sudah dijalankan. Setiap exec hanya bisa dijalankan sekali.
+func (s *execStore) start(id string) (*execInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.execs[id]
+	if !ok {
+		return nil, httpError(http.StatusNotFound, "exec %s tidak ditemukan", id)
+	}
+	if e.started {
+		return nil, httpError(http.StatusConflict, "exec %s sudah dijalankan", id)
+	}
+	e.started = true
+	return e, nil
+}
+
+func (s *execStore) setRunning(e *execInstance, pid int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.running = true
+	e.pid = pid
+}
+
+func (s *execStore) setExited(e *execInstance, exitCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.running = false
+	e.exitCode = exitCode
+	time.AfterFunc(execRetention, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.execs, e.id)
+	})
+}
+
+// execCreate menangani POST /containers/{id}/exec
+func (d *Daemon) execCreate(w http.ResponseWriter, r *http.Request, params []string) error {
+	var config api.ExecConfig
+	if err := decodeBody(r, &config); err != nil {
+		return err
+	}
+	if len(config.Cmd) == 0 {
+		return httpError(http.StatusBadRequest, "perintah exec kosong")
+	}
+	// Seperti create container, exec dengan Tty ditolak karena daemon
+	// belum bisa mengalokasikan pseudo-terminal
+	if config.Tty {
+		return httpError(http.StatusBadRequest, "Tty tidak didukung karena daemon belum mengalokasikan pseudo-terminal untuk exec")
+	}
+	if config.WorkingDir != "" && !path.IsAbs(config.WorkingDir) {
+		return httpError(http.StatusBadRequest, "working directory exec harus path absolut: %q", config.WorkingDir)
+	}
+
+	info, err := container.InspectContainer(params[0])
+	if err != nil {
+		return err
+	}
+	if info.State.Paused {
+		return httpError(http.StatusConflict, "container %s sedang di-pause, jalankan unpause terlebih dahulu", params[0])
+	}
+	if !info.State.Running {
+		return httpError(http.StatusConflict, "container %s tidak berjalan", params[0])
+	}
+
+	e := &execInstance{id: utils.GenerateID(64), containerID: info.ID, config: config}
+	d.execs.add(e)
+	return writeJSON(w, http.StatusCreated, api.IDResponse{ID: e.id})
+}
+
+// execStart menangani POST /exec/{id}/start. Tanpa Detach, koneksi HTTP
+// di-hijack menjadi stream dua arah: input client diteruskan ke stdin
+// perintah dan output dikirim balik dengan format multiplexed.
+func (d *Daemon) execStart(w http.ResponseWriter, r *http.Request, params []string) error {
+	var config api.ExecStartConfig
+	if err := decodeBody(r, &config); err != nil {
+		return err
+	}
+	if config.Tty {
+		return httpError(http.StatusBadRequest, "Tty tidak didukung karena daemon belum mengalokasikan pseudo-terminal untuk exec")
+	}
+
+	e, err := d.execs.start(params[0])
+	if err != nil {
+		return err
+	}
+	cmd, err := container.ExecCommand(e.containerID, container.ExecOptions{
+		Cmd:        e.config.Cmd,
+		Env:        e.config.Env,
+		WorkingDir: e.config.WorkingDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	if config.Detach {
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("gagal menjalankan exec: %v", err)
+		}
+		d.execs.setRunning(e, cmd.Process.Pid)
+		go func() {
+			d.execs.setExited(e, exitCode(cmd.Wait()))
+		}()
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return fmt.Errorf("gagal meng-hijack koneksi: %v", err)
+	}
+	defer conn.Close()
+
+	contentType := api.MediaTypeMultiplexedStream
+	if r.Header.Get("Upgrade") != "" {
+		fmt.Fprintf(conn, "HTTP/1.1 101 UPGRADED\r\nContent-Type: %s\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n", contentType)
+	} else {
+		fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Type: %s\r\n\r\n", contentType)
+	}
+
+	stdout, stderr := api.NewMultiplexedWriters(conn)
+	if e.config.AttachStdout {
+		cmd.Stdout = stdout
+	}
+	if e.config.AttachStderr {
+		cmd.Stderr = stderr
+	}
+
+	if e.config.AttachStdin {
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("gagal membuat pipe stdin: %v", err)
+		}
+		// Sisa data di buffer hijack ikut dibaca sebelum koneksi
+		go func() {
+			io.Copy(stdin, buf.Reader)
+			stdin.Close()
+		}()
+	}
+
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(stderr, "gagal menjalankan exec: %v\n", err)
+		d.execs.setExited(e, 126)
+		return nil
+	}
+	d.execs.setRunning(e, cmd.Process.Pid)
+	d.execs.setExited(e, exitCode(cmd.Wait()))
+	return nil
+}
+
+// execInspect menangani GET /exec/{id}/json
+func (d *Daemon) execInspect(w http.ResponseWriter, r *http.Request, params []string) error {
+	info, ok := d.execs.inspect(params[0])
+	if !ok {
+		return httpError(http.StatusNotFound, "exec %s tidak ditemukan", params[0])
+	}
+	return writeJSON(w, http.StatusOK, info)
+}
+
+// exitCode mengambil exit code dari hasil cmd.Wait
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return 126
+}
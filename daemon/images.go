@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+)
+
+// imageList menangani GET /images/json
+func (d *Daemon) imageList(w http.ResponseWriter, r *http.Request, _ []string) error {
+	filters, err := filtersValue(r)
+	if err != nil {
+		return err
+	}
+	images, err := container.ListImages()
+	if err != nil {
+		return err
+	}
+	images, err = container.FilterImages(images, filters)
+	if err != nil {
+		return httpError(http.StatusBadRequest, "%v", err)
+	}
+
+	list := []api.ImageSummary{}
+	for _, img := range images {
+		summary := api.ImageSummary{
+			ID:          img.Digest,
+			RepoTags:    []string{img.Name + ":" + img.Tag},
+			RepoDigests: []string{},
+			Created:     img.CreatedAt.Unix(),
+			Size:        img.Size,
+			SharedSize:  -1,
+			Labels:      img.Labels,
+			Containers:  -1,
+		}
+		if summary.Labels == nil {
+			summary.Labels = map[string]string{}
+		}
+		list = append(list, summary)
+	}
+	return writeJSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,214 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// versionPrefix adalah prefix versi opsional di path, misalnya /v1.43
+var versionPrefix = regexp.MustCompile(`^/v[0-9]+(\.[0-9]+)*`)
+
+// handlerFunc adalah handler endpoint. Error yang dikembalikan ditulis
+// sebagai response JSON dengan kode status sesuai jenisnya.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params []string) error
+
+// route adalah satu endpoint. Segmen pattern "*" cocok dengan satu segmen
+// path apa pun dan diteruskan ke handler sebagai params.
+type route struct {
+	method  string
+	pattern []string
+	handler handlerFunc
+}
+
+// routes mengembalikan semua endpoint yang didukung daemon
+func (d *Daemon) routes() []route {
+	return []route{
+		{"GET", []string{"_ping"}, d.ping},
+		{"HEAD", []string{"_ping"}, d.ping},
+		{"GET", []string{"version"}, d.version},
+		{"GET", []string{"events"}, d.events},
+
+		{"POST", []string{"containers", "create"}, d.containerCreate},
+		{"POST", []string{"containers", "prune"}, d.containerPrune},
+		{"POST", []string{"containers", "resume"}, d.containerResume},
+		{"GET", []string{"containers", "json"}, d.containerList},
+		{"GET", []string{"containers", "*", "json"}, d.containerInspect},
+		{"POST", []string{"containers", "*", "start"}, d.containerStart},
+		{"POST", []string{"containers", "*", "stop"}, d.containerStop},
+		{"POST", []string{"containers", "*", "kill"}, d.containerKill},
+		{"POST", []string{"containers", "*", "pause"}, d.containerPause},
+		{"POST", []string{"containers", "*", "unpause"}, d.containerUnpause},
+		{"POST", []string{"containers", "*", "update"}, d.containerUpdate},
+		{"POST", []string{"containers", "*", "wait"}, d.containerWait},
+		{"GET", []string{"containers", "*", "logs"}, d.containerLogs},
+		{"DELETE", []string{"containers", "*"}, d.containerRemove},
+
+		{"POST", []string{"containers", "*", "exec"}, d.execCreate},
+		{"POST", []string{"exec", "*", "start"}, d.execStart},
+		{"GET", []string{"exec", "*", "json"}, d.execInspect},
+
+		{"GET", []string{"images", "json"}, d.imageList},
+
+		{"GET", []string{"volumes"}, d.volumeList},
+		{"POST", []string{"volumes", "create"}, d.volumeCreate},
+		{"GET", []string{"volumes", "*"}, d.volumeInspect},
+		{"DELETE", []string{"volumes", "*"}, d.volumeRemove},
+		{"POST", []string{"volumes", "*", "backup"}, d.volumeBackup},
+		{"POST", []string{"volumes", "*", "restore"}, d.volumeRestore},
+	}
+}
+
+// route mencocokkan request dengan endpoint dan menjalankan handler-nya
+func (d *Daemon) route(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("API-Version", api.Version)
+	w.Header().Set("Server", "minidockerd")
+
+	path := versionPrefix.ReplaceAllString(r.URL.Path, "")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	methodAllowed := true
+	for _, rt := range d.routes() {
+		params, ok := matchPattern(rt.pattern, segments)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodAllowed = false
+			continue
+		}
+		if err := rt.handler(w, r, params); err != nil {
+			writeError(w, err)
+		}
+		return
+	}
+
+	if !methodAllowed {
+		writeError(w, httpError(http.StatusMethodNotAllowed, "method %s tidak didukung untuk %s", r.Method, path))
+		return
+	}
+	writeError(w, httpError(http.StatusNotFound, "endpoint %s tidak didukung", path))
+}
+
+// matchPattern mencocokkan segmen path dengan pattern route
+func matchPattern(pattern, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i, p := range pattern {
+		if p == "*" {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params = append(params, segments[i])
+			continue
+		}
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// statusError adalah error dengan kode status HTTP tertentu
+type statusError struct {
+	status int
+	msg    string
+}
+
+func (e *statusError) Error() string { return e.msg }
+
+// httpError membuat error dengan kode status tertentu
+func httpError(status int, format string, args ...interface{}) error {
+	return &statusError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+// writeError menulis error sebagai {"message": ...} dengan kode status
+// yang sesuai jenis error
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var se *statusError
+	switch {
+	case errors.As(err, &se):
+		status = se.status
+	case errors.Is(err, container.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, container.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, container.ErrAmbiguousID), errors.Is(err, container.ErrInvalidParameter):
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, api.ErrorResponse{Message: err.Error()})
+}
+
+// writeJSON menulis v sebagai response JSON. Error penulisan tidak bisa
+// dilaporkan lagi ke client karena header sudah terkirim, jadi selalu nil.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+	return nil
+}
+
+// decodeBody membaca body JSON request. Body kosong dibiarkan sebagai nilai
+// nol, sama seperti Docker.
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		return httpError(http.StatusBadRequest, "body request tidak valid: %v", err)
+	}
+	return nil
+}
+
+// boolValue membaca parameter query boolean seperti all=1 atau force=true
+func boolValue(r *http.Request, name string) bool {
+	value := strings.ToLower(r.URL.Query().Get(name))
+	return value != "" && value != "0" && value != "false" && value != "no"
+}
+
+// filtersValue membaca parameter query filters menjadi container.Filters
+func filtersValue(r *http.Request) (container.Filters, error) {
+	filters, err := api.DecodeFilters(r.URL.Query().Get("filters"))
+	if err != nil {
+		return nil, httpError(http.StatusBadRequest, "%v", err)
+	}
+	return container.Filters(filters), nil
+}
+
+// ping menjawab GET/HEAD /_ping yang dipakai client untuk negosiasi versi
+func (d *Daemon) ping(w http.ResponseWriter, r *http.Request, _ []string) error {
+	w.Header().Set("Docker-Experimental", "false")
+	w.Header().Set("OSType", runtime.GOOS)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len("OK")))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		io.WriteString(w, "OK")
+	}
+	return nil
+}
+
+// version menjawab GET /version
+func (d *Daemon) version(w http.ResponseWriter, r *http.Request, _ []string) error {
+	kernel, _ := utils.ExecuteCommand("uname", "-r")
+	return writeJSON(w, http.StatusOK, api.VersionResponse{
+		Version:       "minidocker",
+		APIVersion:    api.Version,
+		MinAPIVersion: api.MinVersion,
+		GoVersion:     runtime.Version(),
+		Os:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		KernelVersion: strings.TrimSpace(kernel),
+	})
+}
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/user/minidocker/api"
+	"github.com/user/minidocker/container"
+	"github.com/user/minidocker/pkg/utils"
+)
+
+// apiVolume mengubah volume minidocker menjadi format Docker
+func apiVolume(v container.Volume) api.Volume {
+	volume := api.Volume{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  v.CreatedAt.UTC().Format(time.RFC3339),
+		Labels:     v.Labels,
+		Scope:      "local",
+		Options:    map[string]string{},
+	}
+	if volume.Labels == nil {
+		volume.Labels = map[string]string{}
+	}
+	return volume
+}
+
+// volumeList menangani GET /volumes
+func (d *Daemon) volumeList(w http.ResponseWriter, r *http.Request, _ []string) error {
+	filters, err := filtersValue(r)
+	if err != nil {
+		return err
+	}
+	volumes, err := container.ListVolumes()
+	if err != nil {
+		return err
+	}
+	volumes, err = container.FilterVolumes(volumes, filters)
+	if err != nil {
+		return httpError(http.StatusBadRequest, "%v", err)
+	}
+
+	resp := api.VolumeListResponse{Volumes: []api.Volume{}, Warnings: []string{}}
+	for _, v := range volumes {
+		resp.Volumes = append(resp.Volumes, apiVolume(v))
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+// volumeCreate menangani POST /volumes/create
+func (d *Daemon) volumeCreate(w http.ResponseWriter, r *http.Request, _ []string) error {
+	var req api.VolumeCreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Driver != "" && req.Driver != "local" {
+		return httpError(http.StatusBadRequest, "driver volume %q tidak didukung, hanya local", req.Driver)
+	}
+	// Seperti Docker, volume tanpa nama mendapat nama acak
+	if req.Name == "" {
+		req.Name = utils.GenerateID(64)
+	}
+
+	volume, err := container.CreateVolume(req.Name, req.Labels)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusCreated, apiVolume(*volume))
+}
+
+// volumeInspect menangani GET /volumes/{name}
+func (d *Daemon) volumeInspect(w http.ResponseWriter, r *http.Request, params []string) error {
+	volume, err := container.GetVolume(params[0])
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, apiVolume(*volume))
+}
+
+// volumeRemove menangani DELETE /volumes/{name}
+func (d *Daemon) volumeRemove(w http.ResponseWriter, r *http.Request, params []string) error {
+	if err := container.RemoveVolume(params[0], boolValue(r, "force")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// backupPath membaca path file backup dari body request volume backup dan
+// restore. Path relatif ditolak karena direktori kerja client tidak
+// diketahui daemon.
+func backupPath(r *http.Request) (string, error) {
+	var req api.VolumeBackupRequest
+	if err := decodeBody(r, &req); err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(req.Path) {
+		return "", httpError(http.StatusBadRequest, "path backup harus absolut: %q", req.Path)
+	}
+	return req.Path, nil
+}
+
+// volumeBackup menangani POST /volumes/{name}/backup
+func (d *Daemon) volumeBackup(w http.ResponseWriter, r *http.Request, params []string) error {
+	path, err := backupPath(r)
+	if err != nil {
+		return err
+	}
+	if err := container.BackupVolumeData(params[0], path); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// volumeRestore menangani POST /volumes/{name}/restore
+func (d *Daemon) volumeRestore(w http.ResponseWriter, r *http.Request, params []string) error {
+	path, err := backupPath(r)
+	if err != nil {
+		return err
+	}
+	if err := container.RestoreVolumeData(params[0], path); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
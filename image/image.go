@@ -10,18 +10,37 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-)
 
-const (
-	ImageDir = "/var/run/minidocker/images"
+	"github.com/user/minidocker/pkg/config"
+	"github.com/user/minidocker/pkg/store"
 )
 
+// ImageDir menyimpan arsip image, diatur oleh SetRoot
+var ImageDir = filepath.Join(config.DefaultRoot, "images")
+
+// SetRoot memindahkan direktori image ke bawah root data minidocker
+func SetRoot(root string) {
+	ImageDir = filepath.Join(root, "images")
+}
+
+// output menerima pesan progres dan peringatan, diatur oleh SetOutput
+var output io.Writer = os.Stdout
+
+// SetOutput mengatur tujuan pesan progres dan peringatan (default stdout)
+func SetOutput(w io.Writer) {
+	output = w
+}
+
 // ImageConfig merepresentasikan konfigurasi image
 type ImageConfig struct {
 	Name    string   `json:"name"`
 	Version string   `json:"version"`
 	Cmd     []string `json:"cmd"`
 	Env     []string `json:"env"`
+	// StopSignal adalah sinyal untuk menghentikan container, misalnya SIGQUIT
+	StopSignal string `json:"stop_signal,omitempty"`
+	// Labels diwarisi oleh container yang dibuat dari image ini
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // InitImageDir membuat direktori untuk menyimpan image
@@ -40,7 +59,7 @@ func ExtractImage(imageName, targetDir string) error {
 	}
 
 	// Cek apakah image sudah tersedia dalam cache
-	imagePath := filepath.Join(ImageDir, imageName+".tar.gz")
+	imagePath := ArchivePath(imageName)
 	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
 		// Download image jika belum tersedia
 		if err := downloadImage(imageName, imagePath); err != nil {
@@ -114,13 +133,13 @@ func ExtractImage(imageName, targetDir string) error {
 				return fmt.Errorf("gagal membuat symlink %s -> %s: %v", path, header.Linkname, err)
 			}
 		default:
-			fmt.Printf("Mengabaikan %s (tipe=%d)\n", header.Name, header.Typeflag)
+			fmt.Fprintf(output, "Mengabaikan %s (tipe=%d)\n", header.Name, header.Typeflag)
 		}
 	}
 
 	// Ekstrak dan tulis konfigurasi image
 	if err := extractImageConfig(imageName, targetDir); err != nil {
-		fmt.Printf("Warning: gagal ekstrak konfigurasi image: %v\n", err)
+		fmt.Fprintf(output, "Warning: gagal ekstrak konfigurasi image: %v\n", err)
 	}
 
 	return nil
@@ -150,8 +169,8 @@ func downloadImage(imageName, targetPath string) error {
 // createBasicAlpineImage membuat image Alpine sederhana
 func createBasicAlpineImage(targetPath string) error {
 	// Untuk DEMO saja - pada implementasi nyata perlu download dari registry
-	fmt.Println("Image Alpine tidak ditemukan. Dalam implementasi lengkap, ini akan diunduh dari registry")
-	fmt.Println("Untuk demo, kita akan membuat placeholder image...")
+	fmt.Fprintln(output, "Image Alpine tidak ditemukan. Dalam implementasi lengkap, ini akan diunduh dari registry")
+	fmt.Fprintln(output, "Untuk demo, kita akan membuat placeholder image...")
 	
 	// Demo: Buat file tar.gz kosong dengan beberapa file demo
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
@@ -224,8 +243,8 @@ func createBasicAlpineImage(targetPath string) error {
 // createBasicBusyboxImage membuat image Busybox sederhana
 func createBasicBusyboxImage(targetPath string) error {
 	// Sama seperti Alpine, untuk demo saja
-	fmt.Println("Image Busybox tidak ditemukan. Dalam implementasi lengkap, ini akan diunduh dari registry")
-	fmt.Println("Untuk demo, kita akan membuat placeholder image...")
+	fmt.Fprintln(output, "Image Busybox tidak ditemukan. Dalam implementasi lengkap, ini akan diunduh dari registry")
+	fmt.Fprintln(output, "Untuk demo, kita akan membuat placeholder image...")
 	
 	// Demo: Buat file tar.gz kosong dengan beberapa file demo
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
@@ -330,31 +349,100 @@ func addTarFile(tw *tar.Writer, name, content string, mode int64) error {
 	return nil
 }
 
+// ConfigFile adalah nama file konfigurasi image di direktori container,
+// terpisah dari config.json yang berisi metadata container
+const ConfigFile = "image.json"
+
+// configSchema adalah skema file ConfigFile
+var configSchema = store.Schema{
+	Name:    "image config",
+	Version: 1,
+	Migrations: map[int]store.Migration{
+		0: func(doc map[string]interface{}) error { return nil },
+	},
+}
+
+// configPath mengembalikan lokasi ConfigFile untuk rootfs container
+func configPath(rootfs string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(rootfs)), ConfigFile)
+}
+
 // extractImageConfig mengekstrak konfigurasi image dari rootfs
 func extractImageConfig(imageName, rootfs string) error {
 	// Baca konfigurasi dari image-config.json
-	configPath := filepath.Join(rootfs, "image-config.json")
-	data, err := ioutil.ReadFile(configPath)
+	var config ImageConfig
+	data, err := ioutil.ReadFile(filepath.Join(rootfs, "image-config.json"))
 	if err != nil {
 		// Untuk demo, jika tidak ada file config, buat konfigurasi default
-		config := ImageConfig{
-			Name:    imageName,
-			Version: "latest",
-			Cmd:     []string{"/bin/sh"},
-			Env:     []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
-		}
-		
-		data, err = json.Marshal(config)
-		if err != nil {
-			return err
-		}
+		config = defaultImageConfig(imageName)
+	} else if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("gagal parse image-config.json: %v", err)
 	}
-	
+
 	// Tulis konfigurasi ke file
-	configTargetPath := filepath.Join(rootfs, "../config.json")
-	if err := ioutil.WriteFile(configTargetPath, data, 0644); err != nil {
+	if err := configSchema.Write(configPath(rootfs), config); err != nil {
 		return fmt.Errorf("gagal menulis konfigurasi image: %v", err)
 	}
-	
 	return nil
-} 
\ No newline at end of file
+}
+
+// ReadImageConfig membaca konfigurasi image yang ditulis saat ekstraksi ke rootfs
+func ReadImageConfig(rootfs string) (ImageConfig, error) {
+	var config ImageConfig
+	if err := configSchema.Read(configPath(rootfs), &config); err != nil {
+		return config, fmt.Errorf("gagal membaca konfigurasi image: %v", err)
+	}
+	return config, nil
+}
+
+// defaultImageConfig adalah konfigurasi untuk image tanpa image-config.json
+func defaultImageConfig(imageName string) ImageConfig {
+	return ImageConfig{
+		Name:    imageName,
+		Version: "latest",
+		Cmd:     []string{"/bin/sh"},
+		Env:     []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+	}
+}
+
+// ArchivePath mengembalikan lokasi file tar.gz image di cache lokal
+func ArchivePath(imageName string) string {
+	return filepath.Join(ImageDir, imageName+".tar.gz")
+}
+
+// ReadArchiveConfig membaca konfigurasi image langsung dari file tar.gz di
+// cache lokal tanpa mengekstraknya. Image tanpa image-config.json mendapat
+// konfigurasi default yang sama seperti saat ekstraksi.
+func ReadArchiveConfig(imageName string) (ImageConfig, error) {
+	file, err := os.Open(ArchivePath(imageName))
+	if err != nil {
+		return ImageConfig{}, err
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return ImageConfig{}, fmt.Errorf("gagal membaca gzip: %v", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return defaultImageConfig(imageName), nil
+		}
+		if err != nil {
+			return ImageConfig{}, fmt.Errorf("gagal membaca tar: %v", err)
+		}
+		if filepath.Clean(header.Name) != "image-config.json" {
+			continue
+		}
+
+		var config ImageConfig
+		if err := json.NewDecoder(tr).Decode(&config); err != nil {
+			return ImageConfig{}, fmt.Errorf("gagal parse konfigurasi image: %v", err)
+		}
+		return config, nil
+	}
+}
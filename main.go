@@ -1,9 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/urfave/cli/v2"
 	"github.com/user/minidocker/cmd"
@@ -12,12 +14,24 @@ import (
 
 func main() {
 	app := &cli.App{
-		Name:  "minidocker",
-		Usage: "Container runtime sederhana seperti Docker",
+		Name:   "minidocker",
+		Usage:  "Container runtime sederhana seperti Docker",
+		Flags:  cmd.GlobalFlags(),
+		Before: cmd.Setup,
 		Commands: []*cli.Command{
 			cmd.RunCommand(),
 			cmd.ListCommand(),
 			cmd.StopCommand(),
+			cmd.KillCommand(),
+			cmd.RemoveCommand(),
+			cmd.ContainerCommand(),
+			cmd.ResumeCommand(),
+			cmd.UpdateCommand(),
+			cmd.StatsCommand(),
+			cmd.EventsCommand(),
+			cmd.PauseCommand(),
+			cmd.UnpauseCommand(),
+			cmd.InspectCommand(),
 			cmd.LogsCommand(),
 			cmd.ExecCommand(),
 			cmd.VolumeCreateCommand(),
@@ -30,22 +44,81 @@ func main() {
 			cmd.PushCommand(),
 			cmd.ImagesCommand(),
 			cmd.TagCommand(),
+			cmd.DaemonCommand(),
+			cmd.SeccompCommand(),
+			cmd.SecurityProfileCommand(),
+			{
+				Name:     "internal-shim",
+				Usage:    "Perintah internal untuk memantau proses container",
+				HideHelp: true,
+				Hidden:   true,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "attach"},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() < 1 {
+						return fmt.Errorf("ID container diperlukan")
+					}
+					return container.RunShim(ctx.Args().First(), ctx.Bool("attach"))
+				},
+			},
 			{
 				Name:     "internal-start",
 				Usage:    "Perintah internal untuk memulai container",
 				HideHelp: true,
 				Hidden:   true,
 				Action: func(ctx *cli.Context) error {
-					if ctx.NArg() < 1 {
-						return fmt.Errorf("rootfs path diperlukan")
+					return container.InternalStartContainer()
+				},
+			},
+			{
+				Name:     "internal-exec",
+				Usage:    "Perintah internal untuk menjalankan exec di container",
+				HideHelp: true,
+				Hidden:   true,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "workdir", Value: "/"},
+					&cli.StringFlag{Name: "env", Value: "[]"},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() < 2 {
+						return fmt.Errorf("ID container dan perintah diperlukan")
+					}
+					var env []string
+					if err := json.Unmarshal([]byte(ctx.String("env")), &env); err != nil {
+						return fmt.Errorf("environment exec tidak valid: %v", err)
+					}
+					code, err := container.InternalExecContainer(ctx.Args().First(), ctx.String("workdir"), env, ctx.Args().Slice()[1:])
+					if err != nil {
+						return err
+					}
+					os.Exit(code)
+					return nil
+				},
+			},
+			{
+				Name:     "internal-exec-init",
+				Usage:    "Perintah internal untuk memulai proses exec di container",
+				HideHelp: true,
+				Hidden:   true,
+				Action: func(ctx *cli.Context) error {
+					// Error sudah dilaporkan ke helper internal-exec lewat
+					// start pipe dan ditampilkan di sana
+					if err := container.InternalExecInit(); err != nil {
+						return cli.Exit("", 1)
 					}
-					rootfs := ctx.Args().First()
-					return container.InternalStartContainer(rootfs)
+					return nil
 				},
 			},
 		},
 	}
 
+	// Binary yang dipanggil lewat symlink minidockerd langsung menjalankan
+	// daemon, flag global seperti --root dan -H tetap berlaku
+	if filepath.Base(os.Args[0]) == "minidockerd" {
+		app.DefaultCommand = "daemon"
+	}
+
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(fmt.Sprintf("Error menjalankan aplikasi: %v", err))
 	}
@@ -0,0 +1,138 @@
+// Package config membaca konfigurasi global minidocker dari
+// /etc/minidocker/config.json: lokasi data, serta nilai default untuk
+// container baru seperti batas resource, profil keamanan, log driver, dan
+// mirror registry.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// DefaultFile adalah lokasi default file konfigurasi
+	DefaultFile = "/etc/minidocker/config.json"
+	// DefaultRoot menyimpan data persisten (container, image, volume, registry)
+	DefaultRoot = "/var/lib/minidocker"
+	// DefaultExecRoot menyimpan state runtime yang boleh hilang saat reboot
+	DefaultExecRoot = "/var/run/minidocker"
+	// DefaultSeccompDir menyimpan profil seccomp
+	DefaultSeccompDir = "/etc/minidocker/seccomp"
+	// DefaultProfilesDir menyimpan profil keamanan buatan user (JSON atau YAML)
+	DefaultProfilesDir = "/etc/minidocker/profiles"
+
+	// EnvRoot, EnvExecRoot, dan EnvConfig menimpa lokasi dari file konfigurasi
+	EnvRoot     = "MINIDOCKER_ROOT"
+	EnvExecRoot = "MINIDOCKER_EXEC_ROOT"
+	EnvConfig   = "MINIDOCKER_CONFIG"
+)
+
+// Log driver yang didukung
+const (
+	// LogDriverFile menulis output container ke container.log
+	LogDriverFile = "file"
+	// LogDriverNone membuang output container
+	LogDriverNone = "none"
+)
+
+// Config adalah isi file konfigurasi minidocker. Field yang tidak diisi
+// memakai nilai dari Default.
+type Config struct {
+	Root       string `json:"root"`
+	ExecRoot   string `json:"exec_root"`
+	SeccompDir string `json:"seccomp_dir"`
+	// ProfilesDir berisi profil keamanan tambahan untuk --security-profile
+	ProfilesDir string `json:"profiles_dir"`
+
+	// DefaultMemory dan DefaultCPU dipakai jika run tidak diberi --memory
+	// atau --cpu/--cpus. String kosong berarti batas bawaan engine (64m dan
+	// 10%).
+	DefaultMemory string `json:"default_memory"`
+	DefaultCPU    string `json:"default_cpu"`
+
+	SecurityProfile string   `json:"security_profile"`
+	LogDriver       string   `json:"log_driver"`
+	RegistryMirrors []string `json:"registry_mirrors"`
+}
+
+// Default mengembalikan konfigurasi bawaan minidocker
+func Default() *Config {
+	return &Config{
+		Root:            DefaultRoot,
+		ExecRoot:        DefaultExecRoot,
+		SeccompDir:      DefaultSeccompDir,
+		ProfilesDir:     DefaultProfilesDir,
+		DefaultMemory:   "64m",
+		DefaultCPU:      "10",
+		SecurityProfile: "default",
+		LogDriver:       LogDriverFile,
+	}
+}
+
+// Load membaca file konfigurasi di atas nilai default. Jika required false,
+// file yang tidak ada tidak dianggap error.
+func Load(path string, required bool) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("gagal membaca file konfigurasi: %v", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("file konfigurasi %s tidak valid: %v", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("file konfigurasi %s tidak valid: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate memeriksa nilai konfigurasi dan mengubah path menjadi absolut
+func (c *Config) Validate() error {
+	for name, dir := range map[string]*string{
+		"root":         &c.Root,
+		"exec_root":    &c.ExecRoot,
+		"seccomp_dir":  &c.SeccompDir,
+		"profiles_dir": &c.ProfilesDir,
+	} {
+		if *dir == "" {
+			return fmt.Errorf("%s tidak boleh kosong", name)
+		}
+		abs, err := filepath.Abs(*dir)
+		if err != nil {
+			return fmt.Errorf("%s tidak valid: %v", name, err)
+		}
+		*dir = abs
+	}
+	if c.Root == c.ExecRoot {
+		return fmt.Errorf("root dan exec_root harus berbeda")
+	}
+
+	if err := ValidateLogDriver(c.LogDriver); err != nil {
+		return err
+	}
+	for _, mirror := range c.RegistryMirrors {
+		if mirror == "" {
+			return fmt.Errorf("registry_mirrors berisi mirror kosong")
+		}
+	}
+	return nil
+}
+
+// ValidateLogDriver memastikan log driver didukung
+func ValidateLogDriver(driver string) error {
+	switch driver {
+	case LogDriverFile, LogDriverNone:
+		return nil
+	}
+	return fmt.Errorf("log driver %q tidak didukung (gunakan %s atau %s)", driver, LogDriverFile, LogDriverNone)
+}
@@ -0,0 +1,403 @@
+package seccomp
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Instruction adalah satu instruksi BPF classic (struct sock_filter)
+type Instruction struct {
+	Code uint16 `json:"code"`
+	Jt   uint8  `json:"jt"`
+	Jf   uint8  `json:"jf"`
+	K    uint32 `json:"k"`
+}
+
+// Opcode BPF classic yang dipakai program seccomp
+const (
+	bpfLdAbs = 0x20 // BPF_LD | BPF_W | BPF_ABS
+	bpfAndK  = 0x54 // BPF_ALU | BPF_AND | BPF_K
+	bpfJa    = 0x05 // BPF_JMP | BPF_JA
+	bpfJeq   = 0x15 // BPF_JMP | BPF_JEQ | BPF_K
+	bpfJgt   = 0x25 // BPF_JMP | BPF_JGT | BPF_K
+	bpfJge   = 0x35 // BPF_JMP | BPF_JGE | BPF_K
+	bpfJset  = 0x45 // BPF_JMP | BPF_JSET | BPF_K
+	bpfRet   = 0x06 // BPF_RET | BPF_K
+)
+
+// Offset field struct seccomp_data
+const (
+	offsetNr   = 0
+	offsetArch = 4
+	offsetArgs = 16
+)
+
+// Nilai return filter seccomp
+const (
+	retKillProcess = 0x80000000
+	retKillThread  = 0x00000000
+	retTrap        = 0x00030000
+	retErrno       = 0x00050000
+	retTrace       = 0x7ff00000
+	retLog         = 0x7ffc0000
+	retAllow       = 0x7fff0000
+	retData        = 0x0000ffff
+)
+
+// maxInstructions adalah batas panjang program BPF di kernel (BPF_MAXINSNS)
+const maxInstructions = 4096
+
+// x32SyscallBit menandai syscall ABI x32 di audit arch x86_64
+const x32SyscallBit = 0x40000000
+
+// eperm adalah errno default untuk SCMP_ACT_ERRNO
+const eperm = 1
+
+// archInfo menjelaskan satu arsitektur seccomp. Arsitektur tanpa tabel
+// syscall tidak bisa difilter per syscall.
+type archInfo struct {
+	audit uint32
+	// goarch adalah nama arsitektur gaya Go yang dipakai includes/excludes
+	goarch string
+	table  map[string]uint32
+}
+
+var arches = map[string]archInfo{
+	"SCMP_ARCH_X86_64":  {audit: 0xc000003e, goarch: "amd64", table: syscallsX86_64},
+	"SCMP_ARCH_X32":     {audit: 0xc000003e, goarch: "x32"},
+	"SCMP_ARCH_X86":     {audit: 0x40000003, goarch: "386"},
+	"SCMP_ARCH_AARCH64": {audit: 0xc00000b7, goarch: "arm64", table: syscallsGeneric},
+	"SCMP_ARCH_ARM":     {audit: 0x40000028, goarch: "arm"},
+	"SCMP_ARCH_RISCV64": {audit: 0xc00000f3, goarch: "riscv64", table: syscallsGeneric},
+	"SCMP_ARCH_PPC64LE": {audit: 0xc0000015, goarch: "ppc64le"},
+	"SCMP_ARCH_S390X":   {audit: 0x80000016, goarch: "s390x"},
+}
+
+// NativeArch mengembalikan nama arsitektur seccomp untuk proses ini
+func NativeArch() string {
+	for name, info := range arches {
+		if info.goarch == runtime.GOARCH {
+			return name
+		}
+	}
+	return ""
+}
+
+// NativeSyscall mencari nomor syscall di tabel arsitektur native. Tabel
+// hanya tersedia untuk x86_64, aarch64, dan riscv64.
+func NativeSyscall(name string) (uint32, bool) {
+	nr, ok := arches[NativeArch()].table[name]
+	return nr, ok
+}
+
+// Compile menerjemahkan profil ke program BPF untuk arch (kosong berarti
+// arsitektur native). Program memeriksa arsitektur pemanggil lebih dulu:
+// arsitektur yang tidak ada di profil dibunuh, dan arsitektur tanpa tabel
+// syscall (misalnya SCMP_ARCH_X86) ditolak seluruhnya dengan defaultAction,
+// atau EPERM jika defaultAction mengizinkan. Nama syscall yang tidak ada di
+// suatu arsitektur diabaikan untuk arsitektur tersebut.
+func Compile(p *Profile, arch string) ([]Instruction, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if arch == "" {
+		arch = NativeArch()
+	}
+	native, ok := arches[arch]
+	if !ok || native.table == nil {
+		return nil, fmt.Errorf("arsitektur %q tidak didukung", arch)
+	}
+
+	// Arsitektur native selalu diizinkan, ditambah sub-arsitektur dari archMap
+	names := append([]string{arch}, p.Architectures...)
+	for _, m := range p.ArchMap {
+		if m.Arch == arch {
+			names = append(names, m.SubArches...)
+		}
+	}
+	var blocks []string
+	x32 := false
+	seen := map[uint32]bool{}
+	for _, name := range names {
+		info, ok := arches[name]
+		if !ok {
+			return nil, fmt.Errorf("arsitektur %q tidak dikenal", name)
+		}
+		if name == "SCMP_ARCH_X32" {
+			x32 = true
+			continue
+		}
+		if !seen[info.audit] {
+			seen[info.audit] = true
+			blocks = append(blocks, name)
+		}
+	}
+
+	defaultRet := actionValue(p.DefaultAction, p.DefaultErrnoRet)
+	denyRet := defaultRet
+	if p.DefaultAction == ActAllow || p.DefaultAction == ActLog || p.DefaultAction == ActTrace {
+		denyRet = retErrno | eperm
+	}
+
+	a := newAssembler()
+	a.load(offsetArch)
+	labels := make([]int, len(blocks))
+	for i, name := range blocks {
+		labels[i] = a.newLabel()
+		skip := a.newLabel()
+		a.jump(bpfJeq, arches[name].audit, next, skip)
+		a.ja(labels[i])
+		a.mark(skip)
+	}
+	a.ret(retKillThread)
+
+	for i, name := range blocks {
+		info := arches[name]
+		a.mark(labels[i])
+		if info.table == nil {
+			a.ret(denyRet)
+			continue
+		}
+
+		a.load(offsetNr)
+		if name == "SCMP_ARCH_X86_64" {
+			// Syscall x32 memakai audit arch yang sama dengan bit tambahan
+			x32Ret := uint32(retKillThread)
+			if x32 {
+				x32Ret = denyRet
+			}
+			native := a.newLabel()
+			a.jump(bpfJge, x32SyscallBit, next, native)
+			a.ret(x32Ret)
+			a.mark(native)
+		}
+
+		for _, rule := range p.Syscalls {
+			if !rule.appliesTo(name, info) {
+				continue
+			}
+			ret := actionValue(rule.Action, rule.ErrnoRet)
+			if ret == defaultRet && len(rule.Args) == 0 {
+				continue
+			}
+			for _, syscall := range rule.names() {
+				nr, ok := info.table[syscall]
+				if !ok {
+					continue
+				}
+				end := a.newLabel()
+				a.jump(bpfJeq, nr, next, end)
+				for _, arg := range rule.Args {
+					a.compareArg(arg, end)
+				}
+				a.ret(ret)
+				a.mark(end)
+				if len(rule.Args) > 0 {
+					a.load(offsetNr)
+				}
+			}
+		}
+		a.ret(defaultRet)
+	}
+
+	prog, err := a.assemble()
+	if err != nil {
+		return nil, err
+	}
+	if len(prog) > maxInstructions {
+		return nil, fmt.Errorf("program BPF terlalu besar (%d instruksi, maksimal %d)", len(prog), maxInstructions)
+	}
+	return prog, nil
+}
+
+// appliesTo memeriksa includes dan excludes aturan untuk satu arsitektur
+func (s Syscall) appliesTo(name string, info archInfo) bool {
+	matchArch := func(list []string) bool {
+		for _, a := range list {
+			if a == name || a == info.goarch {
+				return true
+			}
+		}
+		return false
+	}
+	if s.Includes != nil {
+		if len(s.Includes.Caps) > 0 {
+			return false
+		}
+		if len(s.Includes.Arches) > 0 && !matchArch(s.Includes.Arches) {
+			return false
+		}
+	}
+	if s.Excludes != nil && matchArch(s.Excludes.Arches) {
+		return false
+	}
+	return true
+}
+
+// actionValue mengubah aksi menjadi nilai return filter
+func actionValue(action Action, errnoRet *uint) uint32 {
+	data := uint32(0)
+	if errnoRet != nil {
+		data = uint32(*errnoRet) & retData
+	}
+	switch action {
+	case ActAllow:
+		return retAllow
+	case ActErrno:
+		if errnoRet == nil {
+			data = eperm
+		}
+		return retErrno | data
+	case ActKillProcess:
+		return retKillProcess
+	case ActTrap:
+		return retTrap
+	case ActTrace:
+		return retTrace | data
+	case ActLog:
+		return retLog
+	}
+	return retKillThread
+}
+
+// next sebagai target jump berarti instruksi berikutnya
+const next = -1
+
+// assembler menyusun program BPF dengan label. Offset jump baru dihitung
+// oleh assemble setelah semua label diketahui posisinya.
+type assembler struct {
+	prog   []Instruction
+	labels []int
+	fixups []fixup
+}
+
+// fixup mencatat field jump yang menunggu posisi label
+type fixup struct {
+	index int
+	label int
+	field byte // 't', 'f', atau 'k' untuk ja
+}
+
+func newAssembler() *assembler {
+	return &assembler{}
+}
+
+func (a *assembler) newLabel() int {
+	a.labels = append(a.labels, -1)
+	return len(a.labels) - 1
+}
+
+func (a *assembler) mark(label int) {
+	a.labels[label] = len(a.prog)
+}
+
+func (a *assembler) emit(ins Instruction) {
+	a.prog = append(a.prog, ins)
+}
+
+func (a *assembler) load(offset uint32) {
+	a.emit(Instruction{Code: bpfLdAbs, K: offset})
+}
+
+func (a *assembler) and(mask uint32) {
+	a.emit(Instruction{Code: bpfAndK, K: mask})
+}
+
+func (a *assembler) ret(value uint32) {
+	a.emit(Instruction{Code: bpfRet, K: value})
+}
+
+// jump menambahkan jump kondisional ke label jt jika benar dan jf jika salah
+func (a *assembler) jump(code uint16, k uint32, jt, jf int) {
+	index := len(a.prog)
+	a.emit(Instruction{Code: code, K: k})
+	if jt != next {
+		a.fixups = append(a.fixups, fixup{index: index, label: jt, field: 't'})
+	}
+	if jf != next {
+		a.fixups = append(a.fixups, fixup{index: index, label: jf, field: 'f'})
+	}
+}
+
+// ja menambahkan jump tanpa syarat, yang jangkauannya tidak dibatasi 255
+func (a *assembler) ja(label int) {
+	a.fixups = append(a.fixups, fixup{index: len(a.prog), label: label, field: 'k'})
+	a.emit(Instruction{Code: bpfJa})
+}
+
+// compareArg membandingkan argumen 64-bit sebagai dua word 32-bit (little
+// endian) dan melompat ke fail jika tidak cocok
+func (a *assembler) compareArg(arg Arg, fail int) {
+	lo := offsetArgs + 8*uint32(arg.Index)
+	hi := lo + 4
+	vhi, vlo := uint32(arg.Value>>32), uint32(arg.Value)
+	ok := a.newLabel()
+
+	switch arg.Op {
+	case OpEqualTo:
+		a.load(hi)
+		a.jump(bpfJeq, vhi, next, fail)
+		a.load(lo)
+		a.jump(bpfJeq, vlo, next, fail)
+	case OpNotEqual:
+		a.load(hi)
+		a.jump(bpfJeq, vhi, next, ok)
+		a.load(lo)
+		a.jump(bpfJeq, vlo, fail, ok)
+	case OpGreaterThan, OpGreaterEqual:
+		code := uint16(bpfJgt)
+		if arg.Op == OpGreaterEqual {
+			code = bpfJge
+		}
+		a.load(hi)
+		a.jump(bpfJgt, vhi, ok, next)
+		a.jump(bpfJeq, vhi, next, fail)
+		a.load(lo)
+		a.jump(code, vlo, ok, fail)
+	case OpLessThan, OpLessEqual:
+		// arg < v sama dengan !(arg >= v), arg <= v sama dengan !(arg > v)
+		code := uint16(bpfJge)
+		if arg.Op == OpLessEqual {
+			code = bpfJgt
+		}
+		a.load(hi)
+		a.jump(bpfJgt, vhi, fail, next)
+		a.jump(bpfJeq, vhi, next, ok)
+		a.load(lo)
+		a.jump(code, vlo, fail, ok)
+	case OpMaskedEqual:
+		mhi, mlo := uint32(arg.Value>>32), uint32(arg.Value)
+		whi, wlo := uint32(arg.ValueTwo>>32), uint32(arg.ValueTwo)
+		a.load(hi)
+		a.and(mhi)
+		a.jump(bpfJeq, whi, next, fail)
+		a.load(lo)
+		a.and(mlo)
+		a.jump(bpfJeq, wlo, next, fail)
+	}
+	a.mark(ok)
+}
+
+// assemble mengisi offset jump dari posisi label
+func (a *assembler) assemble() ([]Instruction, error) {
+	for _, f := range a.fixups {
+		offset := a.labels[f.label] - (f.index + 1)
+		if offset < 0 {
+			return nil, fmt.Errorf("jump mundur di instruksi %d", f.index)
+		}
+		switch f.field {
+		case 'k':
+			a.prog[f.index].K = uint32(offset)
+		default:
+			if offset > 255 {
+				return nil, fmt.Errorf("jump di instruksi %d terlalu jauh (%d)", f.index, offset)
+			}
+			if f.field == 't' {
+				a.prog[f.index].Jt = uint8(offset)
+			} else {
+				a.prog[f.index].Jf = uint8(offset)
+			}
+		}
+	}
+	return a.prog, nil
+}
@@ -0,0 +1,514 @@
+package seccomp
+
+import (
+	"strings"
+	"testing"
+)
+
+// seccompData adalah struct seccomp_data yang diperiksa filter
+type seccompData struct {
+	nr   uint32
+	arch uint32
+	args [6]uint64
+}
+
+// word mengembalikan word 32-bit di offset seperti BPF_LD|BPF_W|BPF_ABS di
+// kernel little endian
+func (d seccompData) word(t *testing.T, offset uint32) uint32 {
+	switch {
+	case offset == offsetNr:
+		return d.nr
+	case offset == offsetArch:
+		return d.arch
+	case offset >= offsetArgs && offset < offsetArgs+48 && offset%4 == 0:
+		arg := d.args[(offset-offsetArgs)/8]
+		if (offset-offsetArgs)%8 == 4 {
+			return uint32(arg >> 32)
+		}
+		return uint32(arg)
+	}
+	t.Fatalf("load dari offset %d tidak valid", offset)
+	return 0
+}
+
+// run menjalankan program BPF classic seperti interpreter kernel untuk
+// instruksi yang dihasilkan Compile
+func run(t *testing.T, prog []Instruction, data seccompData) uint32 {
+	t.Helper()
+	var acc uint32
+	for pc := 0; pc < len(prog); pc++ {
+		ins := prog[pc]
+		switch ins.Code {
+		case bpfLdAbs:
+			acc = data.word(t, ins.K)
+		case bpfAndK:
+			acc &= ins.K
+		case bpfJa:
+			pc += int(ins.K)
+		case bpfJeq, bpfJgt, bpfJge, bpfJset:
+			var match bool
+			switch ins.Code {
+			case bpfJeq:
+				match = acc == ins.K
+			case bpfJgt:
+				match = acc > ins.K
+			case bpfJge:
+				match = acc >= ins.K
+			case bpfJset:
+				match = acc&ins.K != 0
+			}
+			if match {
+				pc += int(ins.Jt)
+			} else {
+				pc += int(ins.Jf)
+			}
+		case bpfRet:
+			return ins.K
+		default:
+			t.Fatalf("instruksi %d: opcode 0x%02x tidak dikenal", pc, ins.Code)
+		}
+	}
+	t.Fatalf("program selesai tanpa ret")
+	return 0
+}
+
+func compile(t *testing.T, p *Profile, arch string) []Instruction {
+	t.Helper()
+	prog, err := Compile(p, arch)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	return prog
+}
+
+func errnoPtr(n uint) *uint {
+	return &n
+}
+
+const (
+	auditX86_64  = 0xc000003e
+	auditX86     = 0x40000003
+	auditAArch64 = 0xc00000b7
+)
+
+func nr(t *testing.T, arch, name string) uint32 {
+	t.Helper()
+	n, ok := arches[arch].table[name]
+	if !ok {
+		t.Fatalf("syscall %s tidak ada di %s", name, arch)
+	}
+	return n
+}
+
+func TestCompileActions(t *testing.T) {
+	p := &Profile{
+		DefaultAction:   ActErrno,
+		DefaultErrnoRet: errnoPtr(38),
+		Syscalls: []Syscall{
+			{Names: []string{"read", "write"}, Action: ActAllow},
+			{Name: "ptrace", Action: ActKillProcess},
+			{Names: []string{"reboot"}, Action: ActTrap},
+			{Names: []string{"mount"}, Action: ActErrno, ErrnoRet: errnoPtr(13)},
+			{Names: []string{"umount2"}, Action: ActErrno},
+			{Names: []string{"kexec_load"}, Action: ActKill},
+			{Names: []string{"chroot"}, Action: ActLog},
+			{Names: []string{"bpf"}, Action: ActTrace, ErrnoRet: errnoPtr(7)},
+			// Aturan dengan aksi default tidak menghasilkan instruksi
+			{Names: []string{"getpid"}, Action: ActErrno, ErrnoRet: errnoPtr(38)},
+			// Nama yang tidak ada di tabel diabaikan
+			{Names: []string{"tidak_ada"}, Action: ActAllow},
+		},
+	}
+
+	for _, arch := range []string{"SCMP_ARCH_X86_64", "SCMP_ARCH_AARCH64"} {
+		t.Run(arch, func(t *testing.T) {
+			prog := compile(t, p, arch)
+			audit := arches[arch].audit
+			tests := []struct {
+				syscall string
+				want    uint32
+			}{
+				{"read", retAllow},
+				{"write", retAllow},
+				{"ptrace", retKillProcess},
+				{"reboot", retTrap},
+				{"mount", retErrno | 13},
+				{"umount2", retErrno | eperm},
+				{"kexec_load", retKillThread},
+				{"chroot", retLog},
+				{"bpf", retTrace | 7},
+				{"getpid", retErrno | 38},
+				{"close", retErrno | 38},
+			}
+			for _, tt := range tests {
+				got := run(t, prog, seccompData{nr: nr(t, arch, tt.syscall), arch: audit})
+				if got != tt.want {
+					t.Errorf("%s = %s (0x%x), ingin %s (0x%x)", tt.syscall, retName(got), got, retName(tt.want), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileArchDispatch(t *testing.T) {
+	read := nr(t, "SCMP_ARCH_X86_64", "read")
+	tests := []struct {
+		name    string
+		profile Profile
+		data    seccompData
+		want    uint32
+	}{
+		{
+			name:    "arsitektur native",
+			profile: Profile{DefaultAction: ActAllow},
+			data:    seccompData{nr: read, arch: auditX86_64},
+			want:    retAllow,
+		},
+		{
+			name:    "arsitektur yang tidak ada di profil dibunuh",
+			profile: Profile{DefaultAction: ActAllow},
+			data:    seccompData{nr: read, arch: auditAArch64},
+			want:    retKillThread,
+		},
+		{
+			name:    "syscall x32 tanpa SCMP_ARCH_X32 dibunuh",
+			profile: Profile{DefaultAction: ActAllow},
+			data:    seccompData{nr: read | x32SyscallBit, arch: auditX86_64},
+			want:    retKillThread,
+		},
+		{
+			name: "syscall x32 dengan SCMP_ARCH_X32 ditolak EPERM",
+			profile: Profile{
+				DefaultAction: ActAllow,
+				ArchMap: []ArchMap{{
+					Arch:      "SCMP_ARCH_X86_64",
+					SubArches: []string{"SCMP_ARCH_X86", "SCMP_ARCH_X32"},
+				}},
+			},
+			data: seccompData{nr: read | x32SyscallBit, arch: auditX86_64},
+			want: retErrno | eperm,
+		},
+		{
+			name: "syscall x32 memakai aksi default yang menolak",
+			profile: Profile{
+				DefaultAction:   ActErrno,
+				DefaultErrnoRet: errnoPtr(38),
+				Architectures:   []string{"SCMP_ARCH_X32"},
+			},
+			data: seccompData{nr: read | x32SyscallBit, arch: auditX86_64},
+			want: retErrno | 38,
+		},
+		{
+			name: "sub-arsitektur tanpa tabel ditolak seluruhnya",
+			profile: Profile{
+				DefaultAction: ActAllow,
+				ArchMap: []ArchMap{{
+					Arch:      "SCMP_ARCH_X86_64",
+					SubArches: []string{"SCMP_ARCH_X86"},
+				}},
+				Syscalls: []Syscall{{Names: []string{"read"}, Action: ActAllow}},
+			},
+			data: seccompData{nr: 3, arch: auditX86},
+			want: retErrno | eperm,
+		},
+		{
+			name: "archMap arsitektur lain diabaikan",
+			profile: Profile{
+				DefaultAction: ActAllow,
+				ArchMap: []ArchMap{{
+					Arch:      "SCMP_ARCH_AARCH64",
+					SubArches: []string{"SCMP_ARCH_X86"},
+				}},
+			},
+			data: seccompData{nr: 3, arch: auditX86},
+			want: retKillThread,
+		},
+		{
+			name:    "arsitektur lain dengan tabel syscall sendiri",
+			profile: Profile{DefaultAction: ActErrno, Architectures: []string{"SCMP_ARCH_AARCH64"}, Syscalls: []Syscall{{Names: []string{"read"}, Action: ActAllow}}},
+			data:    seccompData{nr: nr(t, "SCMP_ARCH_AARCH64", "read"), arch: auditAArch64},
+			want:    retAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prog := compile(t, &tt.profile, "SCMP_ARCH_X86_64")
+			if got := run(t, prog, tt.data); got != tt.want {
+				t.Errorf("hasil = %s (0x%x), ingin %s (0x%x)", retName(got), got, retName(tt.want), tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileIncludesExcludes(t *testing.T) {
+	p := &Profile{
+		DefaultAction: ActErrno,
+		Syscalls: []Syscall{
+			{Names: []string{"read"}, Action: ActAllow, Includes: &Filter{Arches: []string{"amd64"}}},
+			{Names: []string{"write"}, Action: ActAllow, Includes: &Filter{Arches: []string{"SCMP_ARCH_AARCH64"}}},
+			{Names: []string{"close"}, Action: ActAllow, Excludes: &Filter{Arches: []string{"SCMP_ARCH_X86_64"}}},
+			// Aturan yang bergantung pada capability tidak dipakai
+			{Names: []string{"reboot"}, Action: ActAllow, Includes: &Filter{Caps: []string{"CAP_SYS_BOOT"}}},
+		},
+	}
+	prog := compile(t, p, "SCMP_ARCH_X86_64")
+
+	for syscall, want := range map[string]uint32{
+		"read":   retAllow,
+		"write":  retErrno | eperm,
+		"close":  retErrno | eperm,
+		"reboot": retErrno | eperm,
+	} {
+		got := run(t, prog, seccompData{nr: nr(t, "SCMP_ARCH_X86_64", syscall), arch: auditX86_64})
+		if got != want {
+			t.Errorf("%s = %s, ingin %s", syscall, retName(got), retName(want))
+		}
+	}
+}
+
+func TestCompileArgs(t *testing.T) {
+	const big = 0x0000000100000002 // hi = 1, lo = 2
+	tests := []struct {
+		name string
+		arg  Arg
+		// match dan miss adalah nilai argumen yang harus cocok dan tidak cocok
+		match []uint64
+		miss  []uint64
+	}{
+		{
+			name:  "EQ 64-bit",
+			arg:   Arg{Index: 1, Op: OpEqualTo, Value: big},
+			match: []uint64{big},
+			miss:  []uint64{2, 0x0000000200000002, 0x0000000100000003},
+		},
+		{
+			name:  "NE 64-bit",
+			arg:   Arg{Index: 0, Op: OpNotEqual, Value: big},
+			match: []uint64{2, 0x0000000200000002, 0x0000000100000003, 0},
+			miss:  []uint64{big},
+		},
+		{
+			name:  "GT",
+			arg:   Arg{Index: 2, Op: OpGreaterThan, Value: big},
+			match: []uint64{big + 1, 0x0000000200000000, ^uint64(0)},
+			miss:  []uint64{big, big - 1, 0x00000000ffffffff, 0},
+		},
+		{
+			name:  "GE",
+			arg:   Arg{Index: 3, Op: OpGreaterEqual, Value: big},
+			match: []uint64{big, big + 1, 0x0000000200000000},
+			miss:  []uint64{big - 1, 0x00000000ffffffff},
+		},
+		{
+			name:  "LT",
+			arg:   Arg{Index: 4, Op: OpLessThan, Value: big},
+			match: []uint64{big - 1, 0x00000000ffffffff, 0},
+			miss:  []uint64{big, big + 1, 0x0000000200000000},
+		},
+		{
+			name:  "LE",
+			arg:   Arg{Index: 5, Op: OpLessEqual, Value: big},
+			match: []uint64{big, big - 1, 0x00000000ffffffff},
+			miss:  []uint64{big + 1, 0x0000000200000001},
+		},
+		{
+			// (arg & 0x0000ff00000000ff) == 0x0000120000000034
+			name:  "MASKED_EQ",
+			arg:   Arg{Index: 0, Op: OpMaskedEqual, Value: 0x0000ff00000000ff, ValueTwo: 0x0000120000000034},
+			match: []uint64{0x0000120000000034, 0xffff12ffffffff34},
+			miss:  []uint64{0x0000130000000034, 0x0000120000000035, 0x34},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Profile{
+				DefaultAction: ActErrno,
+				Syscalls: []Syscall{
+					{Names: []string{"personality"}, Action: ActAllow, Args: []Arg{tt.arg}},
+				},
+			}
+			prog := compile(t, p, "SCMP_ARCH_X86_64")
+			check := func(value uint64, want uint32) {
+				data := seccompData{nr: nr(t, "SCMP_ARCH_X86_64", "personality"), arch: auditX86_64}
+				data.args[tt.arg.Index] = value
+				if got := run(t, prog, data); got != want {
+					t.Errorf("args[%d] = 0x%x: hasil %s, ingin %s", tt.arg.Index, value, retName(got), retName(want))
+				}
+			}
+			for _, value := range tt.match {
+				check(value, retAllow)
+			}
+			for _, value := range tt.miss {
+				check(value, retErrno|eperm)
+			}
+		})
+	}
+}
+
+func TestCompileMultipleArgRules(t *testing.T) {
+	// Semua argumen satu aturan harus cocok; jika tidak, aturan berikutnya
+	// untuk syscall yang sama dan syscall lain tetap diperiksa
+	p := &Profile{
+		DefaultAction: ActErrno,
+		Syscalls: []Syscall{
+			{Names: []string{"socket"}, Action: ActAllow, Args: []Arg{
+				{Index: 0, Op: OpEqualTo, Value: 1},
+				{Index: 1, Op: OpEqualTo, Value: 2},
+			}},
+			{Names: []string{"socket"}, Action: ActErrno, ErrnoRet: errnoPtr(97), Args: []Arg{
+				{Index: 0, Op: OpEqualTo, Value: 17},
+			}},
+			{Names: []string{"read"}, Action: ActAllow},
+		},
+	}
+	prog := compile(t, p, "SCMP_ARCH_X86_64")
+	socket := nr(t, "SCMP_ARCH_X86_64", "socket")
+
+	tests := []struct {
+		data seccompData
+		want uint32
+	}{
+		{seccompData{nr: socket, args: [6]uint64{1, 2}}, retAllow},
+		{seccompData{nr: socket, args: [6]uint64{1, 3}}, retErrno | eperm},
+		{seccompData{nr: socket, args: [6]uint64{2, 2}}, retErrno | eperm},
+		{seccompData{nr: socket, args: [6]uint64{17, 2}}, retErrno | 97},
+		{seccompData{nr: nr(t, "SCMP_ARCH_X86_64", "read"), args: [6]uint64{1, 3}}, retAllow},
+	}
+	for _, tt := range tests {
+		tt.data.arch = auditX86_64
+		if got := run(t, prog, tt.data); got != tt.want {
+			t.Errorf("%+v: hasil %s, ingin %s", tt.data, retName(got), retName(tt.want))
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		arch    string
+		want    string
+	}{
+		{"arsitektur tanpa tabel", Profile{DefaultAction: ActAllow}, "SCMP_ARCH_X86", "tidak didukung"},
+		{"arsitektur tidak dikenal", Profile{DefaultAction: ActAllow}, "SCMP_ARCH_MIPS", "tidak didukung"},
+		{"archMap tidak dikenal", Profile{DefaultAction: ActAllow, ArchMap: []ArchMap{{Arch: "SCMP_ARCH_X86_64", SubArches: []string{"SCMP_ARCH_MIPS"}}}}, "SCMP_ARCH_X86_64", "tidak dikenal"},
+		{"profil tidak valid", Profile{}, "SCMP_ARCH_X86_64", "defaultAction"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(&tt.profile, tt.arch)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Compile error = %v, ingin mengandung %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileTooManyInstructions(t *testing.T) {
+	// Setiap aturan dengan dua argumen EQ menghasilkan 11 instruksi
+	var rules []Syscall
+	for i := 0; i < 400; i++ {
+		rules = append(rules, Syscall{Names: []string{"ioctl"}, Action: ActAllow, Args: []Arg{
+			{Index: 0, Op: OpEqualTo, Value: uint64(i)},
+			{Index: 1, Op: OpEqualTo, Value: 1},
+		}})
+	}
+	_, err := Compile(&Profile{DefaultAction: ActErrno, Syscalls: rules}, "SCMP_ARCH_X86_64")
+	if err == nil || !strings.Contains(err.Error(), "terlalu besar") {
+		t.Errorf("Compile error = %v, ingin program terlalu besar", err)
+	}
+}
+
+func TestAssemblerJumpRange(t *testing.T) {
+	// Jump kondisional hanya menjangkau 255 instruksi
+	a := newAssembler()
+	far := a.newLabel()
+	a.jump(bpfJeq, 1, next, far)
+	for i := 0; i < 256; i++ {
+		a.ret(retAllow)
+	}
+	a.mark(far)
+	a.ret(retKillThread)
+	if _, err := a.assemble(); err == nil || !strings.Contains(err.Error(), "terlalu jauh") {
+		t.Errorf("assemble error = %v, ingin jump terlalu jauh", err)
+	}
+
+	// Tepat 255 instruksi masih bisa dijangkau
+	a = newAssembler()
+	far = a.newLabel()
+	a.jump(bpfJeq, 1, far, next)
+	for i := 0; i < 255; i++ {
+		a.ret(retAllow)
+	}
+	a.mark(far)
+	a.ret(retKillThread)
+	prog, err := a.assemble()
+	if err != nil {
+		t.Fatalf("assemble: %v", err)
+	}
+	if prog[0].Jt != 255 || prog[0].Jf != 0 {
+		t.Errorf("jt/jf = %d/%d, ingin 255/0", prog[0].Jt, prog[0].Jf)
+	}
+
+	// ja tidak dibatasi 255
+	a = newAssembler()
+	far = a.newLabel()
+	a.ja(far)
+	for i := 0; i < 1000; i++ {
+		a.ret(retAllow)
+	}
+	a.mark(far)
+	a.ret(retKillThread)
+	prog, err = a.assemble()
+	if err != nil {
+		t.Fatalf("assemble ja: %v", err)
+	}
+	if prog[0].K != 1000 {
+		t.Errorf("ja k = %d, ingin 1000", prog[0].K)
+	}
+
+	// Jump mundur tidak didukung BPF classic
+	a = newAssembler()
+	back := a.newLabel()
+	a.mark(back)
+	a.ret(retAllow)
+	a.jump(bpfJeq, 1, back, next)
+	if _, err := a.assemble(); err == nil {
+		t.Error("assemble jump mundur berhasil, ingin error")
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	p := &Profile{
+		DefaultAction: ActErrno,
+		Syscalls: []Syscall{
+			{Names: []string{"read"}, Action: ActAllow},
+			{Names: []string{"personality"}, Action: ActAllow, Args: []Arg{{Index: 0, Op: OpEqualTo, Value: 8}}},
+		},
+	}
+	got := Disassemble(compile(t, p, "SCMP_ARCH_X86_64"))
+	want := `0000  ld   [4]                       ; arch
+0001  jeq  #0xc000003e, 2, 3         ; SCMP_ARCH_X86_64
+0002  ja   4
+0003  ret  #0x00000000               ; KILL_THREAD
+; SCMP_ARCH_X86_64
+0004  ld   [0]                       ; nr
+0005  jge  #0x40000000, 6, 7
+0006  ret  #0x00000000               ; KILL_THREAD
+0007  jeq  #0x0, 8, 9                ; read
+0008  ret  #0x7fff0000               ; ALLOW
+0009  jeq  #0x87, 10, 15             ; personality
+0010  ld   [20]                      ; args[0] hi
+0011  jeq  #0x0, 12, 15
+0012  ld   [16]                      ; args[0] lo
+0013  jeq  #0x8, 14, 15
+0014  ret  #0x7fff0000               ; ALLOW
+0015  ld   [0]                       ; nr
+0016  ret  #0x00050001               ; ERRNO(1)
+`
+	if got != want {
+		t.Errorf("Disassemble =\n%s\ningin\n%s", got, want)
+	}
+}
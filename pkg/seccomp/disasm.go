@@ -0,0 +1,133 @@
+package seccomp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Disassemble menulis program BPF sebagai teks, satu instruksi per baris,
+// dengan target jump absolut. Nomor syscall dan nilai return diberi
+// keterangan jika program disusun oleh Compile.
+func Disassemble(prog []Instruction) string {
+	blocks := archBlocks(prog)
+	var table map[uint32]string
+	loaded := -1
+
+	var b strings.Builder
+	for i, ins := range prog {
+		if name, ok := blocks[i]; ok {
+			table = reverseTable(arches[name].table)
+			fmt.Fprintf(&b, "; %s\n", name)
+		}
+
+		var text, note string
+		switch ins.Code {
+		case bpfLdAbs:
+			loaded = int(ins.K)
+			text = fmt.Sprintf("ld   [%d]", ins.K)
+			note = fieldName(ins.K)
+		case bpfAndK:
+			loaded = -1
+			text = fmt.Sprintf("and  #0x%x", ins.K)
+		case bpfJa:
+			text = fmt.Sprintf("ja   %d", i+1+int(ins.K))
+		case bpfJeq, bpfJgt, bpfJge, bpfJset:
+			op := map[uint16]string{bpfJeq: "jeq", bpfJgt: "jgt", bpfJge: "jge", bpfJset: "jset"}[ins.Code]
+			text = fmt.Sprintf("%-4s #0x%x, %d, %d", op, ins.K, i+1+int(ins.Jt), i+1+int(ins.Jf))
+			switch {
+			case loaded == offsetNr && ins.Code == bpfJeq:
+				note = table[ins.K]
+			case loaded == offsetArch && ins.Code == bpfJeq:
+				note = archName(ins.K)
+			}
+		case bpfRet:
+			text = fmt.Sprintf("ret  #0x%08x", ins.K)
+			note = retName(ins.K)
+		default:
+			text = fmt.Sprintf("code=0x%02x jt=%d jf=%d k=0x%x", ins.Code, ins.Jt, ins.Jf, ins.K)
+		}
+
+		if note != "" {
+			fmt.Fprintf(&b, "%04d  %-30s ; %s\n", i, text, note)
+		} else {
+			fmt.Fprintf(&b, "%04d  %s\n", i, text)
+		}
+	}
+	return b.String()
+}
+
+// archBlocks mengenali dispatch arsitektur di awal program Compile
+// (jeq AUDIT_ARCH lalu ja) dan mengembalikan posisi awal blok tiap arsitektur
+func archBlocks(prog []Instruction) map[int]string {
+	blocks := map[int]string{}
+	if len(prog) == 0 || prog[0] != (Instruction{Code: bpfLdAbs, K: offsetArch}) {
+		return blocks
+	}
+	for i := 1; i+1 < len(prog); i += 2 {
+		if prog[i].Code != bpfJeq || prog[i+1].Code != bpfJa {
+			break
+		}
+		if name := archName(prog[i].K); name != "" {
+			blocks[i+2+int(prog[i+1].K)] = name
+		}
+	}
+	return blocks
+}
+
+// archName mencari arsitektur dengan tabel syscall berdasarkan audit arch
+func archName(audit uint32) string {
+	for _, name := range []string{"SCMP_ARCH_X86_64", "SCMP_ARCH_AARCH64", "SCMP_ARCH_RISCV64", "SCMP_ARCH_X86", "SCMP_ARCH_ARM", "SCMP_ARCH_PPC64LE", "SCMP_ARCH_S390X"} {
+		if arches[name].audit == audit {
+			return name
+		}
+	}
+	return ""
+}
+
+func reverseTable(table map[string]uint32) map[uint32]string {
+	reverse := make(map[uint32]string, len(table))
+	for name, nr := range table {
+		reverse[nr] = name
+	}
+	return reverse
+}
+
+// fieldName menjelaskan offset struct seccomp_data
+func fieldName(offset uint32) string {
+	switch {
+	case offset == offsetNr:
+		return "nr"
+	case offset == offsetArch:
+		return "arch"
+	case offset >= offsetArgs && offset < offsetArgs+48:
+		half := "lo"
+		if (offset-offsetArgs)%8 == 4 {
+			half = "hi"
+		}
+		return fmt.Sprintf("args[%d] %s", (offset-offsetArgs)/8, half)
+	}
+	return ""
+}
+
+// retName menjelaskan nilai return filter
+func retName(value uint32) string {
+	if value == retKillProcess {
+		return "KILL_PROCESS"
+	}
+	data := value & retData
+	switch value &^ retData {
+	case retKillThread:
+		return "KILL_THREAD"
+	case retTrap:
+		return "TRAP"
+	case retErrno:
+		return fmt.Sprintf("ERRNO(%d)", data)
+	case retTrace:
+		return fmt.Sprintf("TRACE(%d)", data)
+	case retLog:
+		return "LOG"
+	case retAllow:
+		return "ALLOW"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package seccomp
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	prSetSeccomp          = 22 // PR_SET_SECCOMP
+	seccompModeFilter     = 2  // SECCOMP_MODE_FILTER
+	seccompSetModeFilter  = 1  // SECCOMP_SET_MODE_FILTER
+	seccompFilterFlagSync = 1  // SECCOMP_FILTER_FLAG_TSYNC
+)
+
+// Install memasang program ke proses ini. Filter berlaku untuk semua thread
+// (SECCOMP_FILTER_FLAG_TSYNC) dan diwariskan ke exec. Tanpa no_new_privs,
+// pemanggil harus punya CAP_SYS_ADMIN. Goroutine pemanggil dikunci ke
+// thread-nya agar exec berikutnya tetap terfilter jika kernel hanya
+// mendukung prctl(PR_SET_SECCOMP).
+func Install(prog []Instruction) error {
+	if len(prog) == 0 {
+		return fmt.Errorf("program seccomp kosong")
+	}
+	runtime.LockOSThread()
+
+	filter := make([]syscall.SockFilter, len(prog))
+	for i, ins := range prog {
+		filter[i] = syscall.SockFilter{Code: ins.Code, Jt: ins.Jt, Jf: ins.Jf, K: ins.K}
+	}
+	fprog := syscall.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+
+	if nr, ok := NativeSyscall("seccomp"); ok {
+		r1, _, errno := syscall.RawSyscall(uintptr(nr), seccompSetModeFilter, seccompFilterFlagSync, uintptr(unsafe.Pointer(&fprog)))
+		switch {
+		case errno == 0 && r1 != 0:
+			return fmt.Errorf("seccomp: thread %d tidak bisa disinkronkan", r1)
+		case errno == 0:
+			return nil
+		case errno != syscall.ENOSYS:
+			return fmt.Errorf("seccomp: %v", errno)
+		}
+	}
+
+	_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetSeccomp, seccompModeFilter, uintptr(unsafe.Pointer(&fprog)))
+	if errno != 0 {
+		return fmt.Errorf("prctl(PR_SET_SECCOMP): %v", errno)
+	}
+	return nil
+}
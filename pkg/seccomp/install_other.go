@@ -0,0 +1,10 @@
+//go:build !linux
+
+package seccomp
+
+import "fmt"
+
+// Install hanya didukung di Linux
+func Install(prog []Instruction) error {
+	return fmt.Errorf("seccomp hanya didukung di Linux")
+}
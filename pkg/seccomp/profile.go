@@ -0,0 +1,210 @@
+// Package seccomp mengompilasi profil seccomp berformat JSON Docker menjadi
+// program BPF classic dan memasangnya ke proses. Kompilasi tidak memakai
+// libseccomp: nomor syscall diambil dari tabel di syscalls.go dan program
+// disusun sendiri oleh Compile.
+package seccomp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Action adalah aksi seccomp, misalnya SCMP_ACT_ALLOW atau SCMP_ACT_ERRNO
+type Action string
+
+// Aksi yang didukung
+const (
+	ActAllow       Action = "SCMP_ACT_ALLOW"
+	ActErrno       Action = "SCMP_ACT_ERRNO"
+	ActKill        Action = "SCMP_ACT_KILL"
+	ActKillThread  Action = "SCMP_ACT_KILL_THREAD"
+	ActKillProcess Action = "SCMP_ACT_KILL_PROCESS"
+	ActTrap        Action = "SCMP_ACT_TRAP"
+	ActTrace       Action = "SCMP_ACT_TRACE"
+	ActLog         Action = "SCMP_ACT_LOG"
+)
+
+// Operator membandingkan argumen syscall dengan nilai di aturan
+type Operator string
+
+// Operator yang didukung
+const (
+	OpEqualTo      Operator = "SCMP_CMP_EQ"
+	OpNotEqual     Operator = "SCMP_CMP_NE"
+	OpLessThan     Operator = "SCMP_CMP_LT"
+	OpLessEqual    Operator = "SCMP_CMP_LE"
+	OpGreaterThan  Operator = "SCMP_CMP_GT"
+	OpGreaterEqual Operator = "SCMP_CMP_GE"
+	OpMaskedEqual  Operator = "SCMP_CMP_MASKED_EQ"
+)
+
+// Profile adalah profil seccomp dengan format yang sama seperti Docker
+type Profile struct {
+	DefaultAction Action `json:"defaultAction"`
+	// DefaultErrnoRet adalah errno untuk DefaultAction SCMP_ACT_ERRNO
+	// (default EPERM)
+	DefaultErrnoRet *uint `json:"defaultErrnoRet,omitempty"`
+	// Architectures adalah arsitektur yang boleh memanggil syscall. Arsitektur
+	// native selalu disertakan.
+	Architectures []string `json:"architectures,omitempty"`
+	// ArchMap menambahkan sub-arsitektur untuk arsitektur native, misalnya
+	// SCMP_ARCH_X86 dan SCMP_ARCH_X32 untuk SCMP_ARCH_X86_64
+	ArchMap  []ArchMap `json:"archMap,omitempty"`
+	Syscalls []Syscall `json:"syscalls"`
+}
+
+// ArchMap memetakan arsitektur ke sub-arsitekturnya
+type ArchMap struct {
+	Arch      string   `json:"architecture"`
+	SubArches []string `json:"subArchitectures"`
+}
+
+// Syscall adalah satu aturan profil. Name adalah format lama yang setara
+// dengan Names berisi satu nama.
+type Syscall struct {
+	Names    []string `json:"names,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Action   Action   `json:"action"`
+	Args     []Arg    `json:"args,omitempty"`
+	ErrnoRet *uint    `json:"errnoRet,omitempty"`
+	Comment  string   `json:"comment,omitempty"`
+	// Includes dan Excludes membatasi aturan ke arsitektur tertentu. Aturan
+	// dengan Includes.Caps tidak dipakai karena capability container belum
+	// diketahui saat kompilasi.
+	Includes *Filter `json:"includes,omitempty"`
+	Excludes *Filter `json:"excludes,omitempty"`
+}
+
+// Filter memilih arsitektur atau capability tempat aturan berlaku
+type Filter struct {
+	Arches []string `json:"arches,omitempty"`
+	Caps   []string `json:"caps,omitempty"`
+}
+
+// Arg membandingkan argumen ke-Index (0-5) dengan Value. ValueTwo hanya
+// dipakai SCMP_CMP_MASKED_EQ: (arg & Value) == ValueTwo.
+type Arg struct {
+	Index    uint     `json:"index"`
+	Value    uint64   `json:"value"`
+	ValueTwo uint64   `json:"valueTwo,omitempty"`
+	Op       Operator `json:"op"`
+}
+
+// LoadProfile membaca dan memvalidasi profil dari file JSON
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca profil seccomp: %v", err)
+	}
+	profile, err := ParseProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("profil seccomp %s: %v", path, err)
+	}
+	return profile, nil
+}
+
+// ParseProfile mem-parse dan memvalidasi profil JSON
+func ParseProfile(data []byte) (*Profile, error) {
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("JSON tidak valid: %v", err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Validate memeriksa aksi, arsitektur, dan argumen semua aturan. Nama
+// syscall yang tidak dikenal bukan error; lihat UnknownSyscalls.
+func (p *Profile) Validate() error {
+	if p.DefaultAction == "" {
+		return fmt.Errorf("defaultAction wajib diisi")
+	}
+	if err := validateAction(p.DefaultAction, p.DefaultErrnoRet); err != nil {
+		return fmt.Errorf("defaultAction: %v", err)
+	}
+	for _, arch := range p.Architectures {
+		if _, ok := arches[arch]; !ok {
+			return fmt.Errorf("arsitektur %q tidak dikenal", arch)
+		}
+	}
+
+	for i, rule := range p.Syscalls {
+		names := rule.names()
+		if len(names) == 0 {
+			return fmt.Errorf("syscalls[%d]: names wajib diisi", i)
+		}
+		for _, name := range names {
+			if name == "" || strings.ContainsAny(name, " \t\n") {
+				return fmt.Errorf("syscalls[%d]: nama syscall %q tidak valid", i, name)
+			}
+		}
+		if err := validateAction(rule.Action, rule.ErrnoRet); err != nil {
+			return fmt.Errorf("syscalls[%d] (%s): %v", i, names[0], err)
+		}
+		if len(rule.Args) > 6 {
+			return fmt.Errorf("syscalls[%d] (%s): maksimal 6 argumen", i, names[0])
+		}
+		for _, arg := range rule.Args {
+			if arg.Index > 5 {
+				return fmt.Errorf("syscalls[%d] (%s): index argumen %d di luar 0-5", i, names[0], arg.Index)
+			}
+			if !validOperator(arg.Op) {
+				return fmt.Errorf("syscalls[%d] (%s): operator %q tidak dikenal", i, names[0], arg.Op)
+			}
+		}
+	}
+	return nil
+}
+
+// UnknownSyscalls mengembalikan nama syscall di profil yang tidak ada di
+// tabel arsitektur mana pun, biasanya karena salah ketik
+func (p *Profile) UnknownSyscalls() []string {
+	var unknown []string
+	seen := map[string]bool{}
+	for _, rule := range p.Syscalls {
+		for _, name := range rule.names() {
+			if !seen[name] && !KnownSyscall(name) {
+				unknown = append(unknown, name)
+			}
+			seen[name] = true
+		}
+	}
+	return unknown
+}
+
+// names mengembalikan nama syscall aturan, termasuk format lama Name
+func (s Syscall) names() []string {
+	if s.Name != "" {
+		return append([]string{s.Name}, s.Names...)
+	}
+	return s.Names
+}
+
+// validateAction memeriksa aksi dan errnoRet-nya
+func validateAction(action Action, errnoRet *uint) error {
+	switch action {
+	case ActAllow, ActKill, ActKillThread, ActKillProcess, ActTrap, ActLog:
+		if errnoRet != nil {
+			return fmt.Errorf("errnoRet hanya berlaku untuk %s", ActErrno)
+		}
+	case ActErrno, ActTrace:
+		if errnoRet != nil && *errnoRet > 0xffff {
+			return fmt.Errorf("errnoRet %d di luar 0-65535", *errnoRet)
+		}
+	default:
+		return fmt.Errorf("aksi %q tidak didukung", action)
+	}
+	return nil
+}
+
+func validOperator(op Operator) bool {
+	switch op {
+	case OpEqualTo, OpNotEqual, OpLessThan, OpLessEqual, OpGreaterThan, OpGreaterEqual, OpMaskedEqual:
+		return true
+	}
+	return false
+}
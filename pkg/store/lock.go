@@ -0,0 +1,33 @@
+package store
+
+import (
+	"fmt"
+	"os"
+)
+
+// FileLock adalah lock eksklusif untuk satu objek. Lock disimpan di file
+// terpisah (<path>.lock) karena file metadata diganti dengan rename pada
+// setiap penulisan.
+type FileLock struct {
+	file *os.File
+}
+
+// Lock mengambil lock eksklusif untuk objek di path dan menunggu jika lock
+// sedang dipegang proses lain
+func Lock(path string) (*FileLock, error) {
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka lock %s: %v", path, err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("gagal mengunci %s: %v", path, err)
+	}
+	return &FileLock{file: file}, nil
+}
+
+// Unlock melepas lock
+func (l *FileLock) Unlock() error {
+	unlockFile(l.file)
+	return l.file.Close()
+}
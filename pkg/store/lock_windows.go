@@ -0,0 +1,14 @@
+//go:build windows
+
+package store
+
+import "os"
+
+// lockFile tidak melakukan apa-apa di Windows karena container hanya
+// disimulasikan di sana
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile tidak melakukan apa-apa di Windows
+func unlockFile(file *os.File) {}
@@ -0,0 +1,154 @@
+// Package store menyimpan metadata minidocker (container, volume, image)
+// sebagai file JSON. Penulisan bersifat atomik (file sementara lalu rename),
+// perubahan read-modify-write dilindungi file lock per objek, dan setiap
+// file mencatat versi skemanya sehingga format lama bisa dimigrasi.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// versionKey adalah field JSON tempat versi skema disimpan
+const versionKey = "schema_version"
+
+// Migration mengubah dokumen JSON dari satu versi skema ke versi berikutnya
+type Migration func(doc map[string]interface{}) error
+
+// Schema menjelaskan format satu jenis objek
+type Schema struct {
+	// Name dipakai di pesan error, misalnya "container"
+	Name string
+	// Version adalah versi skema yang ditulis oleh kode saat ini
+	Version int
+	// Migrations[n] mengubah dokumen versi n menjadi versi n+1. File tanpa
+	// schema_version dianggap versi 0.
+	Migrations map[int]Migration
+}
+
+// Read membaca file JSON ke v, menjalankan migrasi jika versinya lama.
+// Hasil migrasi baru ditulis ke disk pada Write atau Update berikutnya.
+func (s Schema) Read(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("gagal parse metadata %s %s: %v", s.Name, path, err)
+	}
+
+	version := 0
+	if raw, ok := doc[versionKey].(float64); ok {
+		version = int(raw)
+	}
+	if version > s.Version {
+		return fmt.Errorf("metadata %s %s memakai skema versi %d, lebih baru dari yang didukung (%d)",
+			s.Name, path, version, s.Version)
+	}
+
+	if version < s.Version {
+		for ; version < s.Version; version++ {
+			migrate, ok := s.Migrations[version]
+			if !ok {
+				return fmt.Errorf("tidak ada migrasi %s dari skema versi %d", s.Name, version)
+			}
+			if err := migrate(doc); err != nil {
+				return fmt.Errorf("gagal migrasi %s %s dari versi %d: %v", s.Name, path, version, err)
+			}
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("gagal membaca metadata %s %s: %v", s.Name, path, err)
+	}
+	return nil
+}
+
+// Write menulis v ke path secara atomik dengan lock objek
+func (s Schema) Write(path string, v interface{}) error {
+	lock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return s.write(path, v)
+}
+
+// Update membaca path ke v, memanggil fn, lalu menulis v kembali. Selama
+// proses ini objek dikunci sehingga perubahan dari proses lain tidak hilang.
+// Jika fn mengembalikan error, file tidak diubah.
+func (s Schema) Update(path string, v interface{}, fn func() error) error {
+	lock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if err := s.Read(path, v); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return s.write(path, v)
+}
+
+// write menulis v dengan schema_version tanpa mengambil lock
+func (s Schema) write(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("gagal encode metadata %s: %v", s.Name, err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("metadata %s harus berupa objek JSON: %v", s.Name, err)
+	}
+	doc[versionKey] = s.Version
+
+	if data, err = json.MarshalIndent(doc, "", "  "); err != nil {
+		return fmt.Errorf("gagal encode metadata %s: %v", s.Name, err)
+	}
+	return WriteFileAtomic(path, data, 0644)
+}
+
+// WriteFileAtomic menulis data ke file sementara di direktori yang sama,
+// melakukan fsync, lalu me-rename-nya ke path. Pembaca selalu melihat isi
+// file lama atau baru secara utuh, tidak pernah file yang setengah ditulis.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("gagal membuat file sementara: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal menulis %s: %v", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal mengatur permission %s: %v", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal sync %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("gagal menulis %s: %v", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("gagal mengganti %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testDoc struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+var testSchema = Schema{
+	Name:    "test",
+	Version: 1,
+	Migrations: map[int]Migration{
+		// Versi 0 menyimpan count sebagai string di field "total"
+		0: func(doc map[string]interface{}) error {
+			if total, ok := doc["total"].(string); ok {
+				doc["count"] = len(total)
+				delete(doc, "total")
+			}
+			return nil
+		},
+	},
+}
+
+func writeRaw(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadMigratesVersion0(t *testing.T) {
+	path := writeRaw(t, `{"name": "lama", "total": "abc"}`)
+
+	var doc testDoc
+	if err := testSchema.Read(path, &doc); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if doc.Name != "lama" || doc.Count != 3 {
+		t.Errorf("hasil migrasi = %+v, ingin {Name:lama Count:3}", doc)
+	}
+
+	// Read tidak menulis ulang file; versi baru baru tercatat saat Write
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), versionKey) {
+		t.Errorf("Read menulis ulang file: %s", data)
+	}
+	if err := testSchema.Write(path, &doc); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `"schema_version": 1`) {
+		t.Errorf("file setelah Write tidak mencatat versi skema: %s", data)
+	}
+}
+
+func TestReadCurrentVersionSkipsMigration(t *testing.T) {
+	path := writeRaw(t, `{"schema_version": 1, "name": "baru", "count": 7, "total": "abc"}`)
+
+	var doc testDoc
+	if err := testSchema.Read(path, &doc); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if doc.Count != 7 {
+		t.Errorf("Count = %d, ingin 7 (migrasi tidak boleh dijalankan)", doc.Count)
+	}
+}
+
+func TestReadRejectsNewerVersion(t *testing.T) {
+	path := writeRaw(t, `{"schema_version": 2, "name": "masa depan"}`)
+
+	var doc testDoc
+	err := testSchema.Read(path, &doc)
+	if err == nil {
+		t.Fatal("Read berhasil untuk skema versi lebih baru, ingin error")
+	}
+	if !strings.Contains(err.Error(), "lebih baru") {
+		t.Errorf("pesan error = %q", err)
+	}
+}
+
+func TestReadMissingMigration(t *testing.T) {
+	schema := Schema{Name: "test", Version: 2, Migrations: testSchema.Migrations}
+	path := writeRaw(t, `{"name": "lama"}`)
+
+	var doc testDoc
+	if err := schema.Read(path, &doc); err == nil {
+		t.Fatal("Read berhasil tanpa migrasi dari versi 1, ingin error")
+	}
+}
+
+func TestUpdateErrorLeavesFileUnchanged(t *testing.T) {
+	path := writeRaw(t, `{"schema_version": 1, "name": "a", "count": 1}`)
+	before, _ := os.ReadFile(path)
+
+	var doc testDoc
+	err := testSchema.Update(path, &doc, func() error {
+		doc.Count = 100
+		return os.ErrInvalid
+	})
+	if err != os.ErrInvalid {
+		t.Fatalf("Update error = %v, ingin %v", err, os.ErrInvalid)
+	}
+	after, _ := os.ReadFile(path)
+	if string(before) != string(after) {
+		t.Errorf("file berubah setelah fn gagal: %s", after)
+	}
+}
+
+func TestConcurrentUpdatesKeepEveryWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.json")
+	if err := testSchema.Write(path, &testDoc{Name: "counter"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers, increments = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*increments)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				var doc testDoc
+				errs <- testSchema.Update(path, &doc, func() error {
+					doc.Count++
+					return nil
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	var doc testDoc
+	if err := testSchema.Read(path, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Count != workers*increments {
+		t.Errorf("Count = %d, ingin %d (ada penulisan yang hilang)", doc.Count, workers*increments)
+	}
+}
+
+func TestWriteFileAtomicReplacesWithoutLeftovers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	if err := os.WriteFile(path, []byte("lama"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("baru"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "baru" {
+		t.Fatalf("isi file = %q, %v, ingin \"baru\"", data, err)
+	}
+	info, _ := os.Stat(path)
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("permission = %o, ingin 644", perm)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("file sementara tertinggal: %v", names)
+	}
+}
+
+func TestWriteFileAtomicMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tidak-ada", "data.json")
+	if err := WriteFileAtomic(path, []byte("x"), 0644); err == nil {
+		t.Fatal("WriteFileAtomic berhasil di direktori yang tidak ada")
+	}
+}